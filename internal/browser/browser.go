@@ -0,0 +1,29 @@
+// Package browser opens URLs in the user's default web browser.
+package browser
+
+import "errors"
+
+// ErrUnsupported is returned when opening URLs is not available on this platform.
+var ErrUnsupported = errors.New("opening URLs is not supported on this platform")
+
+// Open opens url in the default browser. It returns once the opener has been
+// started, without waiting for the browser.
+func Open(url string) error {
+	cmd, err := command(url)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the opener when it exits
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// command is implemented in platform-specific files:
+// - browser_linux.go for Linux and the BSDs (xdg-open)
+// - browser_darwin.go for macOS
+// - browser_windows.go for Windows
+// - browser_other.go for all other platforms
+// func command(url string) (*exec.Cmd, error)
@@ -0,0 +1,10 @@
+//go:build darwin
+
+package browser
+
+import "os/exec"
+
+// command uses open, which hands the URL to the default browser.
+func command(url string) (*exec.Cmd, error) {
+	return exec.Command("open", url), nil
+}
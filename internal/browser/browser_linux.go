@@ -0,0 +1,10 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+package browser
+
+import "os/exec"
+
+// command uses xdg-open, which hands the URL to the desktop's default browser.
+func command(url string) (*exec.Cmd, error) {
+	return exec.Command("xdg-open", url), nil
+}
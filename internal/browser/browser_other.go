@@ -0,0 +1,10 @@
+//go:build !linux && !freebsd && !openbsd && !netbsd && !darwin && !windows
+
+package browser
+
+import "os/exec"
+
+// command is not implemented on this platform.
+func command(url string) (*exec.Cmd, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package browser
+
+import "os/exec"
+
+// command uses the URL protocol handler, which avoids the quoting rules of "cmd /c start".
+func command(url string) (*exec.Cmd, error) {
+	return exec.Command("rundll32", "url.dll,FileProtocolHandler", url), nil
+}
@@ -17,10 +17,52 @@ type Terminal struct {
 	Path string `json:"path"`
 }
 
+// Watch rule scopes select which containers a rule applies to.
+const (
+	WatchScopeAll       = ""          // All containers
+	WatchScopeContainer = "container" // Containers with the given name
+	WatchScopeProject   = "project"   // Containers of the given compose project
+	WatchScopeImage     = "image"     // Containers created from the given image
+)
+
+// Watch rule actions define what happens when a rule matches a log line.
+const (
+	WatchActionNotify    = "notify"    // Show a desktop notification
+	WatchActionHighlight = "highlight" // Highlight the container in Harbor
+	WatchActionCount     = "count"     // Only count matches
+)
+
+// WatchRule is a log pattern that is evaluated against the logs of running containers.
+type WatchRule struct {
+	Pattern string `json:"pattern"`
+	Scope   string `json:"scope,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Action  string `json:"action"`
+}
+
+// LogSettings controls how log windows process container output.
+type LogSettings struct {
+	// DisableMultiline turns off grouping of continuation lines (stack traces) into one entry.
+	DisableMultiline bool `json:"disable_multiline,omitempty"`
+	// MultilinePattern is an additional regular expression for continuation lines.
+	MultilinePattern string `json:"multiline_pattern,omitempty"`
+}
+
+// VolumeSettings controls how volumes are accessed.
+type VolumeSettings struct {
+	// HelperImage is the image of the containers used to read volumes and to
+	// check connectivity from containers that lack the tools.
+	// If empty, a local busybox or alpine image is used.
+	HelperImage string `json:"helper_image,omitempty"`
+}
+
 // Settings represents the application settings.
 type Settings struct {
-	Terminals        []Terminal `json:"terminals"`
-	SelectedTerminal string     `json:"selected_terminal"`
+	Terminals        []Terminal     `json:"terminals"`
+	SelectedTerminal string         `json:"selected_terminal"`
+	WatchRules       []WatchRule    `json:"watch_rules,omitempty"`
+	Logs             LogSettings    `json:"logs"`
+	Volumes          VolumeSettings `json:"volumes"`
 }
 
 // configDir returns the path to the config directory.
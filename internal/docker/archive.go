@@ -0,0 +1,130 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// archiveManifest is an entry of manifest.json in a docker-archive tarball.
+type archiveManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// layerFileFunc is called for every entry of every layer tarball in an image archive.
+// The layer is identified by its path inside the archive.
+type layerFileFunc func(layer string, hdr *tar.Header, content io.Reader) error
+
+// countingReader counts the bytes read and reports them to a callback.
+type countingReader struct {
+	r        io.Reader
+	n        int64
+	reported int64
+	fn       func(int64)
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	// Report at most every megabyte to keep the callback cheap
+	if c.fn != nil && (c.n-c.reported >= 1<<20 || err != nil) {
+		c.reported = c.n
+		c.fn(c.n)
+	}
+	return n, err
+}
+
+// walkImage exports an image with ImageSave and calls fn for every file of every layer.
+// It returns the layer paths in order from the lowest to the topmost layer.
+// progress, if not nil, is called with the number of archive bytes read.
+func (c *Client) walkImage(ctx context.Context, ref string, fn layerFileFunc, progress func(int64)) ([]string, error) {
+	c.mu.RLock()
+	rc, err := c.cli.ImageSave(ctx, []string{ref})
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return walkImageArchive(&countingReader{r: rc, fn: progress}, fn)
+}
+
+// walkImageArchive reads a docker-archive (or OCI layout) tarball sequentially.
+// Every file that is itself a tarball is treated as a layer and walked with fn;
+// manifest.json then defines which of them belong to the image and in which order.
+func walkImageArchive(r io.Reader, fn layerFileFunc) ([]string, error) {
+	var manifests []archiveManifest
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if hdr.Name == "manifest.json" {
+			if err := json.NewDecoder(tr).Decode(&manifests); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := walkLayer(hdr.Name, tr, fn); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(manifests) == 0 {
+		return nil, errors.New("image archive has no manifest.json")
+	}
+	return manifests[0].Layers, nil
+}
+
+// walkLayer walks a layer tarball, which may be gzip-compressed.
+// Files that are not tarballs (such as image configs) are skipped.
+func walkLayer(name string, r io.Reader, fn layerFileFunc) error {
+	br := bufio.NewReaderSize(r, 64*1024)
+	head, _ := br.Peek(512)
+
+	var layer io.Reader = br
+	switch {
+	case len(head) >= 2 && head[0] == 0x1f && head[1] == 0x8b:
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil
+		}
+		defer gz.Close()
+		layer = gz
+	case len(head) == 512 && bytes.Equal(head, make([]byte, 512)):
+		// An empty tarball only consists of zero blocks
+		return nil
+	case len(head) < 512 || !bytes.Equal(head[257:262], []byte("ustar")):
+		return nil
+	}
+
+	tr := tar.NewReader(layer)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(name, hdr, tr); err != nil {
+			return err
+		}
+	}
+}
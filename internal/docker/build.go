@@ -0,0 +1,234 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/moby/patternmatcher/ignorefile"
+)
+
+// BuildOptions configures an image build.
+type BuildOptions struct {
+	ContextDir string
+	Dockerfile string // Relative to ContextDir; defaults to "Dockerfile"
+	Tags       []string
+	BuildArgs  map[string]string
+	Target     string
+	NoCache    bool
+	Pull       bool // Always attempt to pull newer base images
+}
+
+// BuildLineKind classifies a line of build output.
+type BuildLineKind int
+
+const (
+	BuildLineOutput BuildLineKind = iota // Output of a build step
+	BuildLineStep                        // Start of a step, e.g. "Step 2/5 : RUN make"
+	BuildLineStatus                      // Progress of pulling a base image
+	BuildLineError                       // Build failure
+)
+
+// BuildLine is a line of build output.
+type BuildLine struct {
+	Kind BuildLineKind
+	Text string
+	Step int // Current step, 0 before the first step
+}
+
+// BuildProgress is the state of a running build.
+type BuildProgress struct {
+	Step       int
+	StepCount  int
+	ImageID    string // Set once the image has been built
+	Successful bool   // "Successfully built" was reported
+}
+
+// buildStepPattern matches the step header of the classic builder.
+var buildStepPattern = regexp.MustCompile(`^Step (\d+)/(\d+) : `)
+
+// BuildImage builds an image from a local context directory with the classic
+// builder, which works offline against local base images. Each line of output
+// is passed to fn along with the progress of the build.
+func (c *Client) BuildImage(ctx context.Context, opts BuildOptions, fn func(BuildLine, BuildProgress)) (BuildProgress, error) {
+	buildContext, dockerfile, err := tarBuildContext(opts.ContextDir, opts.Dockerfile)
+	if err != nil {
+		return BuildProgress{}, err
+	}
+	defer buildContext.Close()
+
+	buildArgs := make(map[string]*string, len(opts.BuildArgs))
+	for k, v := range opts.BuildArgs {
+		buildArgs[k] = &v
+	}
+
+	// Builds work offline, so unreadable credentials only affect private base images
+	authConfigs, _ := buildAuthConfigs()
+
+	c.mu.RLock()
+	resp, err := c.cli.ImageBuild(ctx, buildContext, types.ImageBuildOptions{
+		Tags:        opts.Tags,
+		Dockerfile:  dockerfile,
+		BuildArgs:   buildArgs,
+		Target:      opts.Target,
+		NoCache:     opts.NoCache,
+		PullParent:  opts.Pull,
+		Remove:      true,
+		ForceRemove: true,
+		Version:     types.BuilderV1,
+		AuthConfigs: authConfigs,
+	})
+	c.mu.RUnlock()
+	if err != nil {
+		return BuildProgress{}, err
+	}
+	defer resp.Body.Close()
+
+	progress, err := readBuildOutput(resp.Body, fn)
+	if err != nil && ctx.Err() != nil {
+		return progress, ctx.Err()
+	}
+	return progress, err
+}
+
+// readBuildOutput decodes the JSON message stream of a build.
+// It returns the error reported by the daemon, if any.
+func readBuildOutput(r io.Reader, fn func(BuildLine, BuildProgress)) (BuildProgress, error) {
+	var progress BuildProgress
+	emit := func(kind BuildLineKind, text string) {
+		if fn != nil {
+			fn(BuildLine{Kind: kind, Text: text, Step: progress.Step}, progress)
+		}
+	}
+
+	dec := json.NewDecoder(r)
+	var partial string // Stream output is not split at line boundaries
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return progress, err
+		}
+
+		if msg.Error != nil {
+			if partial != "" {
+				emit(BuildLineOutput, partial)
+				partial = ""
+			}
+			emit(BuildLineError, msg.Error.Message)
+			return progress, errors.New(msg.Error.Message)
+		}
+
+		if msg.Aux != nil {
+			var aux types.BuildResult
+			if err := json.Unmarshal(*msg.Aux, &aux); err == nil && aux.ID != "" {
+				progress.ImageID = aux.ID
+			}
+		}
+
+		if msg.Status != "" {
+			text := msg.Status
+			if msg.ID != "" {
+				text = msg.ID + ": " + text
+			}
+			emit(BuildLineStatus, text)
+		}
+
+		if msg.Stream == "" {
+			continue
+		}
+		lines := strings.Split(partial+msg.Stream, "\n")
+		partial = lines[len(lines)-1]
+		for _, line := range lines[:len(lines)-1] {
+			line = strings.TrimRight(line, "\r")
+			kind := BuildLineOutput
+			if m := buildStepPattern.FindStringSubmatch(line); m != nil {
+				progress.Step, _ = strconv.Atoi(m[1])
+				progress.StepCount, _ = strconv.Atoi(m[2])
+				kind = BuildLineStep
+			} else if strings.HasPrefix(line, "Successfully built ") {
+				progress.Successful = true
+			}
+			emit(kind, line)
+		}
+	}
+
+	if partial != "" {
+		emit(BuildLineOutput, partial)
+	}
+	return progress, nil
+}
+
+// tarBuildContext creates a tarball of a context directory, leaving out files
+// matched by its .dockerignore. The Dockerfile must be inside the context; its
+// path inside the tarball is returned.
+func tarBuildContext(contextDir, dockerfile string) (io.ReadCloser, string, error) {
+	contextDir, err := filepath.Abs(contextDir)
+	if err != nil {
+		return nil, "", err
+	}
+	info, err := os.Stat(contextDir)
+	if err != nil {
+		return nil, "", err
+	}
+	if !info.IsDir() {
+		return nil, "", fmt.Errorf("build context %s is not a directory", contextDir)
+	}
+
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	if !filepath.IsAbs(dockerfile) {
+		dockerfile = filepath.Join(contextDir, dockerfile)
+	}
+	rel, err := filepath.Rel(contextDir, dockerfile)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, "", fmt.Errorf("Dockerfile %s is outside of the build context", dockerfile)
+	}
+	if _, err := os.Stat(dockerfile); err != nil {
+		return nil, "", err
+	}
+	rel = filepath.ToSlash(rel)
+
+	excludes, err := readDockerignore(contextDir)
+	if err != nil {
+		return nil, "", err
+	}
+	// The daemon needs the Dockerfile and .dockerignore even if they are ignored
+	excludes = append(excludes, "!"+rel, "!.dockerignore")
+
+	rc, err := archive.TarWithOptions(contextDir, &archive.TarOptions{
+		ExcludePatterns: excludes,
+		ChownOpts:       &idtools.Identity{UID: 0, GID: 0},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return rc, rel, nil
+}
+
+// readDockerignore returns the patterns of the .dockerignore file in a directory.
+func readDockerignore(dir string) ([]string, error) {
+	f, err := os.Open(filepath.Join(dir, ".dockerignore"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ignorefile.ReadAll(f)
+}
@@ -14,6 +14,9 @@ const apiVersion = "1.51"
 type Client struct {
 	cli *client.Client
 	mu  sync.RWMutex
+
+	aliasMu sync.Mutex
+	aliases map[string][]string // Network aliases by endpoint ID, see NetworkAliases
 }
 
 // NewClient creates a new Docker client connected to the local Docker daemon.
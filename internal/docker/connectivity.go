@@ -0,0 +1,288 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// connectivityScript resolves $1 and connects to port $2 with whatever tools
+// the container has. Each line of output starts with a keyword; see
+// parseConnectivityOutput.
+const connectivityScript = `t="$1"; p="$2"
+if command -v getent >/dev/null 2>&1; then
+	echo "dns-tool getent"
+	{ getent ahosts "$t" 2>/dev/null || getent hosts "$t"; } | while read -r a rest; do echo "addr $a"; done
+elif command -v nslookup >/dev/null 2>&1; then
+	echo "dns-tool nslookup"
+	nslookup "$t" 2>&1 | while IFS= read -r l; do echo "ns $l"; done
+else
+	echo "dns-tool none"
+fi
+[ "$p" = 0 ] && exit 0
+if command -v nc >/dev/null 2>&1; then
+	echo "tcp-tool nc"
+	if m=$(nc -z -w 3 "$t" "$p" 2>&1); then echo "tcp ok"; else echo "tcp fail"; fi
+elif command -v bash >/dev/null 2>&1; then
+	echo "tcp-tool bash"
+	if m=$(bash -c 'exec 3<>"/dev/tcp/$0/$1"' "$t" "$p" 2>&1); then echo "tcp ok"; else echo "tcp fail"; fi
+else
+	echo "tcp-tool none"
+	exit 0
+fi
+printf '%s\n' "$m" | while IFS= read -r l; do [ -n "$l" ] && echo "tcp-msg $l"; done
+exit 0
+`
+
+// ConnectivityCheck describes a DNS and TCP check from inside a container.
+type ConnectivityCheck struct {
+	Source      string // ID of the running container to check from
+	Target      string // Container name, alias or hostname
+	Port        uint16 // 0 to only check name resolution
+	HelperImage string // Image for the helper container, default busybox
+}
+
+// ConnectivityResult is the outcome of a ConnectivityCheck.
+type ConnectivityResult struct {
+	Helper    string // Image of the helper container used, empty if the check ran in the source container
+	DNSTool   string
+	Addresses []string // Addresses the target resolved to, empty if it did not resolve
+	TCPTool   string   // Empty if no port was checked
+	Reachable bool
+	TCPError  string
+	Names     []NameMatch // Containers answering to the target name
+}
+
+// NameMatch is a container that can be reached by a name on a network.
+type NameMatch struct {
+	Container string
+	Network   string
+	Via       string // "container name", "container ID" or "alias"
+	Shared    bool   // Whether the source container is on the network too
+	NoDNS     bool   // The network has no embedded DNS (the default bridge)
+}
+
+// CheckConnectivity resolves the target and connects to its port from inside
+// the source container, using the tools it provides. When the container has
+// no shell or no suitable tools, a helper container sharing its network
+// namespace runs the check instead. The result also lists which networks and
+// aliases make the target name resolvable.
+func (c *Client) CheckConnectivity(ctx context.Context, check ConnectivityCheck) (ConnectivityResult, error) {
+	var result ConnectivityResult
+
+	c.mu.RLock()
+	containers, err := c.listContainers(ctx)
+	c.mu.RUnlock()
+	if err != nil {
+		return result, err
+	}
+	var source *Container
+	for i := range containers {
+		if containers[i].ID == shortID(check.Source) {
+			source = &containers[i]
+		}
+	}
+	if source == nil {
+		return result, fmt.Errorf("container %s not found", check.Source)
+	}
+	if source.State != "running" {
+		return result, fmt.Errorf("%s is not running", source.Name)
+	}
+	result.Names = matchName(containers, c.NetworkAliases(ctx, containers), *source, check.Target)
+
+	cmd := []string{"sh", "-c", connectivityScript, "sh", check.Target, strconv.Itoa(int(check.Port))}
+	output, err := c.execOutput(ctx, check.Source, cmd)
+	if err == nil {
+		parseConnectivityOutput(output, &result)
+	}
+	if err != nil || result.DNSTool == "none" || result.TCPTool == "none" {
+		// No shell or no tools: run the same script next to the container
+		img, err := c.helperImage(ctx, check.HelperImage)
+		if err != nil {
+			return result, fmt.Errorf("failed to get a helper image: %w", err)
+		}
+		output, err := c.runNetworkHelper(ctx, check.Source, img, cmd)
+		if err != nil {
+			return result, err
+		}
+		names := result.Names
+		result = ConnectivityResult{Helper: img, Names: names}
+		parseConnectivityOutput(output, &result)
+		if result.DNSTool == "none" || result.TCPTool == "none" {
+			return result, fmt.Errorf("the helper image %s has neither getent/nslookup nor nc", img)
+		}
+	}
+	return result, nil
+}
+
+// execOutput runs cmd in a running container and returns its standard output.
+func (c *Client) execOutput(ctx context.Context, id string, cmd []string) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	exec, err := c.cli.ContainerExecCreate(ctx, id, container.ExecOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.cli.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Close()
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, resp.Reader); err != nil {
+		return nil, err
+	}
+	inspect, err := c.cli.ContainerExecInspect(ctx, exec.ID)
+	if err != nil {
+		return nil, err
+	}
+	if inspect.ExitCode != 0 {
+		// 126 and 127 mean the shell does not exist or cannot run
+		return nil, fmt.Errorf("exec exited with status %d: %s", inspect.ExitCode, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// runNetworkHelper runs cmd in a helper container that shares the network
+// namespace of a container and returns its standard output.
+func (c *Client) runNetworkHelper(ctx context.Context, id, img string, cmd []string) ([]byte, error) {
+	c.mu.RLock()
+	created, err := c.cli.ContainerCreate(ctx, &container.Config{
+		Image:  img,
+		Cmd:    cmd,
+		Labels: map[string]string{helperLabel: "network"},
+	}, &container.HostConfig{
+		NetworkMode: container.NetworkMode("container:" + id),
+	}, nil, nil, "")
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	defer c.removeHelper(created.ID)
+
+	if err := c.runHelper(ctx, created.ID); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	logs, err := c.cli.ContainerLogs(ctx, created.ID, container.LogsOptions{ShowStdout: true})
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	defer logs.Close()
+
+	var stdout bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stdout, logs); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// parseConnectivityOutput reads the output of connectivityScript into result.
+func parseConnectivityOutput(output []byte, result *ConnectivityResult) {
+	var tcpMsg []string
+	seen := make(map[string]bool)
+	addAddr := func(s string) {
+		if addr, err := netip.ParseAddr(s); err == nil && !seen[addr.String()] {
+			seen[addr.String()] = true
+			result.Addresses = append(result.Addresses, addr.String())
+		}
+	}
+
+	// nslookup prints the DNS server's address before the answer
+	inAnswer := false
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		keyword, rest, _ := strings.Cut(scanner.Text(), " ")
+		switch keyword {
+		case "dns-tool":
+			result.DNSTool = rest
+		case "addr":
+			addAddr(rest)
+		case "ns":
+			rest = strings.TrimSpace(rest)
+			if strings.HasPrefix(rest, "Name:") {
+				inAnswer = true
+			} else if inAnswer && strings.HasPrefix(rest, "Address") {
+				// "Address: 172.18.0.3" or "Address 1: 172.18.0.3 db.backend"
+				if _, value, ok := strings.Cut(rest, ":"); ok {
+					if fields := strings.Fields(value); len(fields) > 0 {
+						addAddr(fields[0])
+					}
+				}
+			}
+		case "tcp-tool":
+			result.TCPTool = rest
+		case "tcp":
+			result.Reachable = rest == "ok"
+		case "tcp-msg":
+			tcpMsg = append(tcpMsg, rest)
+		}
+	}
+	if result.TCPTool != "" && !result.Reachable {
+		result.TCPError = strings.Join(tcpMsg, " ")
+	}
+}
+
+// matchName finds the containers that answer to name on each of their
+// networks. Docker's embedded DNS resolves container names, short IDs and
+// aliases on user-defined networks only. aliases are those of NetworkAliases.
+func matchName(containers []Container, aliases map[string]map[string][]string, source Container, name string) []NameMatch {
+	name = strings.TrimSuffix(name, ".")
+	onSource := make(map[string]bool)
+	for _, n := range source.Networks {
+		onSource[n.NetworkID] = true
+	}
+
+	var matches []NameMatch
+	for _, ctr := range containers {
+		for _, n := range ctr.Networks {
+			// Names may be qualified with the network, e.g. db.backend
+			bare, qualified := strings.CutSuffix(strings.ToLower(name), "."+strings.ToLower(n.Name))
+			if !qualified {
+				bare = strings.ToLower(name)
+			}
+
+			via := ""
+			switch {
+			case bare == strings.ToLower(ctr.Name):
+				via = "container name"
+			case bare == ctr.ID:
+				via = "container ID"
+			default:
+				for _, alias := range aliases[ctr.ID][n.NetworkID] {
+					if bare == strings.ToLower(alias) {
+						via = "alias"
+						break
+					}
+				}
+			}
+			if via == "" {
+				continue
+			}
+			matches = append(matches, NameMatch{
+				Container: ctr.Name,
+				Network:   n.Name,
+				Via:       via,
+				Shared:    onSource[n.NetworkID],
+				NoDNS:     n.Name == network.NetworkBridge || n.Name == network.NetworkHost,
+			})
+		}
+	}
+	return matches
+}
@@ -2,23 +2,67 @@ package docker
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
 )
 
-const composeProjectLabel = "com.docker.compose.project"
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
 
 // Container represents a Docker container with relevant information.
 type Container struct {
-	ID      string
-	Name    string
-	Image   string
-	Status  string
-	State   string
-	Project string // Compose project name, empty if standalone
+	ID       string
+	Name     string
+	Image    string
+	ImageID  string // Short ID of the image, matching Image.ID
+	Status   string
+	State    string
+	Project  string   // Compose project name, empty if standalone
+	Service  string   // Compose service name, empty if standalone
+	Volumes  []string // Names of the mounted volumes
+	Networks []ContainerNetwork
+	Ports    []ContainerPort // Exposed ports, published or not
+}
+
+// ContainerNetwork is a network a container is connected to, with its addresses on it.
+type ContainerNetwork struct {
+	NetworkID string // Short ID, matching Network.ID
+	Name      string
+	IPv4      string // Empty while the container is not running
+	IPv6      string
+	Endpoint  string // Endpoint ID, empty while the container is not running
+}
+
+// ContainerPort is a port exposed by a container.
+type ContainerPort struct {
+	IP          string // Host address the port is published on, empty if not published
+	PrivatePort uint16
+	PublicPort  uint16 // 0 if not published
+	Type        string // tcp, udp or sctp
+}
+
+// Published reports whether the port is reachable from the host.
+func (p ContainerPort) Published() bool {
+	return p.PublicPort != 0
+}
+
+// String formats the port like "8080->80/tcp", or "80/tcp" if it is not published.
+func (p ContainerPort) String() string {
+	s := strconv.Itoa(int(p.PrivatePort)) + "/" + p.Type
+	if p.Published() {
+		s = strconv.Itoa(int(p.PublicPort)) + "->" + s
+	}
+	return s
 }
 
 // ContainerGroup represents a group of containers, either by project or standalone.
@@ -31,7 +75,11 @@ type ContainerGroup struct {
 func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
+	return c.listContainers(ctx)
+}
 
+// listContainers returns all containers. c.mu must be held.
+func (c *Client) listContainers(ctx context.Context) ([]Container, error) {
 	containers, err := c.cli.ContainerList(ctx, container.ListOptions{
 		All: true,
 	})
@@ -51,13 +99,63 @@ func (c *Client) ListContainers(ctx context.Context) ([]Container, error) {
 			project = p
 		}
 
+		var volumes []string
+		for _, m := range ctr.Mounts {
+			if m.Type == mount.TypeVolume && m.Name != "" {
+				volumes = append(volumes, m.Name)
+			}
+		}
+
+		var networks []ContainerNetwork
+		if ctr.NetworkSettings != nil {
+			for name, ep := range ctr.NetworkSettings.Networks {
+				if ep == nil {
+					continue
+				}
+				networks = append(networks, ContainerNetwork{
+					NetworkID: shortID(ep.NetworkID),
+					Name:      name,
+					IPv4:      ep.IPAddress,
+					IPv6:      ep.GlobalIPv6Address,
+					Endpoint:  ep.EndpointID,
+				})
+			}
+			sort.Slice(networks, func(i, j int) bool {
+				return networks[i].Name < networks[j].Name
+			})
+		}
+
+		// The daemon lists published ports once per host address (IPv4 and IPv6)
+		var ports []ContainerPort
+		seen := make(map[ContainerPort]bool)
+		for _, p := range ctr.Ports {
+			port := ContainerPort{PrivatePort: p.PrivatePort, PublicPort: p.PublicPort, Type: p.Type}
+			if seen[port] {
+				continue
+			}
+			seen[port] = true
+			port.IP = p.IP
+			ports = append(ports, port)
+		}
+		sort.Slice(ports, func(i, j int) bool {
+			if ports[i].PrivatePort != ports[j].PrivatePort {
+				return ports[i].PrivatePort < ports[j].PrivatePort
+			}
+			return ports[i].Type < ports[j].Type
+		})
+
 		result = append(result, Container{
-			ID:      ctr.ID[:12],
-			Name:    name,
-			Image:   ctr.Image,
-			Status:  ctr.Status,
-			State:   ctr.State,
-			Project: project,
+			ID:       ctr.ID[:12],
+			Name:     name,
+			Image:    ctr.Image,
+			ImageID:  shortImageID(ctr.ImageID),
+			Status:   ctr.Status,
+			State:    ctr.State,
+			Project:  project,
+			Service:  ctr.Labels[composeServiceLabel],
+			Volumes:  volumes,
+			Networks: networks,
+			Ports:    ports,
 		})
 	}
 
@@ -177,6 +275,74 @@ func (c *Client) RemoveProject(ctx context.Context, projectName string) error {
 	return nil
 }
 
+// ContainerInfo holds the runtime state of a single container instance.
+type ContainerInfo struct {
+	ID        string
+	Name      string
+	Running   bool
+	ExitCode  int
+	StartedAt time.Time
+	Tty       bool
+	Project   string
+	Service   string
+}
+
+// InspectContainer returns the runtime state of a container by ID or name.
+func (c *Client) InspectContainer(ctx context.Context, containerID string) (ContainerInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	info, err := c.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return ContainerInfo{}, err
+	}
+
+	result := ContainerInfo{
+		ID:   info.ID,
+		Name: strings.TrimPrefix(info.Name, "/"),
+	}
+	if info.State != nil {
+		result.Running = info.State.Running
+		result.ExitCode = info.State.ExitCode
+		result.StartedAt, _ = time.Parse(time.RFC3339Nano, info.State.StartedAt)
+	}
+	if info.Config != nil {
+		result.Tty = info.Config.Tty
+		result.Project = info.Config.Labels[composeProjectLabel]
+		result.Service = info.Config.Labels[composeServiceLabel]
+	}
+
+	return result, nil
+}
+
+// FindServiceContainer returns the ID of the container running a compose service.
+// Running containers are preferred over stopped ones.
+func (c *Client) FindServiceContainer(ctx context.Context, project, service string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	containers, err := c.cli.ContainerList(ctx, container.ListOptions{
+		All: true,
+		Filters: filters.NewArgs(
+			filters.Arg("label", composeProjectLabel+"="+project),
+			filters.Arg("label", composeServiceLabel+"="+service),
+		),
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(containers) == 0 {
+		return "", fmt.Errorf("no container found for service %s/%s", project, service)
+	}
+
+	for _, ctr := range containers {
+		if ctr.State == "running" {
+			return ctr.ID, nil
+		}
+	}
+	return containers[0].ID, nil
+}
+
 // ContainerLogs returns a reader for streaming container logs.
 // The caller is responsible for closing the returned reader.
 func (c *Client) ContainerLogs(ctx context.Context, containerID string, follow bool) (io.ReadCloser, error) {
@@ -0,0 +1,88 @@
+package docker
+
+import (
+	"context"
+	"path"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+)
+
+// helperVolumeTarget is where helper containers mount the volume they work on.
+const helperVolumeTarget = "/volume"
+
+// helperLabel marks containers Harbor creates for its own use.
+const helperLabel = "dev.harbor.helper"
+
+// defaultHelperImages are the local images tried for helper containers, in order.
+// The first one is pulled if none of them exists.
+var defaultHelperImages = []string{"busybox", "alpine"}
+
+// helperImage returns a local image to create helper containers from, pulling it if needed.
+func (c *Client) helperImage(ctx context.Context, configured string) (string, error) {
+	candidates := defaultHelperImages
+	if configured != "" {
+		candidates = []string{configured}
+	}
+
+	c.mu.RLock()
+	for _, ref := range candidates {
+		images, err := c.cli.ImageList(ctx, image.ListOptions{Filters: filters.NewArgs(filters.Arg("reference", ref))})
+		if err != nil {
+			c.mu.RUnlock()
+			return "", err
+		}
+		for _, img := range images {
+			if len(img.RepoTags) > 0 {
+				c.mu.RUnlock()
+				return img.RepoTags[0], nil
+			}
+		}
+	}
+	c.mu.RUnlock()
+
+	if err := c.PullImage(ctx, candidates[0], PullOptions{}, func(TransferProgress) {}); err != nil {
+		return "", err
+	}
+	return candidates[0], nil
+}
+
+// createVolumeHelper creates a container from img that mounts a volume at
+// helperVolumeTarget. The archive API works on it without starting it; cmd is
+// only needed for helpers that are started. c.mu must be held.
+func (c *Client) createVolumeHelper(ctx context.Context, volume, img string, readOnly bool, cmd []string) (string, error) {
+	if cmd == nil {
+		cmd = []string{"true"}
+	}
+	created, err := c.cli.ContainerCreate(ctx, &container.Config{
+		Image:           img,
+		Cmd:             cmd,
+		Labels:          map[string]string{helperLabel: "volume"},
+		NetworkDisabled: true,
+	}, &container.HostConfig{
+		Mounts: []mount.Mount{{
+			Type:     mount.TypeVolume,
+			Source:   volume,
+			Target:   helperVolumeTarget,
+			ReadOnly: readOnly,
+		}},
+	}, nil, nil, "")
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// removeHelper removes a helper container, even if the operation using it was cancelled.
+func (c *Client) removeHelper(id string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cli.ContainerRemove(context.Background(), id, container.RemoveOptions{Force: true})
+}
+
+// helperPath maps a path inside the volume to the helper container.
+func helperPath(p string) string {
+	return path.Join(helperVolumeTarget, cleanVolumePath(p))
+}
@@ -0,0 +1,226 @@
+package docker
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// ArchiveFormat is the output format of an image export.
+type ArchiveFormat int
+
+const (
+	ArchiveTar       ArchiveFormat = iota // docker-archive tarball as produced by "docker save"
+	ArchiveTarGzip                        // Gzip-compressed docker-archive tarball
+	ArchiveOCILayout                      // Directory in the OCI image layout
+)
+
+// Extension returns the file extension for an archive format, or "" for directories.
+func (f ArchiveFormat) Extension() string {
+	switch f {
+	case ArchiveTarGzip:
+		return ".tar.gz"
+	case ArchiveOCILayout:
+		return ""
+	default:
+		return ".tar"
+	}
+}
+
+// ociLayoutFile marks the root of an OCI image layout.
+const ociLayoutFile = "oci-layout"
+
+// ExportImages writes one or more images into a single archive at path.
+// progress, if not nil, is called with the number of bytes read from the daemon.
+func (c *Client) ExportImages(ctx context.Context, refs []string, path string, format ArchiveFormat, progress func(int64)) error {
+	if len(refs) == 0 {
+		return errors.New("no images selected")
+	}
+
+	c.mu.RLock()
+	rc, err := c.cli.ImageSave(ctx, refs)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	r := &countingReader{r: rc, fn: progress}
+
+	if format == ArchiveOCILayout {
+		return extractOCILayout(r, path)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = f
+	var gz *gzip.Writer
+	if format == ArchiveTarGzip {
+		gz = gzip.NewWriter(f)
+		w = gz
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			_ = f.Close()
+			_ = os.Remove(path)
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// extractOCILayout unpacks the tarball of ImageSave into a directory. Docker 25
+// and newer produce archives that are valid OCI image layouts as well.
+func extractOCILayout(r io.Reader, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	sawLayout := false
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(root, filepath.FromSlash(hdr.Name))
+		if target != root && !strings.HasPrefix(target, root+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry %s is outside of the target directory", hdr.Name)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if hdr.Name == ociLayoutFile {
+				sawLayout = true
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := writeArchiveFile(target, tr); err != nil {
+				return err
+			}
+		default:
+			// Legacy archives link duplicate layers; the OCI layout has no links
+			continue
+		}
+	}
+
+	if !sawLayout {
+		return errors.New("the Docker daemon did not produce an OCI image layout (requires Docker 25 or newer)")
+	}
+	return nil
+}
+
+func writeArchiveFile(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ImportImages loads images from a docker-archive tarball (optionally compressed)
+// or an OCI image-layout directory and returns the names of the loaded images.
+// progress, if not nil, is called with the bytes sent and the total size to send
+// (0 if unknown).
+func (c *Client) ImportImages(ctx context.Context, path string, progress func(sent, total int64)) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var input io.ReadCloser
+	var total int64
+	if info.IsDir() {
+		if _, err := os.Stat(filepath.Join(path, ociLayoutFile)); err != nil {
+			return nil, fmt.Errorf("%s is not an OCI image layout", path)
+		}
+		input, err = archive.Tar(path, archive.Uncompressed)
+	} else {
+		// The daemon decompresses gzip, bzip2 and xz archives itself
+		input, err = os.Open(path)
+		total = info.Size()
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+
+	var report func(int64)
+	if progress != nil {
+		report = func(n int64) { progress(n, total) }
+	}
+
+	c.mu.RLock()
+	resp, err := c.cli.ImageLoad(ctx, &countingReader{r: input, fn: report}, false)
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return readLoadOutput(resp.Body)
+}
+
+// readLoadOutput collects the loaded image names from the output of ImageLoad.
+func readLoadOutput(r io.Reader) ([]string, error) {
+	var loaded []string
+	dec := json.NewDecoder(r)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return loaded, nil
+			}
+			return loaded, err
+		}
+		if msg.Error != nil {
+			return loaded, errors.New(msg.Error.Message)
+		}
+		for _, line := range strings.Split(msg.Stream, "\n") {
+			line = strings.TrimSpace(line)
+			if name, ok := strings.CutPrefix(line, "Loaded image: "); ok {
+				loaded = append(loaded, name)
+			} else if id, ok := strings.CutPrefix(line, "Loaded image ID: "); ok {
+				loaded = append(loaded, shortImageID(id))
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package docker
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ImageLayer is a step of an image's history, oldest first.
+type ImageLayer struct {
+	ID          string // Short layer image ID, "<missing>" for layers built elsewhere
+	Instruction string // Dockerfile instruction that created the layer
+	Created     time.Time
+	Size        int64
+	Comment     string
+}
+
+// Empty reports whether the step did not add any files (e.g. ENV or CMD).
+func (l ImageLayer) Empty() bool {
+	return l.Size == 0
+}
+
+// ImageConfig is the runtime configuration baked into an image.
+type ImageConfig struct {
+	Entrypoint   []string
+	Cmd          []string
+	Env          []string
+	ExposedPorts []string
+	Volumes      []string
+	Labels       map[string]string
+	User         string
+	WorkingDir   string
+	Architecture string
+	OS           string
+}
+
+// ImageDetail is the history and configuration of an image.
+type ImageDetail struct {
+	ID      string
+	Tags    []string
+	Digests []string
+	Created time.Time
+	Size    int64
+	Config  ImageConfig
+	Layers  []ImageLayer
+}
+
+// InspectImage returns the history and configuration of an image.
+func (c *Client) InspectImage(ctx context.Context, ref string) (ImageDetail, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	inspect, _, err := c.cli.ImageInspectWithRaw(ctx, ref)
+	if err != nil {
+		return ImageDetail{}, err
+	}
+	history, err := c.cli.ImageHistory(ctx, ref)
+	if err != nil {
+		return ImageDetail{}, err
+	}
+
+	detail := ImageDetail{
+		ID:      shortImageID(inspect.ID),
+		Tags:    inspect.RepoTags,
+		Digests: inspect.RepoDigests,
+		Size:    inspect.Size,
+		Config: ImageConfig{
+			Architecture: inspect.Architecture,
+			OS:           inspect.Os,
+		},
+	}
+	if created, err := time.Parse(time.RFC3339Nano, inspect.Created); err == nil {
+		detail.Created = created
+	}
+	if inspect.Variant != "" {
+		detail.Config.Architecture += "/" + inspect.Variant
+	}
+
+	if cfg := inspect.Config; cfg != nil {
+		detail.Config.Entrypoint = cfg.Entrypoint
+		detail.Config.Cmd = cfg.Cmd
+		detail.Config.Env = cfg.Env
+		detail.Config.Labels = cfg.Labels
+		detail.Config.User = cfg.User
+		detail.Config.WorkingDir = cfg.WorkingDir
+		for port := range cfg.ExposedPorts {
+			detail.Config.ExposedPorts = append(detail.Config.ExposedPorts, string(port))
+		}
+		for volume := range cfg.Volumes {
+			detail.Config.Volumes = append(detail.Config.Volumes, volume)
+		}
+		sort.Strings(detail.Config.ExposedPorts)
+		sort.Strings(detail.Config.Volumes)
+	}
+
+	// History is returned newest first
+	for i := len(history) - 1; i >= 0; i-- {
+		h := history[i]
+		id := h.ID
+		if id != "<missing>" {
+			id = shortImageID(id)
+		}
+		detail.Layers = append(detail.Layers, ImageLayer{
+			ID:          id,
+			Instruction: formatInstruction(h.CreatedBy),
+			Created:     time.Unix(h.Created, 0),
+			Size:        h.Size,
+			Comment:     h.Comment,
+		})
+	}
+
+	return detail, nil
+}
+
+// formatInstruction turns the CreatedBy field of a history entry into a
+// Dockerfile-like instruction, e.g. "/bin/sh -c #(nop)  CMD [...]" into "CMD [...]".
+func formatInstruction(createdBy string) string {
+	s := strings.TrimSpace(createdBy)
+	s = strings.TrimSuffix(s, " # buildkit")
+	if rest, ok := strings.CutPrefix(s, "/bin/sh -c #(nop)"); ok {
+		return strings.TrimSpace(rest)
+	}
+	if rest, ok := strings.CutPrefix(s, "/bin/sh -c "); ok {
+		return "RUN " + strings.TrimSpace(rest)
+	}
+	return s
+}
@@ -2,49 +2,121 @@ package docker
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strings"
 
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
 )
 
+// untaggedImage is the placeholder tag of images without a repository tag.
+const untaggedImage = "<none>:<none>"
+
 // Image represents a Docker image with relevant information.
 type Image struct {
-	ID      string
-	Tags    []string
-	Size    int64
-	Created int64
+	ID       string
+	Tags     []string
+	Size     int64
+	Created  int64
+	Dangling bool     // true if the image has no tags
+	Digests  []string // Registry digests, e.g. "postgres@sha256:…", empty for local builds
+
+	// SharedSize is the size of the layers shared with other images, or -1 if unknown.
+	SharedSize int64
+	// Containers are the containers created from the image, running or not.
+	Containers []Container
+}
+
+// UniqueSize returns the size of the layers no other image uses, which is
+// the space removing the image actually reclaims.
+func (img Image) UniqueSize() int64 {
+	if img.SharedSize < 0 {
+		return img.Size
+	}
+	return img.Size - img.SharedSize
+}
+
+// InUse reports whether any container was created from the image.
+func (img Image) InUse() bool {
+	return len(img.Containers) > 0
+}
+
+// RemoveImageOptions configures the removal of an image.
+type RemoveImageOptions struct {
+	// Force removes the image even if it has several tags or is used by stopped containers.
+	Force bool
+	// NoPrune keeps untagged parent images.
+	NoPrune bool
+}
+
+// ImageInUseError is returned when an image cannot be removed because containers use it.
+type ImageInUseError struct {
+	Err        error
+	Containers []Container
+}
+
+func (e *ImageInUseError) Error() string {
+	names := make([]string, len(e.Containers))
+	for i, ctr := range e.Containers {
+		names[i] = ctr.Name
+	}
+	if len(names) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("image is used by %s", strings.Join(names, ", "))
+}
+
+func (e *ImageInUseError) Unwrap() error {
+	return e.Err
+}
+
+// PruneReport summarizes the result of a prune.
+type PruneReport struct {
+	Deleted        int
+	SpaceReclaimed uint64
 }
 
-// ListImages returns all images.
+// ListImages returns all images with the containers using them.
 func (c *Client) ListImages(ctx context.Context) ([]Image, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	images, err := c.cli.ImageList(ctx, image.ListOptions{
-		All: false, // Don't include intermediate images
+		All:        false, // Don't include intermediate images
+		SharedSize: true,
 	})
 	if err != nil {
 		return nil, err
 	}
+	containers, err := c.listContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	usedBy := make(map[string][]Container)
+	for _, ctr := range containers {
+		usedBy[ctr.ImageID] = append(usedBy[ctr.ImageID], ctr)
+	}
 
 	result := make([]Image, 0, len(images))
 	for _, img := range images {
-		id := img.ID
-		if strings.HasPrefix(id, "sha256:") {
-			id = id[7:19] // Take first 12 chars after sha256:
-		}
-
+		id := shortImageID(img.ID)
 		tags := img.RepoTags
-		if len(tags) == 0 {
-			tags = []string{"<none>:<none>"}
+		dangling := len(tags) == 0 || len(tags) == 1 && tags[0] == untaggedImage
+		if dangling {
+			tags = []string{untaggedImage}
 		}
 
 		result = append(result, Image{
-			ID:      id,
-			Tags:    tags,
-			Size:    img.Size,
-			Created: img.Created,
+			ID:         id,
+			Tags:       tags,
+			Size:       img.Size,
+			Created:    img.Created,
+			Dangling:   dangling,
+			Digests:    img.RepoDigests,
+			SharedSize: img.SharedSize,
+			Containers: usedBy[id],
 		})
 	}
 
@@ -56,6 +128,130 @@ func (c *Client) ListImages(ctx context.Context) ([]Image, error) {
 	return result, nil
 }
 
+// shortImageID returns the first 12 characters of an image ID without the "sha256:" prefix.
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return id
+}
+
+// RemoveImage removes an image by ID or reference and returns the removed
+// and untagged references. If the image is in use, an *ImageInUseError
+// listing the containers that use it is returned.
+func (c *Client) RemoveImage(ctx context.Context, ref string, opts RemoveImageOptions) ([]string, error) {
+	c.mu.RLock()
+	responses, err := c.cli.ImageRemove(ctx, ref, image.RemoveOptions{
+		Force:         opts.Force,
+		PruneChildren: !opts.NoPrune,
+	})
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, c.imageRemoveError(ctx, ref, err)
+	}
+
+	var removed []string
+	for _, r := range responses {
+		if r.Untagged != "" {
+			removed = append(removed, r.Untagged)
+		}
+		if r.Deleted != "" {
+			removed = append(removed, shortImageID(r.Deleted))
+		}
+	}
+	return removed, nil
+}
+
+// UntagImage removes a single tag. If it is the last tag of the image, the image is removed too.
+func (c *Client) UntagImage(ctx context.Context, tag string) error {
+	_, err := c.RemoveImage(ctx, tag, RemoveImageOptions{NoPrune: true})
+	return err
+}
+
+// PruneImages removes dangling images, or all images without containers if all is true.
+func (c *Client) PruneImages(ctx context.Context, all bool) (PruneReport, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	args := filters.NewArgs()
+	if all {
+		args.Add("dangling", "false")
+	} else {
+		args.Add("dangling", "true")
+	}
+	report, err := c.cli.ImagesPrune(ctx, args)
+	if err != nil {
+		return PruneReport{}, err
+	}
+
+	deleted := 0
+	for _, r := range report.ImagesDeleted {
+		if r.Deleted != "" {
+			deleted++
+		}
+	}
+	return PruneReport{Deleted: deleted, SpaceReclaimed: report.SpaceReclaimed}, nil
+}
+
+// PruneCandidates returns the images a prune would remove: dangling images,
+// or all images without containers if all is true.
+func (c *Client) PruneCandidates(ctx context.Context, all bool) ([]Image, error) {
+	images, err := c.ListImages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := c.ListContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[string]bool, len(containers))
+	for _, ctr := range containers {
+		used[ctr.ImageID] = true
+	}
+
+	var candidates []Image
+	for _, img := range images {
+		if used[img.ID] || !all && !img.Dangling {
+			continue
+		}
+		candidates = append(candidates, img)
+	}
+	return candidates, nil
+}
+
+// imageRemoveError wraps conflict errors into an *ImageInUseError listing the containers using the image.
+func (c *Client) imageRemoveError(ctx context.Context, ref string, err error) error {
+	if !errdefs.IsConflict(err) {
+		return err
+	}
+
+	c.mu.RLock()
+	inspect, _, inspectErr := c.cli.ImageInspectWithRaw(ctx, ref)
+	c.mu.RUnlock()
+	if inspectErr != nil {
+		return err
+	}
+	containers, listErr := c.ListContainers(ctx)
+	if listErr != nil {
+		return err
+	}
+
+	imageID := shortImageID(inspect.ID)
+	inUse := &ImageInUseError{Err: err}
+	for _, ctr := range containers {
+		if ctr.ImageID == imageID {
+			inUse.Containers = append(inUse.Containers, ctr)
+		}
+	}
+	if len(inUse.Containers) == 0 {
+		// Conflicts can also be caused by multiple tags, which need no containers
+		return err
+	}
+	return inUse
+}
+
 // FormatSize formats a size in bytes to a human-readable string.
 func FormatSize(size int64) string {
 	const (
@@ -0,0 +1,259 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Whiteout markers of the OCI image layer format
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+// FileChange describes how a layer changed a path.
+type FileChange int
+
+const (
+	FileAdded    FileChange = iota // The path did not exist in lower layers
+	FileModified                   // The path replaced a file of a lower layer
+	FileDeleted                    // The path was removed by a whiteout
+)
+
+// LayerChange is a single path added, modified or deleted by a layer.
+type LayerChange struct {
+	Path   string
+	Size   int64 // Size of the file; for deletions the size of everything removed
+	IsDir  bool
+	Change FileChange
+}
+
+// LayerContents lists the changes of an image layer.
+type LayerContents struct {
+	Digest  string
+	Size    int64 // Bytes of all files added or modified by the layer
+	Changes []LayerChange
+}
+
+// WastedFile is a path whose content in some layer is not visible in the final image.
+type WastedFile struct {
+	Path        string
+	Occurrences int   // Number of layers that wrote or deleted the path
+	WastedBytes int64 // Size of the hidden versions
+}
+
+// ImageAnalysis is the result of reading all layers of an image.
+type ImageAnalysis struct {
+	Layers      []LayerContents
+	TotalBytes  int64 // Bytes of all files in all layers
+	WastedBytes int64 // Bytes overwritten or deleted by later layers
+	Wasted      []WastedFile
+}
+
+// Efficiency returns the share of stored bytes that are visible in the final image, between 0 and 1.
+func (a ImageAnalysis) Efficiency() float64 {
+	if a.TotalBytes == 0 {
+		return 1
+	}
+	return 1 - float64(a.WastedBytes)/float64(a.TotalBytes)
+}
+
+// rawLayerEntry is a file header of a layer tarball.
+type rawLayerEntry struct {
+	path  string
+	size  int64
+	isDir bool
+}
+
+// fsNode is a path in the merged filesystem while layers are applied.
+type fsNode struct {
+	children map[string]*fsNode
+	size     int64
+	isDir    bool
+}
+
+// subtreeSize returns the size of a node and all of its descendants.
+func (n *fsNode) subtreeSize() int64 {
+	size := n.size
+	for _, child := range n.children {
+		size += child.subtreeSize()
+	}
+	return size
+}
+
+// lookup returns the node at p, creating missing directories if create is true.
+func (n *fsNode) lookup(p string, create bool) *fsNode {
+	node := n
+	for _, part := range strings.Split(p, "/") {
+		if part == "" {
+			continue
+		}
+		child, ok := node.children[part]
+		if !ok {
+			if !create {
+				return nil
+			}
+			child = &fsNode{isDir: true}
+			if node.children == nil {
+				node.children = make(map[string]*fsNode)
+			}
+			node.children[part] = child
+		}
+		node = child
+	}
+	return node
+}
+
+// remove detaches the node at p and returns it, or nil if it does not exist.
+func (n *fsNode) remove(p string) *fsNode {
+	parent := n.lookup(path.Dir(p), false)
+	if parent == nil {
+		return nil
+	}
+	name := path.Base(p)
+	child := parent.children[name]
+	delete(parent.children, name)
+	return child
+}
+
+// AnalyzeImage exports an image and computes the changes of every layer and
+// the space wasted by files that later layers overwrite or delete.
+// progress, if not nil, is called with the number of bytes read from the daemon.
+func (c *Client) AnalyzeImage(ctx context.Context, ref string, progress func(int64)) (ImageAnalysis, error) {
+	raw := make(map[string][]rawLayerEntry)
+	order, err := c.walkImage(ctx, ref, func(layer string, hdr *tar.Header, _ io.Reader) error {
+		entry := rawLayerEntry{
+			path:  cleanLayerPath(hdr.Name),
+			isDir: hdr.Typeflag == tar.TypeDir,
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			entry.size = hdr.Size
+		}
+		if entry.path != "" {
+			raw[layer] = append(raw[layer], entry)
+		}
+		return nil
+	}, progress)
+	if err != nil {
+		return ImageAnalysis{}, err
+	}
+
+	return analyzeLayers(order, raw), nil
+}
+
+// cleanLayerPath normalizes a tar entry name to a relative path without trailing slash.
+func cleanLayerPath(name string) string {
+	p := path.Clean("/" + name)
+	return strings.TrimPrefix(p, "/")
+}
+
+// analyzeLayers applies the layers in order to a merged filesystem and records their changes.
+func analyzeLayers(order []string, raw map[string][]rawLayerEntry) ImageAnalysis {
+	var analysis ImageAnalysis
+	root := &fsNode{isDir: true}
+	wasted := make(map[string]*WastedFile)
+
+	addWaste := func(p string, size int64) {
+		analysis.WastedBytes += size
+		w, ok := wasted[p]
+		if !ok {
+			w = &WastedFile{Path: p, Occurrences: 1}
+			wasted[p] = w
+		}
+		w.Occurrences++
+		w.WastedBytes += size
+	}
+
+	for _, layer := range order {
+		contents := LayerContents{Digest: layerDigest(layer)}
+		entries := raw[layer]
+
+		// Whiteouts only affect lower layers, so apply them before the additions
+		for _, e := range entries {
+			dir, base := path.Split(e.path)
+			dir = strings.TrimSuffix(dir, "/")
+			switch {
+			case base == whiteoutOpaque:
+				node := root.lookup(dir, false)
+				if node == nil {
+					continue
+				}
+				removed := node.subtreeSize() - node.size
+				node.children = nil
+				if removed > 0 {
+					addWaste(dir, removed)
+				}
+				contents.Changes = append(contents.Changes, LayerChange{Path: dir, Size: removed, IsDir: true, Change: FileDeleted})
+			case strings.HasPrefix(base, whiteoutPrefix):
+				target := path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix))
+				node := root.remove(target)
+				if node == nil {
+					continue
+				}
+				removed := node.subtreeSize()
+				if removed > 0 {
+					addWaste(target, removed)
+				}
+				contents.Changes = append(contents.Changes, LayerChange{Path: target, Size: removed, IsDir: node.isDir, Change: FileDeleted})
+			}
+		}
+
+		for _, e := range entries {
+			base := path.Base(e.path)
+			if strings.HasPrefix(base, whiteoutPrefix) {
+				continue
+			}
+
+			existing := root.lookup(e.path, false)
+			if e.isDir {
+				if existing == nil {
+					root.lookup(e.path, true)
+					contents.Changes = append(contents.Changes, LayerChange{Path: e.path, IsDir: true, Change: FileAdded})
+				}
+				continue
+			}
+
+			change := FileAdded
+			if existing != nil {
+				change = FileModified
+				if hidden := existing.subtreeSize(); hidden > 0 {
+					addWaste(e.path, hidden)
+				}
+				root.remove(e.path)
+			}
+			node := root.lookup(e.path, true)
+			node.isDir = false
+			node.size = e.size
+
+			contents.Size += e.size
+			contents.Changes = append(contents.Changes, LayerChange{Path: e.path, Size: e.size, Change: change})
+		}
+
+		sort.Slice(contents.Changes, func(i, j int) bool {
+			return contents.Changes[i].Path < contents.Changes[j].Path
+		})
+		analysis.TotalBytes += contents.Size
+		analysis.Layers = append(analysis.Layers, contents)
+	}
+
+	for _, w := range wasted {
+		analysis.Wasted = append(analysis.Wasted, *w)
+	}
+	sort.Slice(analysis.Wasted, func(i, j int) bool {
+		return analysis.Wasted[i].WastedBytes > analysis.Wasted[j].WastedBytes
+	})
+	return analysis
+}
+
+// layerDigest derives a layer digest from its path in the archive,
+// e.g. "blobs/sha256/abc…" or "abc…/layer.tar".
+func layerDigest(layer string) string {
+	if hex, ok := strings.CutPrefix(layer, "blobs/sha256/"); ok {
+		return "sha256:" + hex
+	}
+	return strings.TrimSuffix(layer, "/layer.tar")
+}
@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// LogFormat selects the file format used when exporting logs.
+type LogFormat int
+
+const (
+	LogFormatText       LogFormat = iota // Messages only
+	LogFormatTimestamps                  // Messages prefixed with their timestamp
+	LogFormatNDJSON                      // One JSON object per line with stream, timestamp and message
+)
+
+// Extension returns the file extension conventionally used for the format.
+func (f LogFormat) Extension() string {
+	if f == LogFormatNDJSON {
+		return ".ndjson"
+	}
+	return ".log"
+}
+
+// ndjsonLogLine is the JSON representation of a line in LogFormatNDJSON.
+type ndjsonLogLine struct {
+	Stream    string `json:"stream"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Message   string `json:"message"`
+}
+
+// FetchLogs returns the complete log history of a container without following it.
+func (c *Client) FetchLogs(ctx context.Context, containerID string, tty bool) ([]LogLine, error) {
+	var lines []LogLine
+	err := c.StreamLogs(ctx, containerID, LogOptions{Tty: tty}, func(line LogLine) {
+		lines = append(lines, line)
+	})
+	return lines, err
+}
+
+// WriteLogs writes log entries to w in the given format, optionally gzip-compressed.
+// In NDJSON a grouped multiline entry is written as a single object whose
+// message contains all of its lines. Entries without a stream (such as
+// restart markers) are written as text but left out of NDJSON, since they
+// are not part of the container output.
+func WriteLogs(w io.Writer, entries []LogEntry, format LogFormat, compress bool) error {
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for _, entry := range entries {
+		var err error
+		switch format {
+		case LogFormatNDJSON:
+			if entry.Stream == "" {
+				continue
+			}
+			obj := ndjsonLogLine{Stream: entry.Stream, Message: entry.Text()}
+			if !entry.Timestamp.IsZero() {
+				obj.Timestamp = entry.Timestamp.Format(time.RFC3339Nano)
+			}
+			err = enc.Encode(obj)
+		default:
+			for _, line := range entry.Lines() {
+				if err = writeTextLogLine(bw, line, format == LogFormatTimestamps); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if gz != nil {
+		return gz.Close()
+	}
+	return nil
+}
+
+// writeTextLogLine writes a single line, optionally prefixed with its timestamp.
+func writeTextLogLine(w *bufio.Writer, line LogLine, timestamps bool) error {
+	if timestamps && !line.Timestamp.IsZero() {
+		if _, err := w.WriteString(line.Timestamp.Format(time.RFC3339Nano) + " "); err != nil {
+			return err
+		}
+	}
+	_, err := w.WriteString(line.Message + "\n")
+	return err
+}
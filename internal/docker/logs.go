@@ -0,0 +1,139 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Log stream names as reported in LogLine.Stream.
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// LogLine is a single line read from a container's log stream.
+type LogLine struct {
+	Stream    string
+	Timestamp time.Time
+	Message   string
+}
+
+// LogOptions controls which part of a container's log is streamed.
+type LogOptions struct {
+	Follow bool
+	Since  time.Time // Zero means from the beginning
+	Tty    bool      // Container was started with a TTY (stream is not multiplexed)
+}
+
+// StreamLogs reads the logs of a container line by line and calls fn for each line.
+// It returns when the stream ends (the container stopped or Follow is false)
+// or when ctx is cancelled.
+func (c *Client) StreamLogs(ctx context.Context, containerID string, opts LogOptions, fn func(LogLine)) error {
+	logOpts := container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Tail:       "all",
+		Timestamps: true,
+	}
+	if !opts.Since.IsZero() {
+		logOpts.Since = fmt.Sprintf("%d.%09d", opts.Since.Unix(), opts.Since.Nanosecond())
+	}
+
+	c.mu.RLock()
+	reader, err := c.cli.ContainerLogs(ctx, containerID, logOpts)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	bufReader := bufio.NewReader(reader)
+	if opts.Tty {
+		err = readRawLogs(bufReader, fn)
+	} else {
+		err = readMultiplexedLogs(bufReader, fn)
+	}
+
+	if err == io.EOF || ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// readMultiplexedLogs reads a stdout/stderr multiplexed log stream.
+// Docker logs have an 8-byte header for multiplexed streams.
+// Format: [1 byte stream type][3 bytes padding][4 bytes size (big-endian)]
+func readMultiplexedLogs(reader *bufio.Reader, fn func(LogLine)) error {
+	header := make([]byte, 8)
+	partial := map[string]string{}
+
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			for stream, rest := range partial {
+				if rest != "" {
+					fn(parseLogLine(stream, rest))
+				}
+			}
+			return err
+		}
+
+		stream := StreamStdout
+		if header[0] == 2 {
+			stream = StreamStderr
+		}
+
+		size := binary.BigEndian.Uint32(header[4:8])
+		if size == 0 {
+			continue
+		}
+
+		msg := make([]byte, size)
+		if _, err := io.ReadFull(reader, msg); err != nil {
+			return err
+		}
+
+		// Frames are not guaranteed to end on a line boundary
+		text := partial[stream] + string(msg)
+		for {
+			idx := strings.IndexByte(text, '\n')
+			if idx < 0 {
+				break
+			}
+			fn(parseLogLine(stream, text[:idx]))
+			text = text[idx+1:]
+		}
+		partial[stream] = text
+	}
+}
+
+// readRawLogs reads a log stream of a TTY container, which has no stream headers.
+func readRawLogs(reader *bufio.Reader, fn func(LogLine)) error {
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			fn(parseLogLine(StreamStdout, strings.TrimSuffix(line, "\n")))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// parseLogLine splits the RFC 3339 timestamp Docker prepends to each line.
+func parseLogLine(stream, line string) LogLine {
+	line = strings.TrimSuffix(line, "\r")
+	if ts, msg, ok := strings.Cut(line, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			return LogLine{Stream: stream, Timestamp: t, Message: msg}
+		}
+	}
+	return LogLine{Stream: stream, Message: line}
+}
@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LogEntry is a log line together with the continuation lines grouped under it,
+// such as the frames of a stack trace.
+type LogEntry struct {
+	LogLine
+	Continuation []LogLine
+}
+
+// Lines returns the first line followed by all continuation lines.
+func (e LogEntry) Lines() []LogLine {
+	return append([]LogLine{e.LogLine}, e.Continuation...)
+}
+
+// Text returns all lines of the entry joined by newlines.
+func (e LogEntry) Text() string {
+	if len(e.Continuation) == 0 {
+		return e.Message
+	}
+	var sb strings.Builder
+	sb.WriteString(e.Message)
+	for _, line := range e.Continuation {
+		sb.WriteByte('\n')
+		sb.WriteString(line.Message)
+	}
+	return sb.String()
+}
+
+// continuationPrefixes start lines that belong to the previous entry.
+var continuationPrefixes = []string{"Caused by", "Traceback"}
+
+// MultilineGrouper decides which log lines continue the previous entry.
+// Lines starting with whitespace, "Caused by" or "Traceback" are continuations,
+// as are lines matching an optional user-defined pattern.
+type MultilineGrouper struct {
+	enabled bool
+	pattern *regexp.Regexp
+}
+
+// NewMultilineGrouper creates a grouper. An empty pattern adds no extra rule.
+func NewMultilineGrouper(enabled bool, pattern string) (*MultilineGrouper, error) {
+	g := &MultilineGrouper{enabled: enabled}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		g.pattern = re
+	}
+	return g, nil
+}
+
+// IsContinuation reports whether a line should be joined to the previous entry.
+func (g *MultilineGrouper) IsContinuation(msg string) bool {
+	if g == nil || !g.enabled || msg == "" {
+		return false
+	}
+	if msg[0] == ' ' || msg[0] == '\t' {
+		return true
+	}
+	for _, prefix := range continuationPrefixes {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+	return g.pattern != nil && g.pattern.MatchString(msg)
+}
+
+// Append adds a line to entries, joining it to the last entry if it is a continuation.
+// Lines are only joined to entries of the same stream.
+func (g *MultilineGrouper) Append(entries []LogEntry, line LogLine) []LogEntry {
+	if n := len(entries); n > 0 && entries[n-1].Stream == line.Stream && line.Stream != "" && g.IsContinuation(line.Message) {
+		entries[n-1].Continuation = append(entries[n-1].Continuation, line)
+		return entries
+	}
+	return append(entries, LogEntry{LogLine: line})
+}
+
+// Group groups a sequence of log lines into entries.
+func (g *MultilineGrouper) Group(lines []LogLine) []LogEntry {
+	entries := make([]LogEntry, 0, len(lines))
+	for _, line := range lines {
+		entries = g.Append(entries, line)
+	}
+	return entries
+}
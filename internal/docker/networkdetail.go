@@ -0,0 +1,163 @@
+package docker
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/network"
+)
+
+// NetworkDetail is the full configuration of a network with its endpoints.
+type NetworkDetail struct {
+	Network
+	IPAMDriver  string
+	IPAMOptions map[string]string
+	Options     map[string]string // Driver options, e.g. the bridge name
+	Endpoints   []NetworkEndpoint
+}
+
+// NetworkEndpoint is a container connected to a network.
+type NetworkEndpoint struct {
+	ContainerID string // Short ID, matching Container.ID
+	Name        string
+	State       string
+	IPv4        string // CIDR, empty while the container is not running
+	IPv6        string // CIDR
+	MAC         string
+	Aliases     []string // Extra DNS names configured for the endpoint
+	DNSNames    []string // All names other containers on the network can resolve
+}
+
+// InspectNetwork returns the configuration of a network and the addresses,
+// MAC addresses and aliases of the containers connected to it. Stopped
+// containers are included without addresses.
+func (c *Client) InspectNetwork(ctx context.Context, id string) (NetworkDetail, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	inspect, err := c.cli.NetworkInspect(ctx, id, network.InspectOptions{})
+	if err != nil {
+		return NetworkDetail{}, err
+	}
+	containers, err := c.listContainers(ctx)
+	if err != nil {
+		return NetworkDetail{}, err
+	}
+
+	var users []Container
+	for _, ctr := range containers {
+		for _, n := range ctr.Networks {
+			if n.NetworkID == shortID(inspect.ID) {
+				users = append(users, ctr)
+				break
+			}
+		}
+	}
+
+	detail := NetworkDetail{
+		Network:     networkFromSummary(inspect, users),
+		IPAMDriver:  inspect.IPAM.Driver,
+		IPAMOptions: inspect.IPAM.Options,
+		Options:     inspect.Options,
+	}
+
+	// Endpoints are keyed by the full container ID
+	resources := make(map[string]network.EndpointResource, len(inspect.Containers))
+	for ctrID, res := range inspect.Containers {
+		resources[shortID(ctrID)] = res
+	}
+
+	for _, ctr := range users {
+		ep := NetworkEndpoint{
+			ContainerID: ctr.ID,
+			Name:        ctr.Name,
+			State:       ctr.State,
+		}
+		if res, ok := resources[ctr.ID]; ok {
+			ep.IPv4 = res.IPv4Address
+			ep.IPv6 = res.IPv6Address
+			ep.MAC = res.MacAddress
+		}
+		// Aliases are only part of the container's endpoint settings
+		if full, err := c.cli.ContainerInspect(ctx, ctr.ID); err == nil && full.NetworkSettings != nil {
+			if settings := full.NetworkSettings.Networks[inspect.Name]; settings != nil {
+				ep.Aliases = settings.Aliases
+				ep.DNSNames = settings.DNSNames
+				if ep.MAC == "" {
+					ep.MAC = settings.MacAddress
+				}
+			}
+		}
+		detail.Endpoints = append(detail.Endpoints, ep)
+	}
+
+	sort.Slice(detail.Endpoints, func(i, j int) bool {
+		return strings.ToLower(detail.Endpoints[i].Name) < strings.ToLower(detail.Endpoints[j].Name)
+	})
+	return detail, nil
+}
+
+// NetworkAliases returns the names containers can be resolved by on their
+// networks besides their name and ID, by container ID and network ID.
+// ContainerList does not report aliases, so containers are inspected; the
+// aliases of running containers are cached by endpoint, which changes when
+// a container is reconnected.
+func (c *Client) NetworkAliases(ctx context.Context, containers []Container) map[string]map[string][]string {
+	c.aliasMu.Lock()
+	defer c.aliasMu.Unlock()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cache := make(map[string][]string)
+	result := make(map[string]map[string][]string)
+	for _, ctr := range containers {
+		byNetwork := make(map[string][]string)
+		cached := len(ctr.Networks) > 0
+		for _, n := range ctr.Networks {
+			aliases, ok := c.aliases[n.Endpoint]
+			if n.Endpoint == "" || !ok {
+				cached = false
+				break
+			}
+			byNetwork[n.NetworkID] = aliases
+		}
+
+		if !cached {
+			full, err := c.cli.ContainerInspect(ctx, ctr.ID)
+			if err != nil || full.NetworkSettings == nil {
+				continue
+			}
+			for _, settings := range full.NetworkSettings.Networks {
+				if settings == nil {
+					continue
+				}
+				byNetwork[shortID(settings.NetworkID)] = endpointAliases(ctr, settings.Aliases, settings.DNSNames)
+			}
+		}
+
+		for _, n := range ctr.Networks {
+			if n.Endpoint != "" {
+				cache[n.Endpoint] = byNetwork[n.NetworkID]
+			}
+		}
+		result[ctr.ID] = byNetwork
+	}
+	// Entries of removed endpoints are dropped
+	c.aliases = cache
+	return result
+}
+
+// endpointAliases merges the aliases and DNS names of an endpoint, without the
+// container name and ID every container can be reached by anyway.
+func endpointAliases(ctr Container, aliases, dnsNames []string) []string {
+	var names []string
+	for _, name := range slices.Concat(aliases, dnsNames) {
+		if name == ctr.Name || name == ctr.ID || slices.Contains(names, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
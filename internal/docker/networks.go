@@ -2,20 +2,108 @@ package docker
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/netip"
 	"sort"
+	"strings"
+	"time"
 
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
 )
 
 // Network represents a Docker network with relevant information.
 type Network struct {
-	ID     string
-	Name   string
-	Driver string
-	Scope  string
+	ID         string
+	Name       string
+	Driver     string
+	Scope      string
+	Created    time.Time
+	Internal   bool // No access to the outside world
+	Attachable bool // Standalone containers may join the (swarm) network
+	EnableIPv6 bool
+	Labels     map[string]string
+	Subnets    []NetworkSubnet
+	Containers []Container // Containers connected to the network, running or not
 }
 
-// ListNetworks returns all networks.
+// NetworkSubnet is an IPAM pool of a network.
+type NetworkSubnet struct {
+	Subnet  string // CIDR, e.g. "172.20.0.0/16"
+	Gateway string
+	IPRange string // CIDR containers are assigned addresses from, empty for the whole subnet
+}
+
+// Predefined reports whether the network is one of those the daemon creates
+// itself, which cannot be removed.
+func (n Network) Predefined() bool {
+	switch n.Name {
+	case network.NetworkBridge, network.NetworkHost, network.NetworkNone:
+		return true
+	}
+	return false
+}
+
+// InUse reports whether any container is connected to the network.
+func (n Network) InUse() bool {
+	return len(n.Containers) > 0
+}
+
+// Project returns the compose project that created the network, if any.
+func (n Network) Project() string {
+	return n.Labels[composeProjectLabel]
+}
+
+// CreateNetworkOptions configures a new network.
+type CreateNetworkOptions struct {
+	Name       string
+	Driver     string // Defaults to bridge
+	Subnets    []NetworkSubnet
+	Internal   bool
+	Attachable bool
+	EnableIPv6 bool
+	Labels     map[string]string
+}
+
+// ConnectOptions configures how a container joins a network.
+type ConnectOptions struct {
+	Aliases []string // Additional DNS names of the container on the network
+	IPv4    string   // Static address, empty to let the daemon assign one
+	IPv6    string
+}
+
+// NetworkInUseError is returned when a network cannot be removed because
+// containers are connected to it.
+type NetworkInUseError struct {
+	Err        error
+	Containers []Container
+}
+
+func (e *NetworkInUseError) Error() string {
+	names := make([]string, len(e.Containers))
+	for i, ctr := range e.Containers {
+		names[i] = ctr.Name
+	}
+	if len(names) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("network is used by %s", strings.Join(names, ", "))
+}
+
+func (e *NetworkInUseError) Unwrap() error {
+	return e.Err
+}
+
+// shortID truncates a full object ID to the 12 characters Docker displays.
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// ListNetworks returns all networks with the containers connected to them.
 func (c *Client) ListNetworks(ctx context.Context) ([]Network, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -24,20 +112,20 @@ func (c *Client) ListNetworks(ctx context.Context) ([]Network, error) {
 	if err != nil {
 		return nil, err
 	}
+	containers, err := c.listContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	connected := make(map[string][]Container)
+	for _, ctr := range containers {
+		for _, n := range ctr.Networks {
+			connected[n.NetworkID] = append(connected[n.NetworkID], ctr)
+		}
+	}
 
 	result := make([]Network, 0, len(networks))
 	for _, net := range networks {
-		id := net.ID
-		if len(id) > 12 {
-			id = id[:12]
-		}
-
-		result = append(result, Network{
-			ID:     id,
-			Name:   net.Name,
-			Driver: net.Driver,
-			Scope:  net.Scope,
-		})
+		result = append(result, networkFromSummary(net, connected[shortID(net.ID)]))
 	}
 
 	// Sort by name
@@ -47,3 +135,140 @@ func (c *Client) ListNetworks(ctx context.Context) ([]Network, error) {
 
 	return result, nil
 }
+
+// networkFromSummary converts a network of the Docker API.
+func networkFromSummary(net network.Summary, containers []Container) Network {
+	n := Network{
+		ID:         shortID(net.ID),
+		Name:       net.Name,
+		Driver:     net.Driver,
+		Scope:      net.Scope,
+		Created:    net.Created,
+		Internal:   net.Internal,
+		Attachable: net.Attachable,
+		EnableIPv6: net.EnableIPv6,
+		Labels:     net.Labels,
+		Containers: containers,
+	}
+	for _, cfg := range net.IPAM.Config {
+		n.Subnets = append(n.Subnets, NetworkSubnet{
+			Subnet:  cfg.Subnet,
+			Gateway: cfg.Gateway,
+			IPRange: cfg.IPRange,
+		})
+	}
+	return n
+}
+
+// CreateNetwork creates a network and returns its short ID.
+func (c *Client) CreateNetwork(ctx context.Context, opts CreateNetworkOptions) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	create := network.CreateOptions{
+		Driver:     opts.Driver,
+		Internal:   opts.Internal,
+		Attachable: opts.Attachable,
+		EnableIPv6: &opts.EnableIPv6,
+		Labels:     opts.Labels,
+	}
+	if len(opts.Subnets) > 0 {
+		create.IPAM = &network.IPAM{}
+		for _, s := range opts.Subnets {
+			create.IPAM.Config = append(create.IPAM.Config, network.IPAMConfig{
+				Subnet:  s.Subnet,
+				Gateway: s.Gateway,
+				IPRange: s.IPRange,
+			})
+		}
+	}
+
+	resp, err := c.cli.NetworkCreate(ctx, opts.Name, create)
+	if err != nil {
+		return "", err
+	}
+	return shortID(resp.ID), nil
+}
+
+// RemoveNetwork removes a network. Networks that containers are connected to,
+// even stopped ones that would fail to start without it, are refused with a
+// *NetworkInUseError listing the containers.
+func (c *Client) RemoveNetwork(ctx context.Context, id string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	containers, err := c.listContainers(ctx)
+	if err != nil {
+		return err
+	}
+	var users []Container
+	for _, ctr := range containers {
+		for _, n := range ctr.Networks {
+			if n.NetworkID == shortID(id) || n.Name == id {
+				users = append(users, ctr)
+				break
+			}
+		}
+	}
+	if len(users) > 0 {
+		return &NetworkInUseError{Err: errors.New("network is in use"), Containers: users}
+	}
+
+	return c.cli.NetworkRemove(ctx, id)
+}
+
+// PruneNetworks removes all custom networks no container is connected to and
+// returns their names.
+func (c *Client) PruneNetworks(ctx context.Context) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	report, err := c.cli.NetworksPrune(ctx, filters.NewArgs())
+	if err != nil {
+		return nil, err
+	}
+	return report.NetworksDeleted, nil
+}
+
+// ConnectContainer connects a container to a network.
+func (c *Client) ConnectContainer(ctx context.Context, networkID, containerID string, opts ConnectOptions) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	settings := &network.EndpointSettings{Aliases: opts.Aliases}
+	if opts.IPv4 != "" || opts.IPv6 != "" {
+		settings.IPAMConfig = &network.EndpointIPAMConfig{
+			IPv4Address: opts.IPv4,
+			IPv6Address: opts.IPv6,
+		}
+	}
+	return c.cli.NetworkConnect(ctx, networkID, containerID, settings)
+}
+
+// DisconnectContainer disconnects a container from a network.
+func (c *Client) DisconnectContainer(ctx context.Context, networkID, containerID string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cli.NetworkDisconnect(ctx, networkID, containerID, false)
+}
+
+// OverlappingNetworks returns the networks with a subnet that overlaps subnet,
+// which must be in CIDR notation.
+func OverlappingNetworks(networks []Network, subnet string) ([]Network, error) {
+	prefix, err := netip.ParsePrefix(subnet)
+	if err != nil {
+		return nil, err
+	}
+
+	var overlapping []Network
+	for _, n := range networks {
+		for _, s := range n.Subnets {
+			other, err := netip.ParsePrefix(s.Subnet)
+			if err == nil && other.Overlaps(prefix) {
+				overlapping = append(overlapping, n)
+				break
+			}
+		}
+	}
+	return overlapping, nil
+}
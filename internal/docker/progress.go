@@ -0,0 +1,161 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// LayerProgress is the transfer state of a single image layer.
+type LayerProgress struct {
+	ID     string
+	Status string // Last status reported by the daemon, e.g. "Downloading"
+
+	Transferred   int64 // Bytes downloaded or uploaded so far
+	TransferTotal int64 // Size of the layer, 0 if not known yet
+	Extracted     int64 // Bytes extracted so far (pull only)
+	ExtractTotal  int64
+
+	Done bool
+}
+
+// TransferFraction returns the download or upload progress between 0 and 1.
+func (l LayerProgress) TransferFraction() float32 {
+	if l.Done || l.Transferred >= l.TransferTotal && l.TransferTotal > 0 {
+		return 1
+	}
+	if l.TransferTotal <= 0 {
+		return 0
+	}
+	return float32(l.Transferred) / float32(l.TransferTotal)
+}
+
+// ExtractFraction returns the extraction progress between 0 and 1.
+func (l LayerProgress) ExtractFraction() float32 {
+	if l.Done {
+		return 1
+	}
+	if l.ExtractTotal <= 0 {
+		return 0
+	}
+	return float32(l.Extracted) / float32(l.ExtractTotal)
+}
+
+// TransferProgress is the state of an image pull or push, built from the
+// JSON progress stream of the daemon.
+type TransferProgress struct {
+	Layers []LayerProgress
+	Status string // Last status without a layer ID, e.g. "Status: Downloaded newer image for nginx:latest"
+	Digest string // Manifest digest reported at the end of the transfer
+}
+
+// TotalBytes returns the bytes transferred and the total size of all layers with a known size.
+func (p TransferProgress) TotalBytes() (current, total int64) {
+	for _, l := range p.Layers {
+		if l.TransferTotal > 0 {
+			current += min(l.Transferred, l.TransferTotal)
+			total += l.TransferTotal
+		}
+	}
+	return current, total
+}
+
+// layerStatusDone lists the statuses after which a layer needs no more work.
+var layerStatusDone = map[string]bool{
+	"Pull complete":        true,
+	"Already exists":       true,
+	"Pushed":               true,
+	"Layer already exists": true,
+}
+
+// apply updates the progress with a message from the daemon.
+func (p *TransferProgress) apply(msg jsonmessage.JSONMessage) {
+	if msg.ID == "" || strings.HasPrefix(msg.Status, "Digest:") || strings.HasPrefix(msg.Status, "Status:") {
+		if digest, ok := strings.CutPrefix(msg.Status, "Digest: "); ok {
+			p.Digest = digest
+		} else if _, rest, ok := strings.Cut(msg.Status, ": digest: "); ok {
+			// Push result, e.g. "latest: digest: sha256:… size: 1570"
+			p.Digest, _, _ = strings.Cut(rest, " ")
+			p.Status = msg.Status
+		} else if msg.Status != "" {
+			p.Status = msg.Status
+		}
+		return
+	}
+
+	// Messages about the tag itself ("Pulling from library/nginx") are not layers
+	if strings.HasPrefix(msg.Status, "Pulling from") || strings.HasPrefix(msg.Status, "The push refers to") {
+		p.Status = msg.Status
+		return
+	}
+
+	layer := p.layer(msg.ID)
+	layer.Status = msg.Status
+	switch {
+	case layerStatusDone[msg.Status] || strings.HasPrefix(msg.Status, "Mounted from"):
+		layer.Done = true
+		if layer.TransferTotal > 0 {
+			layer.Transferred = layer.TransferTotal
+		}
+	case msg.Status == "Download complete" || msg.Status == "Verifying Checksum":
+		if layer.TransferTotal > 0 {
+			layer.Transferred = layer.TransferTotal
+		}
+	case msg.Progress != nil && msg.Status == "Extracting":
+		layer.Extracted = msg.Progress.Current
+		layer.ExtractTotal = msg.Progress.Total
+	case msg.Progress != nil:
+		layer.Transferred = msg.Progress.Current
+		if msg.Progress.Total > 0 {
+			layer.TransferTotal = msg.Progress.Total
+		}
+	}
+}
+
+// layer returns the progress entry for a layer ID, adding it if needed.
+func (p *TransferProgress) layer(id string) *LayerProgress {
+	for i := range p.Layers {
+		if p.Layers[i].ID == id {
+			return &p.Layers[i]
+		}
+	}
+	p.Layers = append(p.Layers, LayerProgress{ID: id})
+	return &p.Layers[len(p.Layers)-1]
+}
+
+// snapshot returns a copy that is safe to hand to another goroutine.
+func (p *TransferProgress) snapshot() TransferProgress {
+	cp := *p
+	cp.Layers = append([]LayerProgress(nil), p.Layers...)
+	return cp
+}
+
+// readTransferProgress decodes a JSON progress stream and calls fn after every message.
+// An error message in the stream is returned as an error.
+func readTransferProgress(r io.Reader, fn func(TransferProgress)) error {
+	var progress TransferProgress
+	dec := json.NewDecoder(r)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if msg.Error != nil {
+			return errors.New(msg.Error.Message)
+		}
+		if msg.ErrorMessage != "" {
+			return errors.New(msg.ErrorMessage)
+		}
+
+		progress.apply(msg)
+		if fn != nil {
+			fn(progress.snapshot())
+		}
+	}
+}
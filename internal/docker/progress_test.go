@@ -0,0 +1,170 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadTransferProgress(t *testing.T) {
+	tests := []struct {
+		name    string
+		stream  []string
+		updates int
+		err     string
+		check   func(t *testing.T, p TransferProgress)
+	}{
+		{
+			name: "per-layer totals",
+			stream: []string{
+				`{"status":"Pulling from library/app","id":"1.0"}`,
+				`{"status":"Pulling fs layer","id":"aaa"}`,
+				`{"status":"Pulling fs layer","id":"bbb"}`,
+				`{"status":"Downloading","progressDetail":{"current":100,"total":1000},"id":"aaa"}`,
+				`{"status":"Downloading","progressDetail":{"current":50,"total":500},"id":"bbb"}`,
+				`{"status":"Downloading","progressDetail":{"current":400,"total":1000},"id":"aaa"}`,
+				`{"status":"Download complete","progressDetail":{},"id":"bbb"}`,
+				`{"status":"Extracting","progressDetail":{"current":200,"total":500},"id":"bbb"}`,
+			},
+			updates: 8,
+			check: func(t *testing.T, p TransferProgress) {
+				if p.Status != "Pulling from library/app" {
+					t.Errorf("Status = %q", p.Status)
+				}
+				if len(p.Layers) != 2 {
+					t.Fatalf("got %d layers, want 2", len(p.Layers))
+				}
+				a, b := p.Layers[0], p.Layers[1]
+				if a.ID != "aaa" || a.Transferred != 400 || a.TransferTotal != 1000 || a.Done {
+					t.Errorf("layer aaa = %+v", a)
+				}
+				if b.ID != "bbb" || b.Transferred != 500 || b.TransferTotal != 500 || b.Extracted != 200 || b.ExtractTotal != 500 {
+					t.Errorf("layer bbb = %+v", b)
+				}
+				if f := b.TransferFraction(); f != 1 {
+					t.Errorf("bbb TransferFraction = %v, want 1", f)
+				}
+				if current, total := p.TotalBytes(); current != 900 || total != 1500 {
+					t.Errorf("TotalBytes = %d/%d, want 900/1500", current, total)
+				}
+			},
+		},
+		{
+			name: "already exists",
+			stream: []string{
+				`{"status":"Pulling from library/app","id":"1.0"}`,
+				`{"status":"Already exists","progressDetail":{},"id":"aaa"}`,
+				`{"status":"Pulling fs layer","id":"bbb"}`,
+				`{"status":"Downloading","progressDetail":{"current":10,"total":10},"id":"bbb"}`,
+				`{"status":"Pull complete","progressDetail":{},"id":"bbb"}`,
+				`{"status":"Digest: sha256:0123"}`,
+				`{"status":"Status: Downloaded newer image for app:1.0"}`,
+			},
+			updates: 7,
+			check: func(t *testing.T, p TransferProgress) {
+				a := p.Layers[0]
+				if !a.Done || a.Status != "Already exists" || a.TransferFraction() != 1 || a.ExtractFraction() != 1 {
+					t.Errorf("layer aaa = %+v", a)
+				}
+				if !p.Layers[1].Done {
+					t.Errorf("layer bbb is not done: %+v", p.Layers[1])
+				}
+				// Layers that already exist have no size and do not count
+				if current, total := p.TotalBytes(); current != 10 || total != 10 {
+					t.Errorf("TotalBytes = %d/%d, want 10/10", current, total)
+				}
+				if p.Digest != "sha256:0123" {
+					t.Errorf("Digest = %q", p.Digest)
+				}
+				if p.Status != "Status: Downloaded newer image for app:1.0" {
+					t.Errorf("Status = %q", p.Status)
+				}
+			},
+		},
+		{
+			name: "push",
+			stream: []string{
+				`{"status":"The push refers to repository [localhost:5000/app]"}`,
+				`{"status":"Preparing","progressDetail":{},"id":"aaa"}`,
+				`{"status":"Layer already exists","progressDetail":{},"id":"aaa"}`,
+				`{"status":"Pushing","progressDetail":{"current":512,"total":2048},"id":"bbb"}`,
+				`{"status":"Pushed","progressDetail":{},"id":"bbb"}`,
+				`{"status":"1.0: digest: sha256:4567 size: 1570"}`,
+			},
+			updates: 6,
+			check: func(t *testing.T, p TransferProgress) {
+				for _, l := range p.Layers {
+					if !l.Done {
+						t.Errorf("layer %s is not done: %+v", l.ID, l)
+					}
+				}
+				if current, total := p.TotalBytes(); current != 2048 || total != 2048 {
+					t.Errorf("TotalBytes = %d/%d, want 2048/2048", current, total)
+				}
+				if p.Digest != "sha256:4567" {
+					t.Errorf("Digest = %q", p.Digest)
+				}
+			},
+		},
+		{
+			name: "error mid-stream",
+			stream: []string{
+				`{"status":"Pulling from library/app","id":"1.0"}`,
+				`{"status":"Downloading","progressDetail":{"current":100,"total":1000},"id":"aaa"}`,
+				`{"errorDetail":{"message":"unexpected EOF"},"error":"unexpected EOF"}`,
+				`{"status":"Pull complete","progressDetail":{},"id":"aaa"}`,
+			},
+			updates: 2,
+			err:     "unexpected EOF",
+			check: func(t *testing.T, p TransferProgress) {
+				if len(p.Layers) != 1 || p.Layers[0].Done {
+					t.Errorf("layers after the error = %+v", p.Layers)
+				}
+			},
+		},
+		{
+			name: "error without detail",
+			stream: []string{
+				`{"error":"manifest unknown"}`,
+			},
+			err: "manifest unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var last TransferProgress
+			updates := 0
+			err := readTransferProgress(strings.NewReader(strings.Join(tt.stream, "\n")), func(p TransferProgress) {
+				last = p
+				updates++
+			})
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.err != "" && (err == nil || err.Error() != tt.err):
+				t.Fatalf("error = %v, want %q", err, tt.err)
+			}
+			if updates != tt.updates {
+				t.Errorf("got %d updates, want %d", updates, tt.updates)
+			}
+			if tt.check != nil {
+				tt.check(t, last)
+			}
+		})
+	}
+}
+
+func TestReadTransferProgressSnapshots(t *testing.T) {
+	stream := `{"status":"Downloading","progressDetail":{"current":1,"total":10},"id":"aaa"}
+{"status":"Downloading","progressDetail":{"current":5,"total":10},"id":"aaa"}`
+	var snapshots []TransferProgress
+	if err := readTransferProgress(strings.NewReader(stream), func(p TransferProgress) {
+		snapshots = append(snapshots, p)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Later messages must not change progress already handed out
+	if got := snapshots[0].Layers[0].Transferred; got != 1 {
+		t.Errorf("first snapshot changed to %d bytes", got)
+	}
+}
@@ -0,0 +1,116 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/docker/docker/api/types/image"
+	registrytypes "github.com/docker/docker/api/types/registry"
+
+	"github.com/tsukinoko-kun/harbor/internal/registry"
+)
+
+// PullOptions configures an image pull.
+type PullOptions struct {
+	// Platform selects a platform of a multi-arch image, e.g. "linux/arm64".
+	// Empty uses the platform of the daemon.
+	Platform string
+}
+
+// PullImage pulls an image and calls fn with the progress after every update.
+// Credentials stored for the image's registry are used automatically.
+// Cancel ctx to abort the pull.
+func (c *Client) PullImage(ctx context.Context, ref string, opts PullOptions, fn func(TransferProgress)) error {
+	auth, err := encodeRegistryAuth(ref)
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	rc, err := c.cli.ImagePull(ctx, ref, image.PullOptions{
+		Platform:     opts.Platform,
+		RegistryAuth: auth,
+	})
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	err = readTransferProgress(rc, fn)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// ImagePlatforms returns the platforms (e.g. "linux/amd64") an image is available for in its registry.
+func (c *Client) ImagePlatforms(ctx context.Context, ref string) ([]string, error) {
+	auth, err := encodeRegistryAuth(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	info, err := c.cli.DistributionInspect(ctx, ref, auth)
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var platforms []string
+	for _, p := range info.Platforms {
+		// Attestation manifests are listed as "unknown/unknown"
+		if p.OS == "" || p.OS == "unknown" {
+			continue
+		}
+		name := p.OS + "/" + p.Architecture
+		if p.Variant != "" {
+			name += "/" + p.Variant
+		}
+		if !seen[name] {
+			seen[name] = true
+			platforms = append(platforms, name)
+		}
+	}
+	if len(platforms) == 0 {
+		return nil, errors.New("registry did not report any platforms")
+	}
+	sort.Strings(platforms)
+	return platforms, nil
+}
+
+// encodeRegistryAuth returns the X-Registry-Auth header value for the registry
+// of an image reference, or an empty string if no credentials are stored.
+func encodeRegistryAuth(ref string) (string, error) {
+	creds, err := registry.LookupReference(ref)
+	if err != nil || creds.Empty() {
+		return "", err
+	}
+	return registrytypes.EncodeAuthConfig(registrytypes.AuthConfig{
+		Username:      creds.Username,
+		Password:      creds.Password,
+		IdentityToken: creds.IdentityToken,
+		ServerAddress: creds.ServerAddress,
+	})
+}
+
+// buildAuthConfigs returns the credentials of all registries for pulling base images in a build.
+func buildAuthConfigs() (map[string]registrytypes.AuthConfig, error) {
+	all, err := registry.AllCredentials()
+	if err != nil {
+		return nil, err
+	}
+	configs := make(map[string]registrytypes.AuthConfig, len(all))
+	for address, creds := range all {
+		configs[address] = registrytypes.AuthConfig{
+			Username:      creds.Username,
+			Password:      creds.Password,
+			IdentityToken: creds.IdentityToken,
+			ServerAddress: creds.ServerAddress,
+		}
+	}
+	return configs, nil
+}
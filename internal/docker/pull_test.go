@@ -0,0 +1,104 @@
+package docker
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	registrytypes "github.com/docker/docker/api/types/registry"
+)
+
+func TestPullImage(t *testing.T) {
+	d, c := newStandInDaemon(t)
+	d.images["localhost:5000/app:1.0"] = true
+
+	t.Run("anonymous", func(t *testing.T) {
+		writeDockerConfig(t, "")
+
+		var last TransferProgress
+		updates := 0
+		err := c.PullImage(context.Background(), "localhost:5000/app:1.0", PullOptions{}, func(p TransferProgress) {
+			last = p
+			updates++
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req := d.lastRequest()
+		if req.Query.Get("fromImage") != "localhost:5000/app" || req.Query.Get("tag") != "1.0" {
+			t.Errorf("pulled %s", req.Query.Encode())
+		}
+		if req.RawAuth != "" {
+			t.Errorf("sent credentials %q for a registry without any stored", req.RawAuth)
+		}
+		if updates != 9 {
+			t.Errorf("got %d updates, want 9", updates)
+		}
+		if len(last.Layers) != 2 || !last.Layers[0].Done || !last.Layers[1].Done {
+			t.Errorf("layers = %+v", last.Layers)
+		}
+		if current, total := last.TotalBytes(); current != 1024 || total != 1024 {
+			t.Errorf("TotalBytes = %d/%d, want 1024/1024", current, total)
+		}
+		if last.Digest != "sha256:"+strings.Repeat("a", 64) {
+			t.Errorf("Digest = %q", last.Digest)
+		}
+	})
+
+	t.Run("stored credentials", func(t *testing.T) {
+		auth := base64.StdEncoding.EncodeToString([]byte("alice:secret"))
+		writeDockerConfig(t, `{"auths":{"localhost:5000":{"auth":"`+auth+`"}}}`)
+		d.accounts["localhost:5000"] = registrytypes.AuthConfig{Username: "alice", Password: "secret"}
+		defer delete(d.accounts, "localhost:5000")
+
+		if err := c.PullImage(context.Background(), "localhost:5000/app:1.0", PullOptions{}, nil); err != nil {
+			t.Fatal(err)
+		}
+		req := d.lastRequest()
+		if req.Auth.Username != "alice" || req.Auth.Password != "secret" || req.Auth.ServerAddress != "localhost:5000" {
+			t.Errorf("sent credentials %+v", req.Auth)
+		}
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		writeDockerConfig(t, "")
+		d.accounts["localhost:5000"] = registrytypes.AuthConfig{Username: "alice", Password: "secret"}
+		defer delete(d.accounts, "localhost:5000")
+
+		err := c.PullImage(context.Background(), "localhost:5000/app:1.0", PullOptions{}, nil)
+		if err == nil || !strings.Contains(err.Error(), "authentication required") {
+			t.Fatalf("error = %v, want authentication required", err)
+		}
+	})
+
+	t.Run("platform", func(t *testing.T) {
+		writeDockerConfig(t, "")
+		if err := c.PullImage(context.Background(), "localhost:5000/app:1.0", PullOptions{Platform: "linux/arm64"}, nil); err != nil {
+			t.Fatal(err)
+		}
+		if got := d.lastRequest().Query.Get("platform"); got != "linux/arm64" {
+			t.Errorf("platform = %q", got)
+		}
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		writeDockerConfig(t, "")
+		err := c.PullImage(context.Background(), "localhost:5000/app:2.0", PullOptions{}, nil)
+		if err == nil || !strings.Contains(err.Error(), "manifest unknown") {
+			t.Fatalf("error = %v, want manifest unknown", err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		writeDockerConfig(t, "")
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := c.PullImage(ctx, "localhost:5000/app:1.0", PullOptions{}, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("error = %v, want context.Canceled", err)
+		}
+	})
+}
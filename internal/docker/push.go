@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/image"
+	registrytypes "github.com/docker/docker/api/types/registry"
+)
+
+// TagImage adds the tag target to the image source.
+func (c *Client) TagImage(ctx context.Context, source, target string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cli.ImageTag(ctx, source, target)
+}
+
+// PushImage pushes a tag to its registry and calls fn with the progress after every update.
+// Credentials stored for the registry are used automatically.
+// Cancel ctx to abort the push.
+func (c *Client) PushImage(ctx context.Context, ref string, fn func(TransferProgress)) error {
+	auth, err := encodeRegistryAuth(ref)
+	if err != nil {
+		return err
+	}
+	if auth == "" {
+		// The daemon rejects pushes without an X-Registry-Auth header, even for
+		// registries that need no credentials
+		if auth, err = registrytypes.EncodeAuthConfig(registrytypes.AuthConfig{}); err != nil {
+			return err
+		}
+	}
+
+	c.mu.RLock()
+	rc, err := c.cli.ImagePush(ctx, ref, image.PushOptions{RegistryAuth: auth})
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	err = readTransferProgress(rc, fn)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,136 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	registrytypes "github.com/docker/docker/api/types/registry"
+)
+
+// fakeCredentialHelper installs docker-credential-fake on PATH. It knows the
+// credentials of localhost:5000 and an identity token for localhost:5001.
+func fakeCredentialHelper(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential helper is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+read -r server
+[ "$1" = get ] || { echo "unknown command"; exit 1; }
+case "$server" in
+localhost:5000) echo '{"ServerURL":"localhost:5000","Username":"bob","Secret":"hunter2"}' ;;
+localhost:5001) echo '{"ServerURL":"localhost:5001","Username":"<token>","Secret":"refresh-token"}' ;;
+*) echo "credentials not found in native keychain"; exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "docker-credential-fake"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestPushImage(t *testing.T) {
+	d, c := newStandInDaemon(t)
+
+	t.Run("anonymous", func(t *testing.T) {
+		writeDockerConfig(t, "")
+
+		var last TransferProgress
+		if err := c.PushImage(context.Background(), "localhost:5000/app:1.0", func(p TransferProgress) {
+			last = p
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		req := d.lastRequest()
+		if req.Path != "/images/localhost:5000/app/push" || req.Query.Get("tag") != "1.0" {
+			t.Errorf("pushed %s?%s", req.Path, req.Query.Encode())
+		}
+		// An empty auth config, since the daemon refuses a missing header
+		if req.RawAuth == "" {
+			t.Error("sent no X-Registry-Auth header")
+		}
+		if req.Auth != (registrytypes.AuthConfig{}) {
+			t.Errorf("sent credentials %+v", req.Auth)
+		}
+		if !d.images["localhost:5000/app:1.0"] {
+			t.Error("the registry did not receive the image")
+		}
+		for _, l := range last.Layers {
+			if !l.Done {
+				t.Errorf("layer %s is not done: %+v", l.ID, l)
+			}
+		}
+		if last.Digest != "sha256:"+strings.Repeat("b", 64) {
+			t.Errorf("Digest = %q", last.Digest)
+		}
+	})
+
+	t.Run("anonymous to a registry requiring credentials", func(t *testing.T) {
+		writeDockerConfig(t, "")
+		d.accounts["localhost:5000"] = registrytypes.AuthConfig{Username: "bob", Password: "hunter2"}
+		defer delete(d.accounts, "localhost:5000")
+
+		err := c.PushImage(context.Background(), "localhost:5000/app:1.0", nil)
+		if err == nil || err.Error() != "unauthorized: authentication required" {
+			t.Fatalf("error = %v, want unauthorized: authentication required", err)
+		}
+	})
+
+	t.Run("credential helper", func(t *testing.T) {
+		fakeCredentialHelper(t)
+		writeDockerConfig(t, `{"credHelpers":{"localhost:5000":"fake"}}`)
+		d.accounts["localhost:5000"] = registrytypes.AuthConfig{Username: "bob", Password: "hunter2"}
+		defer delete(d.accounts, "localhost:5000")
+
+		if err := c.PushImage(context.Background(), "localhost:5000/app:2.0", nil); err != nil {
+			t.Fatal(err)
+		}
+		req := d.lastRequest()
+		if req.Auth.Username != "bob" || req.Auth.Password != "hunter2" || req.Auth.ServerAddress != "localhost:5000" {
+			t.Errorf("sent credentials %+v", req.Auth)
+		}
+	})
+
+	t.Run("identity token from the credential store", func(t *testing.T) {
+		fakeCredentialHelper(t)
+		writeDockerConfig(t, `{"credsStore":"fake"}`)
+		d.accounts["localhost:5001"] = registrytypes.AuthConfig{IdentityToken: "refresh-token"}
+		defer delete(d.accounts, "localhost:5001")
+
+		if err := c.PushImage(context.Background(), "localhost:5001/app:1.0", nil); err != nil {
+			t.Fatal(err)
+		}
+		req := d.lastRequest()
+		if req.Auth.IdentityToken != "refresh-token" || req.Auth.Username != "" || req.Auth.Password != "" {
+			t.Errorf("sent credentials %+v", req.Auth)
+		}
+	})
+
+	t.Run("credential helper without credentials", func(t *testing.T) {
+		fakeCredentialHelper(t)
+		writeDockerConfig(t, `{"credsStore":"fake"}`)
+
+		if err := c.PushImage(context.Background(), "localhost:5002/app:1.0", nil); err != nil {
+			t.Fatal(err)
+		}
+		if req := d.lastRequest(); req.RawAuth == "" || req.Auth != (registrytypes.AuthConfig{}) {
+			t.Errorf("sent X-Registry-Auth %q, want an empty auth config", req.RawAuth)
+		}
+	})
+
+	t.Run("broken credential helper", func(t *testing.T) {
+		writeDockerConfig(t, `{"credHelpers":{"localhost:5000":"missing"}}`)
+		t.Setenv("PATH", t.TempDir())
+
+		err := c.PushImage(context.Background(), "localhost:5000/app:1.0", nil)
+		if err == nil || !strings.Contains(err.Error(), "docker-credential-missing") {
+			t.Fatalf("error = %v, want the helper to be named", err)
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+)
+
+// RecreateContainer replaces a container with a new one created from the same
+// configuration, so it picks up the image its tag currently points to.
+// The container keeps its name and volumes, including anonymous ones, and is
+// started again if it was running.
+// If creating or starting the new container fails, the old one is restored.
+// It returns the short ID of the new container.
+func (c *Client) RecreateContainer(ctx context.Context, containerID string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.recreateContainer(ctx, containerID, true, nil)
+}
+
+// recreateContainer implements RecreateContainer. With upgrade, the new
+// container is created from the image the container's tag points to now, with
+// that image's defaults; otherwise from the exact image and configuration of
+// the old container. modify, if not nil, adjusts the host configuration of the
+// new container. c.mu must be held.
+func (c *Client) recreateContainer(ctx context.Context, containerID string, upgrade bool, modify func(hostConfig *container.HostConfig)) (string, error) {
+	info, err := c.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	cfg := *info.Config
+	if upgrade {
+		// The configuration of the old image, to tell its defaults from the container's own settings
+		oldImage, _, err := c.cli.ImageInspectWithRaw(ctx, info.Image)
+		if err != nil {
+			return "", fmt.Errorf("failed to inspect the image of the container: %w", err)
+		}
+		withoutImageDefaults(&cfg, oldImage.Config)
+	} else {
+		// The tag may point to another image by now
+		cfg.Image = info.Image
+	}
+	name := strings.TrimPrefix(info.Name, "/")
+	running := info.State != nil && info.State.Running
+
+	if running {
+		if err := c.cli.ContainerStop(ctx, info.ID, container.StopOptions{}); err != nil {
+			return "", err
+		}
+	}
+
+	// Move the old container aside so the new one can take its name
+	backup := fmt.Sprintf("%s-harbor-old-%s", name, info.ID[:12])
+	if err := c.cli.ContainerRename(ctx, info.ID, backup); err != nil {
+		c.restartAfterRecreate(info.ID, running)
+		return "", err
+	}
+
+	// A hostname that defaulted to the old container ID would otherwise stick
+	if cfg.Hostname == info.ID[:12] {
+		cfg.Hostname = ""
+	}
+
+	hostConfig := *info.HostConfig
+	keepVolumes(info, &hostConfig)
+	if modify != nil {
+		modify(&hostConfig)
+	}
+
+	created, err := c.cli.ContainerCreate(ctx, &cfg, &hostConfig, recreateNetworking(info), nil, name)
+	if err == nil && running {
+		if err = c.cli.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+			_ = c.cli.ContainerRemove(context.Background(), created.ID, container.RemoveOptions{Force: true})
+		}
+	}
+	if err != nil {
+		// Roll back to the old container
+		rollbackErr := c.cli.ContainerRename(context.Background(), info.ID, name)
+		c.restartAfterRecreate(info.ID, running)
+		return "", errors.Join(err, rollbackErr)
+	}
+
+	if err := c.cli.ContainerRemove(ctx, info.ID, container.RemoveOptions{}); err != nil {
+		return created.ID[:12], fmt.Errorf("recreated %s, but the old container %s could not be removed: %w", name, backup, err)
+	}
+	return created.ID[:12], nil
+}
+
+// restartAfterRecreate starts a container again after a failed recreation.
+func (c *Client) restartAfterRecreate(containerID string, running bool) {
+	if running {
+		_ = c.cli.ContainerStart(context.Background(), containerID, container.StartOptions{})
+	}
+}
+
+// withoutImageDefaults removes the settings a container inherited from its
+// image from cfg, so the new container gets the defaults of the new image.
+// Settings that differ from the old image were given explicitly and are kept.
+func withoutImageDefaults(cfg, image *container.Config) {
+	if image == nil {
+		return
+	}
+
+	inherited := make(map[string]bool, len(image.Env))
+	for _, env := range image.Env {
+		inherited[env] = true
+	}
+	var env []string
+	for _, e := range cfg.Env {
+		if !inherited[e] {
+			env = append(env, e)
+		}
+	}
+	cfg.Env = env
+
+	labels := make(map[string]string, len(cfg.Labels))
+	for key, value := range cfg.Labels {
+		if imageValue, ok := image.Labels[key]; !ok || imageValue != value {
+			labels[key] = value
+		}
+	}
+	cfg.Labels = labels
+
+	if slices.Equal(cfg.Cmd, image.Cmd) {
+		cfg.Cmd = nil
+	}
+	if slices.Equal(cfg.Entrypoint, image.Entrypoint) {
+		cfg.Entrypoint = nil
+	}
+	if cfg.WorkingDir == image.WorkingDir {
+		cfg.WorkingDir = ""
+	}
+	if cfg.User == image.User {
+		cfg.User = ""
+	}
+	if cfg.StopSignal == image.StopSignal {
+		cfg.StopSignal = ""
+	}
+	if reflect.DeepEqual(cfg.Healthcheck, image.Healthcheck) {
+		cfg.Healthcheck = nil
+	}
+	if maps.Equal(cfg.ExposedPorts, image.ExposedPorts) {
+		cfg.ExposedPorts = nil
+	}
+	if maps.Equal(cfg.Volumes, image.Volumes) {
+		cfg.Volumes = nil
+	}
+}
+
+// keepVolumes adds the volumes of a container that are not part of its host
+// configuration, i.e. anonymous volumes and those declared by the image, so
+// the new container mounts the same volumes instead of new empty ones.
+func keepVolumes(info types.ContainerJSON, hostConfig *container.HostConfig) {
+	mounted := make(map[string]bool)
+	for _, bind := range hostConfig.Binds {
+		// source:target[:options]
+		if parts := strings.Split(bind, ":"); len(parts) >= 2 {
+			mounted[parts[1]] = true
+		}
+	}
+	volumeAt := make(map[string]string)
+	for _, m := range info.Mounts {
+		if m.Type == mount.TypeVolume {
+			volumeAt[m.Destination] = m.Name
+		}
+	}
+
+	mounts := make([]mount.Mount, len(hostConfig.Mounts))
+	for i, m := range hostConfig.Mounts {
+		// --mount without a source creates an anonymous volume
+		if m.Type == mount.TypeVolume && m.Source == "" {
+			m.Source = volumeAt[m.Target]
+		}
+		mounted[m.Target] = true
+		mounts[i] = m
+	}
+	for _, m := range info.Mounts {
+		if m.Type != mount.TypeVolume || mounted[m.Destination] {
+			continue
+		}
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeVolume,
+			Source:   m.Name,
+			Target:   m.Destination,
+			ReadOnly: !m.RW,
+		})
+	}
+	hostConfig.Mounts = mounts
+}
+
+// recreateNetworking returns the network endpoints of a container without the
+// state the daemon assigned to it, e.g. its IP addresses and endpoint IDs.
+func recreateNetworking(info types.ContainerJSON) *network.NetworkingConfig {
+	if info.NetworkSettings == nil || len(info.NetworkSettings.Networks) == 0 {
+		return nil
+	}
+	endpoints := make(map[string]*network.EndpointSettings, len(info.NetworkSettings.Networks))
+	for name, ep := range info.NetworkSettings.Networks {
+		if ep == nil {
+			continue
+		}
+		aliases := make([]string, 0, len(ep.Aliases))
+		for _, alias := range ep.Aliases {
+			// The daemon adds the short container ID as an alias itself
+			if alias != info.ID[:12] {
+				aliases = append(aliases, alias)
+			}
+		}
+		endpoints[name] = &network.EndpointSettings{
+			IPAMConfig: ep.IPAMConfig,
+			Links:      ep.Links,
+			Aliases:    aliases,
+			DriverOpts: ep.DriverOpts,
+		}
+	}
+	return &network.NetworkingConfig{EndpointsConfig: endpoints}
+}
@@ -0,0 +1,113 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/tsukinoko-kun/harbor/internal/sbom"
+)
+
+// layerCatalog holds the package files and whiteouts found in a single layer.
+type layerCatalog struct {
+	packages  map[string][]sbom.Package // By path of the file listing them
+	whiteouts []string                  // Paths deleted from lower layers
+	opaque    []string                  // Directories whose lower contents are hidden
+	distro    string
+}
+
+// ImagePackages exports an image and catalogs the packages installed in its
+// final filesystem: OS packages, Go binaries, npm lockfiles and Python distributions.
+// progress, if not nil, is called with the number of bytes read from the daemon.
+func (c *Client) ImagePackages(ctx context.Context, ref string, progress func(int64)) (sbom.Inventory, error) {
+	layers := make(map[string]*layerCatalog)
+	order, err := c.walkImage(ctx, ref, func(layer string, hdr *tar.Header, content io.Reader) error {
+		lc, ok := layers[layer]
+		if !ok {
+			lc = &layerCatalog{packages: make(map[string][]sbom.Package)}
+			layers[layer] = lc
+		}
+
+		p := cleanLayerPath(hdr.Name)
+		dir, base := path.Split(p)
+		switch {
+		case base == whiteoutOpaque:
+			lc.opaque = append(lc.opaque, strings.TrimSuffix(dir, "/"))
+			return nil
+		case strings.HasPrefix(base, whiteoutPrefix):
+			lc.whiteouts = append(lc.whiteouts, dir+strings.TrimPrefix(base, whiteoutPrefix))
+			return nil
+		case hdr.Typeflag != tar.TypeReg:
+			return nil
+		case sbom.IsOSRelease(p):
+			lc.distro = sbom.ParseOSRelease(content)
+			return nil
+		}
+
+		executable := hdr.Mode&0o111 != 0
+		if !sbom.Wants(p, hdr.Size, executable) {
+			return nil
+		}
+		pkgs, err := sbom.Catalog(p, hdr.Size, executable, content)
+		if err != nil {
+			// A damaged database should not hide the packages of all other files
+			return nil
+		}
+		// An empty entry still hides the file of a lower layer
+		lc.packages[p] = pkgs
+		return nil
+	}, progress)
+	if err != nil {
+		return sbom.Inventory{}, err
+	}
+
+	// Apply the layers from the bottom so files of upper layers replace lower ones
+	files := make(map[string][]sbom.Package)
+	var distro string
+	for _, layer := range order {
+		lc := layers[layer]
+		if lc == nil {
+			continue
+		}
+		for _, dir := range lc.opaque {
+			removeUnder(files, dir)
+		}
+		for _, p := range lc.whiteouts {
+			delete(files, p)
+			removeUnder(files, p)
+		}
+		for p, pkgs := range lc.packages {
+			files[p] = pkgs
+		}
+		if lc.distro != "" {
+			distro = lc.distro
+		}
+	}
+
+	inv := sbom.Inventory{
+		Image:   ref,
+		Distro:  distro,
+		Created: time.Now(),
+	}
+	for _, pkgs := range files {
+		inv.Packages = append(inv.Packages, pkgs...)
+	}
+	sbom.Sort(inv.Packages)
+	return inv, nil
+}
+
+// removeUnder deletes all files below a directory.
+func removeUnder(files map[string][]sbom.Package, dir string) {
+	prefix := dir + "/"
+	if dir == "" {
+		prefix = ""
+	}
+	for p := range files {
+		if strings.HasPrefix(p, prefix) {
+			delete(files, p)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	registrytypes "github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
+)
+
+// standInDaemon answers the image pull and push endpoints of the Engine API
+// the way a daemon in front of a registry:2 container does, without Docker.
+type standInDaemon struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	images   map[string]bool                     // Tags the registry holds, e.g. "localhost:5000/app:1.0"
+	accounts map[string]registrytypes.AuthConfig // Required credentials by registry host; anonymous if missing
+	requests []standInRequest
+}
+
+// standInRequest is a request the stand-in daemon received.
+type standInRequest struct {
+	Path    string
+	Query   url.Values
+	RawAuth string // X-Registry-Auth header as sent
+	Auth    registrytypes.AuthConfig
+}
+
+// newStandInDaemon starts a stand-in daemon and returns a Client connected to it.
+func newStandInDaemon(t *testing.T) (*standInDaemon, *Client) {
+	t.Helper()
+	d := &standInDaemon{
+		t:        t,
+		images:   make(map[string]bool),
+		accounts: make(map[string]registrytypes.AuthConfig),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(d.serve))
+	t.Cleanup(srv.Close)
+
+	cli, err := client.NewClientWithOpts(
+		client.WithHost("tcp://"+srv.Listener.Addr().String()),
+		client.WithVersion(apiVersion),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = cli.Close() })
+	return d, &Client{cli: cli}
+}
+
+// lastRequest returns the latest request the daemon received.
+func (d *standInDaemon) lastRequest() standInRequest {
+	d.t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.requests) == 0 {
+		d.t.Fatal("the daemon received no request")
+	}
+	return d.requests[len(d.requests)-1]
+}
+
+func (d *standInDaemon) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v"+apiVersion)
+	req := standInRequest{Path: path, Query: r.URL.Query(), RawAuth: r.Header.Get(registrytypes.AuthHeader)}
+	if req.RawAuth != "" {
+		auth, err := registrytypes.DecodeAuthConfig(req.RawAuth)
+		if err != nil {
+			d.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		req.Auth = *auth
+	}
+	d.mu.Lock()
+	d.requests = append(d.requests, req)
+	d.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && path == "/images/create":
+		ref := req.Query.Get("fromImage") + ":" + req.Query.Get("tag")
+		d.pull(w, ref, req.Auth)
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "/images/") && strings.HasSuffix(path, "/push"):
+		name := strings.TrimSuffix(strings.TrimPrefix(path, "/images/"), "/push")
+		d.push(w, name+":"+req.Query.Get("tag"), req)
+	default:
+		d.writeError(w, http.StatusNotFound, "page not found")
+	}
+}
+
+// authorized reports whether auth satisfies the registry of ref.
+func (d *standInDaemon) authorized(ref string, auth registrytypes.AuthConfig) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	host, _, _ := strings.Cut(ref, "/")
+	want, ok := d.accounts[host]
+	if !ok {
+		return true
+	}
+	if want.IdentityToken != "" {
+		return auth.IdentityToken == want.IdentityToken
+	}
+	return auth.Username == want.Username && auth.Password == want.Password
+}
+
+func (d *standInDaemon) pull(w http.ResponseWriter, ref string, auth registrytypes.AuthConfig) {
+	if !d.authorized(ref, auth) {
+		d.writeError(w, http.StatusUnauthorized, "Head \"https://"+ref+"\": unauthorized: authentication required")
+		return
+	}
+	d.mu.Lock()
+	exists := d.images[ref]
+	d.mu.Unlock()
+	if !exists {
+		d.writeError(w, http.StatusNotFound, "manifest for "+ref+" not found: manifest unknown: manifest unknown")
+		return
+	}
+
+	_, tag, _ := strings.Cut(ref[strings.LastIndex(ref, "/")+1:], ":")
+	d.stream(w,
+		map[string]any{"status": "Pulling from " + strings.TrimSuffix(ref, ":"+tag), "id": tag},
+		map[string]any{"status": "Already exists", "progressDetail": map[string]any{}, "id": "aaa"},
+		map[string]any{"status": "Pulling fs layer", "progressDetail": map[string]any{}, "id": "bbb"},
+		map[string]any{"status": "Downloading", "progressDetail": map[string]any{"current": 512, "total": 1024}, "id": "bbb"},
+		map[string]any{"status": "Downloading", "progressDetail": map[string]any{"current": 1024, "total": 1024}, "id": "bbb"},
+		map[string]any{"status": "Extracting", "progressDetail": map[string]any{"current": 1024, "total": 1024}, "id": "bbb"},
+		map[string]any{"status": "Pull complete", "progressDetail": map[string]any{}, "id": "bbb"},
+		map[string]any{"status": "Digest: sha256:" + strings.Repeat("a", 64)},
+		map[string]any{"status": "Status: Downloaded newer image for " + ref},
+	)
+}
+
+func (d *standInDaemon) push(w http.ResponseWriter, ref string, req standInRequest) {
+	// The daemon refuses pushes without the header, even to open registries
+	if req.RawAuth == "" {
+		d.writeError(w, http.StatusBadRequest, "Bad parameters and missing X-Registry-Auth: invalid X-Registry-Auth header: EOF")
+		return
+	}
+	name, tag, _ := strings.Cut(ref[strings.LastIndex(ref, "/")+1:], ":")
+	repo := ref[:strings.LastIndex(ref, "/")+1] + name
+
+	messages := []any{
+		map[string]any{"status": "The push refers to repository [" + repo + "]"},
+		map[string]any{"status": "Preparing", "progressDetail": map[string]any{}, "id": "aaa"},
+		map[string]any{"status": "Preparing", "progressDetail": map[string]any{}, "id": "bbb"},
+	}
+	if !d.authorized(ref, req.Auth) {
+		// Authentication fails once the upload starts, after the response began
+		messages = append(messages, map[string]any{
+			"errorDetail": map[string]any{"message": "unauthorized: authentication required"},
+			"error":       "unauthorized: authentication required",
+		})
+		d.stream(w, messages...)
+		return
+	}
+	messages = append(messages,
+		map[string]any{"status": "Layer already exists", "progressDetail": map[string]any{}, "id": "aaa"},
+		map[string]any{"status": "Pushing", "progressDetail": map[string]any{"current": 2048, "total": 4096}, "id": "bbb"},
+		map[string]any{"status": "Pushed", "progressDetail": map[string]any{}, "id": "bbb"},
+		map[string]any{"status": fmt.Sprintf("%s: digest: sha256:%s size: 1570", tag, strings.Repeat("b", 64))},
+	)
+	d.mu.Lock()
+	d.images[ref] = true
+	d.mu.Unlock()
+	d.stream(w, messages...)
+}
+
+// stream writes JSON progress messages like the daemon does.
+func (d *standInDaemon) stream(w http.ResponseWriter, messages ...any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	for _, msg := range messages {
+		if err := enc.Encode(msg); err != nil {
+			d.t.Error(err)
+			return
+		}
+	}
+}
+
+func (d *standInDaemon) writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// writeDockerConfig points the Docker CLI config at a temporary file with the given content.
+func writeDockerConfig(t *testing.T, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	if content == "" {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
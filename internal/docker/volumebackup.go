@@ -0,0 +1,285 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/errdefs"
+)
+
+// BackupVolumeOptions configures a volume backup.
+type BackupVolumeOptions struct {
+	Volume         string
+	Path           string // Destination .tar.gz file
+	HelperImage    string // Image of the helper container, empty for the default
+	StopContainers bool   // Stop the running containers using the volume until the backup is done
+}
+
+// RestoreVolumeOptions configures restoring a backup into a volume.
+type RestoreVolumeOptions struct {
+	Volume         string // Created if it does not exist
+	Path           string // .tar or .tar.gz file with paths relative to the volume root, e.g. written by BackupVolume
+	HelperImage    string // Image of the helper container, empty for the default
+	Replace        bool   // Delete the current contents of the volume first
+	StopContainers bool   // Stop the running containers using the volume until the restore is done
+}
+
+// BackupVolume writes the contents of a volume into a gzip-compressed tarball
+// with paths relative to the volume root. The root itself is stored as "./"
+// so its owner and permissions are restored, e.g. for database data directories.
+// progress, if not nil, is called with the number of bytes read from the daemon.
+func (c *Client) BackupVolume(ctx context.Context, opts BackupVolumeOptions, progress func(int64)) (err error) {
+	img, err := c.helperImage(ctx, opts.HelperImage)
+	if err != nil {
+		return fmt.Errorf("no image for the helper container: %w", err)
+	}
+
+	if opts.StopContainers {
+		var stopped []string
+		stopped, err = c.stopVolumeUsers(ctx, opts.Volume)
+		if err != nil {
+			return err
+		}
+		// Assigns the named result, so failing to restart is reported
+		defer func() { err = errors.Join(err, c.startContainers(stopped)) }()
+	}
+
+	c.mu.RLock()
+	id, err := c.createVolumeHelper(ctx, opts.Volume, img, true, nil)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.removeHelper(id) }()
+
+	c.mu.RLock()
+	rc, _, err := c.cli.CopyFromContainer(ctx, id, helperVolumeTarget)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := os.Create(opts.Path)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	err = rebaseArchive(gz, &countingReader{r: rc, fn: progress}, func(name string) string {
+		_, rel, _ := strings.Cut(name, "/")
+		return "./" + rel
+	})
+	if err == nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(opts.Path)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(opts.Path)
+		return err
+	}
+	return nil
+}
+
+// rebaseArchive copies a tar archive from r to w, renaming every entry with
+// rename. The targets of hard links are entries too and are renamed alike.
+func rebaseArchive(w io.Writer, r io.Reader, rename func(name string) string) error {
+	tw := tar.NewWriter(w)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		hdr.Name = rename(hdr.Name)
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname = rename(hdr.Linkname)
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// intoHelperVolume renames an entry relative to the volume root, such as
+// "./data/file", to the path of the helper mount relative to "/". Extracting
+// at "/" rather than into the mount point lets the daemon apply the owner and
+// permissions of the root entry to the volume root. Cleaning the path keeps
+// entries such as "../x" inside the volume.
+func intoHelperVolume(name string) string {
+	rel := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if rel == "" {
+		return strings.TrimPrefix(helperVolumeTarget, "/") + "/"
+	}
+	return path.Join(strings.TrimPrefix(helperVolumeTarget, "/"), rel)
+}
+
+// openArchive opens a tar archive that may be gzip-compressed.
+func openArchive(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(2)
+	if len(head) == 2 && head[0] == 0x1f && head[1] == 0x8b {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
+
+// RestoreVolume extracts a tarball, which may be gzip-compressed, into a volume, creating the volume if needed.
+// Ownership and permissions stored in the tarball are kept. progress, if not
+// nil, is called with the bytes sent and the size of the tarball.
+func (c *Client) RestoreVolume(ctx context.Context, opts RestoreVolumeOptions, progress func(sent, total int64)) (err error) {
+	f, err := os.Open(opts.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	img, err := c.helperImage(ctx, opts.HelperImage)
+	if err != nil {
+		return fmt.Errorf("no image for the helper container: %w", err)
+	}
+
+	if err := c.ensureVolume(ctx, opts.Volume); err != nil {
+		return err
+	}
+
+	if opts.StopContainers {
+		var stopped []string
+		stopped, err = c.stopVolumeUsers(ctx, opts.Volume)
+		if err != nil {
+			return err
+		}
+		// Assigns the named result, so failing to restart is reported
+		defer func() { err = errors.Join(err, c.startContainers(stopped)) }()
+	}
+
+	var cmd []string
+	if opts.Replace {
+		cmd = []string{"find", helperVolumeTarget, "-mindepth", "1", "-delete"}
+	}
+	c.mu.RLock()
+	id, err := c.createVolumeHelper(ctx, opts.Volume, img, false, cmd)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.removeHelper(id) }()
+
+	if opts.Replace {
+		if err := c.runHelper(ctx, id); err != nil {
+			return fmt.Errorf("failed to clear the volume: %w", err)
+		}
+	}
+
+	var report func(int64)
+	if progress != nil {
+		report = func(n int64) { progress(n, info.Size()) }
+	}
+
+	archive, err := openArchive(&countingReader{r: f, fn: report})
+	if err != nil {
+		return err
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(rebaseArchive(pw, archive, intoHelperVolume))
+	}()
+	defer pr.Close()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cli.CopyToContainer(ctx, id, "/", pr, container.CopyToContainerOptions{})
+}
+
+// ensureVolume creates a volume with default options if it does not exist.
+func (c *Client) ensureVolume(ctx context.Context, name string) error {
+	c.mu.RLock()
+	_, err := c.cli.VolumeInspect(ctx, name)
+	c.mu.RUnlock()
+	if err == nil || !errdefs.IsNotFound(err) {
+		return err
+	}
+	_, err = c.CreateVolume(ctx, CreateVolumeOptions{Name: name})
+	return err
+}
+
+// runHelper starts a helper container and waits for its command to succeed.
+func (c *Client) runHelper(ctx context.Context, id string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	waitC, errC := c.cli.ContainerWait(ctx, id, container.WaitConditionNextExit)
+	if err := c.cli.ContainerStart(ctx, id, container.StartOptions{}); err != nil {
+		return err
+	}
+	select {
+	case res := <-waitC:
+		if res.Error != nil {
+			return errors.New(res.Error.Message)
+		}
+		if res.StatusCode != 0 {
+			return fmt.Errorf("helper exited with status %d", res.StatusCode)
+		}
+		return nil
+	case err := <-errC:
+		return err
+	}
+}
+
+// stopVolumeUsers stops the running containers mounting a volume and returns
+// their IDs. If stopping one fails, the ones already stopped are started again.
+func (c *Client) stopVolumeUsers(ctx context.Context, name string) ([]string, error) {
+	c.mu.RLock()
+	users, err := c.volumeUsers(ctx, name)
+	c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var stopped []string
+	for _, ctr := range users {
+		if ctr.State != "running" {
+			continue
+		}
+		if err := c.StopContainer(ctx, ctr.ID); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to stop %s: %w", ctr.Name, err), c.startContainers(stopped))
+		}
+		stopped = append(stopped, ctr.ID)
+	}
+	return stopped, nil
+}
+
+// startContainers starts containers stopped by stopVolumeUsers, even if the
+// operation in between was cancelled.
+func (c *Client) startContainers(ids []string) error {
+	var errs []error
+	for _, id := range ids {
+		if err := c.StartContainer(context.Background(), id); err != nil {
+			errs = append(errs, fmt.Errorf("failed to restart %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
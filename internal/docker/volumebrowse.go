@@ -0,0 +1,439 @@
+package docker
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// VolumeEntry is a file or directory in a volume.
+type VolumeEntry struct {
+	Name       string
+	Path       string // Absolute path inside the volume, e.g. "/data/db"
+	Size       int64  // For directories the size of their contents, -1 if unknown
+	Mode       fs.FileMode
+	ModTime    time.Time
+	LinkTarget string // Target of a symbolic link
+}
+
+// IsDir reports whether the entry is a directory.
+func (e VolumeEntry) IsDir() bool {
+	return e.Mode.IsDir()
+}
+
+// IsLink reports whether the entry is a symbolic link.
+func (e VolumeEntry) IsLink() bool {
+	return e.Mode&fs.ModeSymlink != 0
+}
+
+// VolumeBrowser reads the contents of a volume. On Linux it reads the
+// mountpoint directly when it is accessible; otherwise it lists directories
+// by exec in a helper container that mounts the volume read-only and reads
+// files through the archive API. Close must be called to remove the helper
+// container.
+type VolumeBrowser struct {
+	c        *Client
+	volume   string
+	root     string // Mountpoint read directly, empty when using the helper
+	helperID string
+	image    string // Image of the helper container
+}
+
+// BrowseVolume prepares reading the contents of a volume. helperImage is the
+// image used for the helper container; if empty, a local busybox or alpine
+// image is used, and busybox is pulled if neither exists.
+func (c *Client) BrowseVolume(ctx context.Context, vol Volume, helperImage string) (*VolumeBrowser, error) {
+	b := &VolumeBrowser{c: c, volume: vol.Name}
+
+	// Reading the mountpoint needs a daemon on this machine and usually root
+	if runtime.GOOS == "linux" && vol.Mountpoint != "" {
+		if _, err := os.ReadDir(vol.Mountpoint); err == nil {
+			b.root = vol.Mountpoint
+			return b, nil
+		}
+	}
+
+	img, err := c.helperImage(ctx, helperImage)
+	if err != nil {
+		return nil, fmt.Errorf("no image for the helper container: %w", err)
+	}
+	b.image = img
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	// The helper idles until Close removes it, so directories can be listed by exec
+	id, err := c.createVolumeHelper(ctx, vol.Name, img, true, []string{"tail", "-f", "/dev/null"})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cli.ContainerStart(ctx, id, container.StartOptions{}); err != nil {
+		_ = c.cli.ContainerRemove(context.Background(), id, container.RemoveOptions{Force: true})
+		return nil, err
+	}
+	b.helperID = id
+	return b, nil
+}
+
+// Direct reports whether the volume is read from its mountpoint instead of through a helper container.
+func (b *VolumeBrowser) Direct() bool {
+	return b.root != ""
+}
+
+// Source describes how the volume is read, e.g. the mountpoint or the helper image.
+func (b *VolumeBrowser) Source() string {
+	if b.Direct() {
+		return b.root
+	}
+	return "helper container from " + b.image
+}
+
+// Close removes the helper container.
+func (b *VolumeBrowser) Close(ctx context.Context) error {
+	if b.helperID == "" {
+		return nil
+	}
+	b.c.mu.RLock()
+	defer b.c.mu.RUnlock()
+	return b.c.cli.ContainerRemove(ctx, b.helperID, container.RemoveOptions{Force: true})
+}
+
+// cleanVolumePath normalizes a path inside the volume to an absolute slash path.
+func cleanVolumePath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// localPath maps a path inside the volume to the mountpoint. Symbolic links
+// are resolved and must not lead out of the volume.
+func (b *VolumeBrowser) localPath(p string) (string, error) {
+	full := filepath.Join(b.root, filepath.FromSlash(cleanVolumePath(p)))
+	resolved, err := filepath.EvalSymlinks(full)
+	if err != nil {
+		return "", err
+	}
+	root, err := filepath.EvalSymlinks(b.root)
+	if err != nil {
+		return "", err
+	}
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s points outside of the volume", p)
+	}
+	return resolved, nil
+}
+
+// List returns the entries of a directory, directories first. The size of
+// subdirectories is not computed.
+func (b *VolumeBrowser) List(ctx context.Context, dir string) ([]VolumeEntry, error) {
+	dir = cleanVolumePath(dir)
+	var entries []VolumeEntry
+	var err error
+	if b.Direct() {
+		entries, err = b.listLocal(dir)
+	} else {
+		entries, err = b.listHelper(ctx, dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].IsDir() != entries[j].IsDir() {
+			return entries[i].IsDir()
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+func (b *VolumeBrowser) listLocal(dir string) ([]VolumeEntry, error) {
+	full, err := b.localPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	dirEntries, err := os.ReadDir(full)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]VolumeEntry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		info, err := de.Info()
+		if err != nil {
+			continue // Removed since reading the directory
+		}
+		entry := VolumeEntry{
+			Name:    de.Name(),
+			Path:    path.Join(dir, de.Name()),
+			Size:    info.Size(),
+			Mode:    info.Mode(),
+			ModTime: info.ModTime(),
+		}
+		switch {
+		case info.IsDir():
+			entry.Size = -1
+		case info.Mode()&fs.ModeSymlink != 0:
+			entry.LinkTarget, _ = os.Readlink(filepath.Join(full, de.Name()))
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// listScript prints the entries of the directory $1 as "entry <raw mode in
+// hex> <size> <mtime> <name>" lines, each symbolic link followed by a "link
+// <name>" line and a line holding its target.
+const listScript = `cd "$1" || exit 1
+set -- * .[!.]* ..?*
+stat -c 'entry %f %s %Y %n' -- "$@" 2>/dev/null
+for f do [ -L "$f" ] && printf 'link %s\n%s\n' "$f" "$(readlink -- "$f")"; done
+exit 0
+`
+
+// listHelper lists a directory by running listScript in the helper container,
+// so only the directory's own entries are read, never their contents.
+func (b *VolumeBrowser) listHelper(ctx context.Context, dir string) ([]VolumeEntry, error) {
+	b.c.mu.RLock()
+	stat, err := b.c.cli.ContainerStatPath(ctx, b.helperID, helperPath(dir))
+	b.c.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if !stat.Mode.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	output, err := b.c.execOutput(ctx, b.helperID, []string{"sh", "-c", listScript, "sh", helperPath(dir)})
+	if err != nil {
+		return nil, err
+	}
+	return parseListOutput(dir, output), nil
+}
+
+// parseListOutput reads the output of listScript for dir. Lines that do not
+// parse, e.g. of names containing line breaks, are skipped.
+func parseListOutput(dir string, output []byte) []VolumeEntry {
+	var entries []VolumeEntry
+	byName := make(map[string]int)
+	lines := strings.Split(string(output), "\n")
+	for i := 0; i < len(lines); i++ {
+		keyword, rest, _ := strings.Cut(lines[i], " ")
+		switch keyword {
+		case "entry":
+			fields := strings.SplitN(rest, " ", 4)
+			if len(fields) != 4 || fields[3] == "" {
+				continue
+			}
+			mode, err1 := strconv.ParseUint(fields[0], 16, 32)
+			size, err2 := strconv.ParseInt(fields[1], 10, 64)
+			mtime, err3 := strconv.ParseInt(fields[2], 10, 64)
+			if err1 != nil || err2 != nil || err3 != nil {
+				continue
+			}
+			entry := VolumeEntry{
+				Name:    fields[3],
+				Path:    path.Join(dir, fields[3]),
+				Size:    size,
+				Mode:    unixFileMode(uint32(mode)),
+				ModTime: time.Unix(mtime, 0),
+			}
+			if entry.IsDir() {
+				entry.Size = -1
+			}
+			byName[entry.Name] = len(entries)
+			entries = append(entries, entry)
+		case "link":
+			if i+1 < len(lines) {
+				i++
+				if j, ok := byName[rest]; ok {
+					entries[j].LinkTarget = lines[i]
+				}
+			}
+		}
+	}
+	return entries
+}
+
+// unixFileMode converts a raw st_mode to a file mode.
+func unixFileMode(mode uint32) fs.FileMode {
+	m := fs.FileMode(mode & 0o777)
+	switch mode & 0o170000 {
+	case 0o040000:
+		m |= fs.ModeDir
+	case 0o120000:
+		m |= fs.ModeSymlink
+	case 0o010000:
+		m |= fs.ModeNamedPipe
+	case 0o140000:
+		m |= fs.ModeSocket
+	case 0o020000:
+		m |= fs.ModeDevice | fs.ModeCharDevice
+	case 0o060000:
+		m |= fs.ModeDevice
+	}
+	if mode&0o4000 != 0 {
+		m |= fs.ModeSetuid
+	}
+	if mode&0o2000 != 0 {
+		m |= fs.ModeSetgid
+	}
+	if mode&0o1000 != 0 {
+		m |= fs.ModeSticky
+	}
+	return m
+}
+
+// ReadFile returns up to limit bytes of a regular file and whether it is longer.
+func (b *VolumeBrowser) ReadFile(ctx context.Context, p string, limit int64) ([]byte, bool, error) {
+	var r io.Reader
+	if b.Direct() {
+		full, err := b.localPath(p)
+		if err != nil {
+			return nil, false, err
+		}
+		f, err := os.Open(full)
+		if err != nil {
+			return nil, false, err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return nil, false, err
+		}
+		if !info.Mode().IsRegular() {
+			return nil, false, fmt.Errorf("%s is not a regular file", p)
+		}
+		r = f
+	} else {
+		b.c.mu.RLock()
+		defer b.c.mu.RUnlock()
+		rc, _, err := b.c.cli.CopyFromContainer(ctx, b.helperID, helperPath(p))
+		if err != nil {
+			return nil, false, err
+		}
+		defer rc.Close()
+		tr := tar.NewReader(rc)
+		hdr, err := tr.Next()
+		if err != nil {
+			return nil, false, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, false, fmt.Errorf("%s is not a regular file", p)
+		}
+		r = tr
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(data)) > limit {
+		return data[:limit], true, nil
+	}
+	return data, false, nil
+}
+
+// Download writes a file to w, or a tar archive of a directory.
+func (b *VolumeBrowser) Download(ctx context.Context, entry VolumeEntry, w io.Writer) error {
+	if b.Direct() {
+		full, err := b.localPath(entry.Path)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return writeDirArchive(w, full)
+		}
+		f, err := os.Open(full)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	}
+
+	b.c.mu.RLock()
+	defer b.c.mu.RUnlock()
+	rc, _, err := b.c.cli.CopyFromContainer(ctx, b.helperID, helperPath(entry.Path))
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if entry.IsDir() {
+		// The archive API already returns a tarball of the directory
+		_, err = io.Copy(w, rc)
+		return err
+	}
+	tr := tar.NewReader(rc)
+	hdr, err := tr.Next()
+	if err != nil {
+		return err
+	}
+	if hdr.Typeflag != tar.TypeReg {
+		return fmt.Errorf("%s is not a regular file", entry.Path)
+	}
+	_, err = io.Copy(w, tr)
+	return err
+}
+
+// writeDirArchive writes a tar archive of dir, named after its base name like
+// the archive API does. Symbolic links are stored as links, not followed.
+func writeDirArchive(w io.Writer, dir string) error {
+	tw := tar.NewWriter(w)
+	base := filepath.Base(dir)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(base, filepath.ToSlash(rel))
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.CopyN(tw, f, hdr.Size)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,169 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
+)
+
+// CloneVolumeOptions configures copying a volume into a new one.
+type CloneVolumeOptions struct {
+	Source      string
+	Target      CreateVolumeOptions // The new volume; its name must not be taken
+	HelperImage string              // Image of the helper containers, empty for the default
+}
+
+// CloneVolume copies all data of a volume, including owners and permissions,
+// into a new volume. Containers running with the source mounted could write to
+// it during the copy, so they are refused with a *VolumeInUseError.
+// progress, if not nil, is called with the number of bytes copied.
+func (c *Client) CloneVolume(ctx context.Context, opts CloneVolumeOptions, progress func(int64)) (Volume, error) {
+	if opts.Target.Name == "" {
+		return Volume{}, errors.New("the new volume needs a name")
+	}
+	if err := c.checkCloneSource(ctx, opts.Source, opts.Target.Name); err != nil {
+		return Volume{}, err
+	}
+
+	img, err := c.helperImage(ctx, opts.HelperImage)
+	if err != nil {
+		return Volume{}, fmt.Errorf("no image for the helper containers: %w", err)
+	}
+
+	target, err := c.CreateVolume(ctx, opts.Target)
+	if err != nil {
+		return Volume{}, err
+	}
+	if err := c.copyVolume(ctx, opts.Source, target.Name, img, progress); err != nil {
+		// Do not leave a partial copy behind
+		c.mu.RLock()
+		_ = c.cli.VolumeRemove(context.Background(), target.Name, true)
+		c.mu.RUnlock()
+		return Volume{}, err
+	}
+	return target, nil
+}
+
+// checkCloneSource makes sure no running container uses the source and the target name is free.
+func (c *Client) checkCloneSource(ctx context.Context, source, target string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	users, err := c.volumeUsers(ctx, source)
+	if err != nil {
+		return err
+	}
+	var running []Container
+	for _, ctr := range users {
+		if ctr.State == "running" {
+			running = append(running, ctr)
+		}
+	}
+	if len(running) > 0 {
+		return &VolumeInUseError{Err: errors.New("volume is in use"), Containers: running}
+	}
+
+	_, err = c.cli.VolumeInspect(ctx, target)
+	switch {
+	case err == nil:
+		return fmt.Errorf("volume %s already exists", target)
+	case !errdefs.IsNotFound(err):
+		return err
+	}
+	return nil
+}
+
+// copyVolume streams the contents of one volume into another through two helper containers.
+func (c *Client) copyVolume(ctx context.Context, source, target, img string, progress func(int64)) error {
+	c.mu.RLock()
+	srcID, err := c.createVolumeHelper(ctx, source, img, true, nil)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.removeHelper(srcID) }()
+
+	c.mu.RLock()
+	dstID, err := c.createVolumeHelper(ctx, target, img, false, nil)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.removeHelper(dstID) }()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	rc, _, err := c.cli.CopyFromContainer(ctx, srcID, helperVolumeTarget)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// Both helpers mount their volume at the same path, so the archive of the
+	// source mount point extracts at "/" onto the target, root entry included
+	return c.cli.CopyToContainer(ctx, dstID, "/", &countingReader{r: rc, fn: progress}, container.CopyToContainerOptions{})
+}
+
+// RenameVolume clones a volume under a new name, recreates the containers
+// using it so they mount the copy from the exact image they ran, and removes
+// the original. Containers running with the volume mounted are refused with
+// a *VolumeInUseError. If a container cannot be recreated, the original
+// volume is kept.
+func (c *Client) RenameVolume(ctx context.Context, opts CloneVolumeOptions, progress func(int64)) (Volume, error) {
+	target, err := c.CloneVolume(ctx, opts, progress)
+	if err != nil {
+		return Volume{}, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	users, err := c.volumeUsers(ctx, opts.Source)
+	if err != nil {
+		return target, fmt.Errorf("copied to %s, but %w", target.Name, err)
+	}
+	var errs []error
+	for _, ctr := range users {
+		if _, err := c.recreateContainer(ctx, ctr.ID, false, repointVolume(opts.Source, target.Name)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ctr.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return target, fmt.Errorf("copied to %s and kept %s, because containers could not be switched over: %w", target.Name, opts.Source, errors.Join(errs...))
+	}
+
+	if err := c.cli.VolumeRemove(ctx, opts.Source, false); err != nil {
+		return target, fmt.Errorf("copied to %s, but %s could not be removed: %w", target.Name, opts.Source, err)
+	}
+	return target, nil
+}
+
+// repointVolume returns a recreateContainer modifier that mounts volume to
+// instead of from at the same place. Volumes declared by the image are part
+// of the host config's mounts by then; see keepVolumes.
+func repointVolume(from, to string) func(*container.HostConfig) {
+	return func(hostConfig *container.HostConfig) {
+		binds := make([]string, len(hostConfig.Binds))
+		for i, bind := range hostConfig.Binds {
+			if source, rest, ok := strings.Cut(bind, ":"); ok && source == from {
+				bind = to + ":" + rest
+			}
+			binds[i] = bind
+		}
+		hostConfig.Binds = binds
+
+		mounts := make([]mount.Mount, len(hostConfig.Mounts))
+		for i, m := range hostConfig.Mounts {
+			if m.Type == mount.TypeVolume && m.Source == from {
+				m.Source = to
+			}
+			mounts[i] = m
+		}
+		hostConfig.Mounts = mounts
+	}
+}
@@ -2,11 +2,19 @@ package docker
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strings"
 
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
 )
 
+// anonymousVolumeLabel marks volumes the daemon created without a name, e.g. for VOLUME instructions.
+const anonymousVolumeLabel = "com.docker.volume.anonymous"
+
 // Volume represents a Docker volume with relevant information.
 type Volume struct {
 	Name       string
@@ -14,9 +22,79 @@ type Volume struct {
 	Mountpoint string
 	CreatedAt  string
 	Labels     map[string]string
+	Containers []Container // Containers mounting the volume, running or not
+
+	// Disk usage from VolumeDiskUsage, -1 until known or if the driver does not report it
+	Size     int64
+	RefCount int64
+}
+
+// VolumeUsage is the disk usage of a volume as reported by /system/df.
+type VolumeUsage struct {
+	Size     int64 // Bytes used, -1 if the driver does not report it
+	RefCount int64 // Number of containers referencing the volume
+}
+
+// InUse reports whether any container mounts the volume.
+func (v Volume) InUse() bool {
+	return len(v.Containers) > 0
+}
+
+// Anonymous reports whether the daemon created the volume without a name.
+func (v Volume) Anonymous() bool {
+	_, ok := v.Labels[anonymousVolumeLabel]
+	return ok
+}
+
+// Project returns the compose project that created the volume, or an empty string.
+func (v Volume) Project() string {
+	return v.Labels[composeProjectLabel]
+}
+
+// Projects returns the compose projects of the containers using the volume.
+func (v Volume) Projects() []string {
+	seen := make(map[string]bool)
+	var projects []string
+	for _, ctr := range v.Containers {
+		if ctr.Project != "" && !seen[ctr.Project] {
+			seen[ctr.Project] = true
+			projects = append(projects, ctr.Project)
+		}
+	}
+	sort.Strings(projects)
+	return projects
+}
+
+// CreateVolumeOptions configures a new volume.
+type CreateVolumeOptions struct {
+	Name       string // Empty lets the daemon generate a name
+	Driver     string // Empty uses the "local" driver
+	DriverOpts map[string]string
+	Labels     map[string]string
+}
+
+// VolumeInUseError is returned when a volume cannot be removed because containers use it.
+type VolumeInUseError struct {
+	Err        error
+	Containers []Container
+}
+
+func (e *VolumeInUseError) Error() string {
+	names := make([]string, len(e.Containers))
+	for i, ctr := range e.Containers {
+		names[i] = ctr.Name
+	}
+	if len(names) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("volume is used by %s", strings.Join(names, ", "))
+}
+
+func (e *VolumeInUseError) Unwrap() error {
+	return e.Err
 }
 
-// ListVolumes returns all volumes.
+// ListVolumes returns all volumes with the containers using them.
 func (c *Client) ListVolumes(ctx context.Context) ([]Volume, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -25,6 +103,16 @@ func (c *Client) ListVolumes(ctx context.Context) ([]Volume, error) {
 	if err != nil {
 		return nil, err
 	}
+	containers, err := c.listContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	usedBy := make(map[string][]Container)
+	for _, ctr := range containers {
+		for _, name := range ctr.Volumes {
+			usedBy[name] = append(usedBy[name], ctr)
+		}
+	}
 
 	result := make([]Volume, 0, len(resp.Volumes))
 	for _, vol := range resp.Volumes {
@@ -34,6 +122,9 @@ func (c *Client) ListVolumes(ctx context.Context) ([]Volume, error) {
 			Mountpoint: vol.Mountpoint,
 			CreatedAt:  vol.CreatedAt,
 			Labels:     vol.Labels,
+			Containers: usedBy[vol.Name],
+			Size:       -1,
+			RefCount:   -1,
 		})
 	}
 
@@ -44,3 +135,137 @@ func (c *Client) ListVolumes(ctx context.Context) ([]Volume, error) {
 
 	return result, nil
 }
+
+// VolumeDiskUsage returns the disk usage of all volumes by name. The daemon
+// computes the sizes by walking the volumes, so this can take a while.
+func (c *Client) VolumeDiskUsage(ctx context.Context) (map[string]VolumeUsage, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	du, err := c.cli.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.VolumeObject}})
+	if err != nil {
+		return nil, err
+	}
+	usage := make(map[string]VolumeUsage, len(du.Volumes))
+	for _, vol := range du.Volumes {
+		u := VolumeUsage{Size: -1, RefCount: -1}
+		if vol.UsageData != nil {
+			u = VolumeUsage{Size: vol.UsageData.Size, RefCount: vol.UsageData.RefCount}
+		}
+		usage[vol.Name] = u
+	}
+	return usage, nil
+}
+
+// ApplyVolumeUsage returns a copy of volumes with their sizes and reference counts set.
+func ApplyVolumeUsage(volumes []Volume, usage map[string]VolumeUsage) []Volume {
+	result := make([]Volume, len(volumes))
+	for i, vol := range volumes {
+		if u, ok := usage[vol.Name]; ok {
+			vol.Size = u.Size
+			vol.RefCount = u.RefCount
+		}
+		result[i] = vol
+	}
+	return result
+}
+
+// CreateVolume creates a volume and returns it.
+func (c *Client) CreateVolume(ctx context.Context, opts CreateVolumeOptions) (Volume, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	vol, err := c.cli.VolumeCreate(ctx, volume.CreateOptions{
+		Name:       opts.Name,
+		Driver:     opts.Driver,
+		DriverOpts: opts.DriverOpts,
+		Labels:     opts.Labels,
+	})
+	if err != nil {
+		return Volume{}, err
+	}
+	return Volume{
+		Name:       vol.Name,
+		Driver:     vol.Driver,
+		Mountpoint: vol.Mountpoint,
+		CreatedAt:  vol.CreatedAt,
+		Labels:     vol.Labels,
+		Size:       -1,
+		RefCount:   -1,
+	}, nil
+}
+
+// RemoveVolume removes a volume. Volumes used by containers, even stopped
+// ones, are refused with a *VolumeInUseError listing the containers.
+func (c *Client) RemoveVolume(ctx context.Context, name string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if users, err := c.volumeUsers(ctx, name); err != nil {
+		return err
+	} else if len(users) > 0 {
+		return &VolumeInUseError{Err: errdefs.Conflict(fmt.Errorf("volume %s is in use", name)), Containers: users}
+	}
+
+	err := c.cli.VolumeRemove(ctx, name, false)
+	if errdefs.IsConflict(err) {
+		// A container started using the volume in the meantime
+		if users, listErr := c.volumeUsers(ctx, name); listErr == nil && len(users) > 0 {
+			return &VolumeInUseError{Err: err, Containers: users}
+		}
+	}
+	return err
+}
+
+// volumeUsers returns the containers mounting a volume. c.mu must be held.
+func (c *Client) volumeUsers(ctx context.Context, name string) ([]Container, error) {
+	containers, err := c.listContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var users []Container
+	for _, ctr := range containers {
+		for _, v := range ctr.Volumes {
+			if v == name {
+				users = append(users, ctr)
+				break
+			}
+		}
+	}
+	return users, nil
+}
+
+// PruneVolumes removes volumes no container uses. By default the daemon only
+// removes anonymous volumes; all includes named ones, such as those left
+// behind by removed compose projects.
+func (c *Client) PruneVolumes(ctx context.Context, all bool) (PruneReport, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	args := filters.NewArgs()
+	if all {
+		args.Add("all", "true")
+	}
+	report, err := c.cli.VolumesPrune(ctx, args)
+	if err != nil {
+		return PruneReport{}, err
+	}
+	return PruneReport{Deleted: len(report.VolumesDeleted), SpaceReclaimed: report.SpaceReclaimed}, nil
+}
+
+// VolumePruneCandidates returns the volumes a prune would remove: unused
+// anonymous volumes, or all unused volumes if all is true.
+func (c *Client) VolumePruneCandidates(ctx context.Context, all bool) ([]Volume, error) {
+	volumes, err := c.ListVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Volume
+	for _, vol := range volumes {
+		if vol.InUse() || !all && !vol.Anonymous() {
+			continue
+		}
+		candidates = append(candidates, vol)
+	}
+	return candidates, nil
+}
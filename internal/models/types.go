@@ -8,6 +8,7 @@ const (
 	ViewImages
 	ViewVolumes
 	ViewNetworks
+	ViewTopology
 	ViewSettings
 )
 
@@ -22,6 +23,8 @@ func (v View) String() string {
 		return "Volumes"
 	case ViewNetworks:
 		return "Networks"
+	case ViewTopology:
+		return "Topology"
 	case ViewSettings:
 		return "Settings"
 	default:
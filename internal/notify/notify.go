@@ -0,0 +1,19 @@
+// Package notify shows desktop notifications using the platform's native mechanism.
+package notify
+
+import "errors"
+
+// ErrUnsupported is returned when desktop notifications are not available on this platform.
+var ErrUnsupported = errors.New("desktop notifications are not supported on this platform")
+
+// Send shows a desktop notification with the given title and body.
+// Callers should fall back to an in-app notification when it returns an error.
+func Send(title, body string) error {
+	return send(title, body)
+}
+
+// send is implemented in platform-specific files:
+// - notify_linux.go for Linux (freedesktop notifications over D-Bus)
+// - notify_darwin.go for macOS
+// - notify_other.go for all other platforms
+// func send(title, body string) error
@@ -0,0 +1,34 @@
+//go:build linux
+
+package notify
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	notificationsService   = "org.freedesktop.Notifications"
+	notificationsPath      = "/org/freedesktop/Notifications"
+	notificationsInterface = "org.freedesktop.Notifications"
+)
+
+// send calls Notify on the freedesktop notifications D-Bus interface.
+func send(title, body string) error {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+
+	obj := conn.Object(notificationsService, notificationsPath)
+	call := obj.Call(notificationsInterface+".Notify", 0,
+		"Harbor",                  // app_name
+		uint32(0),                 // replaces_id
+		"",                        // app_icon
+		title,                     // summary
+		body,                      // body
+		[]string{},                // actions
+		map[string]dbus.Variant{}, // hints
+		int32(-1),                 // expire_timeout (server default)
+	)
+	return call.Err
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package notify
+
+// send is not implemented; callers fall back to in-app notifications.
+func send(title, body string) error {
+	return ErrUnsupported
+}
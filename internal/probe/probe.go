@@ -0,0 +1,122 @@
+// Package probe checks in the background whether published ports answer HTTP.
+package probe
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// interval is how long a result is reused before the port is probed again.
+	interval = 10 * time.Second
+
+	// timeout limits a single probe, including reading the response headers.
+	timeout = 3 * time.Second
+
+	// dialTimeout is shorter so ports nothing listens on are told apart from slow servers.
+	dialTimeout = 2 * time.Second
+)
+
+// State is the outcome of a probe.
+type State int
+
+const (
+	StatePending     State = iota // Not probed yet
+	StateHTTP                     // The port answered HTTP; see Result.StatusCode
+	StateNotHTTP                  // The port accepted the connection but did not answer HTTP
+	StateUnreachable              // Nothing accepted the connection
+)
+
+// Result is the latest outcome of probing a port.
+type Result struct {
+	State      State
+	StatusCode int    // HTTP status code, for StateHTTP
+	Err        string // Why the probe failed, for StateNotHTTP and StateUnreachable
+}
+
+// entry is the cached result of an address.
+type entry struct {
+	result  Result
+	checked time.Time
+	running bool
+}
+
+// Prober probes HTTP ports in the background and caches the results.
+type Prober struct {
+	client   *http.Client
+	onResult func() // Called after each probe, e.g. to redraw
+
+	mu      sync.Mutex
+	entries map[string]*entry // By host:port
+}
+
+// New creates a prober. onResult, if not nil, is called from a background
+// goroutine whenever a probe finishes.
+func New(onResult func()) *Prober {
+	return &Prober{
+		client: &http.Client{
+			Timeout: timeout,
+			// No proxy and no kept-alive connections to the probed services
+			Transport: &http.Transport{
+				DialContext:       (&net.Dialer{Timeout: dialTimeout}).DialContext,
+				DisableKeepAlives: true,
+			},
+			// Report the redirect itself instead of where it leads
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		onResult: onResult,
+		entries:  make(map[string]*entry),
+	}
+}
+
+// Result returns the latest result for host:port and starts a new probe in
+// the background if there is none or it is outdated.
+func (p *Prober) Result(host string, port uint16) Result {
+	addr := net.JoinHostPort(host, strconv.Itoa(int(port)))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e, ok := p.entries[addr]
+	if !ok {
+		e = &entry{}
+		p.entries[addr] = e
+	}
+	if !e.running && time.Since(e.checked) > interval {
+		e.running = true
+		go p.probe(addr, e)
+	}
+	return e.result
+}
+
+// probe requests the root path of addr and records the outcome.
+func (p *Prober) probe(addr string, e *entry) {
+	result := Result{State: StateHTTP}
+	resp, err := p.client.Get("http://" + addr + "/")
+	if err != nil {
+		result = Result{State: StateNotHTTP, Err: err.Error()}
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			result.State = StateUnreachable
+		}
+	} else {
+		result.StatusCode = resp.StatusCode
+		_ = resp.Body.Close()
+	}
+
+	p.mu.Lock()
+	e.result = result
+	e.checked = time.Now()
+	e.running = false
+	p.mu.Unlock()
+
+	if p.onResult != nil {
+		p.onResult()
+	}
+}
@@ -0,0 +1,181 @@
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+)
+
+// DockerHubHost is the registry host of images without an explicit registry.
+const DockerHubHost = "docker.io"
+
+// dockerHubAuthKey is the key the Docker CLI uses for Docker Hub in config.json.
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// Credentials are the credentials stored for a registry.
+type Credentials struct {
+	ServerAddress string
+	Username      string
+	Password      string
+	IdentityToken string
+}
+
+// Empty reports whether no credentials are set.
+func (c Credentials) Empty() bool {
+	return c.Username == "" && c.Password == "" && c.IdentityToken == ""
+}
+
+// configFile is the part of the Docker CLI config file used by Harbor.
+type configFile struct {
+	Auths       map[string]authEntry `json:"auths"`
+	CredsStore  string               `json:"credsStore,omitempty"`
+	CredHelpers map[string]string    `json:"credHelpers,omitempty"`
+}
+
+// authEntry is a single entry of the "auths" section of config.json.
+type authEntry struct {
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+}
+
+// ConfigPath returns the path of the Docker CLI config file.
+// It respects the DOCKER_CONFIG environment variable.
+func ConfigPath() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".docker", "config.json"), nil
+}
+
+// loadConfig reads the Docker CLI config file. A missing file is not an error.
+func loadConfig() (*configFile, error) {
+	path, err := ConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &configFile{}, nil
+		}
+		return nil, err
+	}
+
+	var cfg configFile
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// HostFromReference returns the registry host of an image reference,
+// e.g. "docker.io" for "postgres:16" or "ghcr.io" for "ghcr.io/org/app".
+func HostFromReference(ref string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", err
+	}
+	return reference.Domain(named), nil
+}
+
+// NormalizeHost strips the scheme and path from a registry address and maps
+// the various Docker Hub addresses to DockerHubHost.
+func NormalizeHost(address string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(address, "https://"), "http://")
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return DockerHubHost
+	}
+	return host
+}
+
+// serverAddress returns the address under which the Docker CLI stores credentials for host.
+func serverAddress(host string) string {
+	if NormalizeHost(host) == DockerHubHost {
+		return dockerHubAuthKey
+	}
+	return host
+}
+
+// Lookup returns the stored credentials for a registry host. Like the Docker
+// CLI it asks the credential helper configured for the host (credHelpers) or
+// the default credential store (credsStore) before reading the "auths" section.
+// If no credentials are stored, empty credentials and no error are returned.
+func Lookup(host string) (Credentials, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	host = NormalizeHost(host)
+	if helper := cfg.helperFor(host); helper != "" {
+		creds, err := helperGet(helper, serverAddress(host))
+		if err != nil {
+			return Credentials{}, err
+		}
+		if !creds.Empty() {
+			return creds, nil
+		}
+	}
+
+	for key, entry := range cfg.Auths {
+		if NormalizeHost(key) != host {
+			continue
+		}
+		creds, err := decodeAuthEntry(entry)
+		if err != nil {
+			return Credentials{}, err
+		}
+		creds.ServerAddress = serverAddress(host)
+		return creds, nil
+	}
+	return Credentials{}, nil
+}
+
+// helperFor returns the name of the credential helper used for a host, or "".
+func (cfg *configFile) helperFor(host string) string {
+	for key, helper := range cfg.CredHelpers {
+		if NormalizeHost(key) == host {
+			return helper
+		}
+	}
+	return cfg.CredsStore
+}
+
+// LookupReference returns the stored credentials for the registry of an image reference.
+func LookupReference(ref string) (Credentials, error) {
+	host, err := HostFromReference(ref)
+	if err != nil {
+		return Credentials{}, err
+	}
+	return Lookup(host)
+}
+
+// decodeAuthEntry decodes the base64 "user:password" auth field of a config entry.
+func decodeAuthEntry(entry authEntry) (Credentials, error) {
+	creds := Credentials{IdentityToken: entry.IdentityToken}
+	if entry.Auth == "" {
+		return creds, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+	if err != nil {
+		return Credentials{}, err
+	}
+	user, password, _ := strings.Cut(string(decoded), ":")
+	creds.Username = user
+	creds.Password = password
+	return creds, nil
+}
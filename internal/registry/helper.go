@@ -0,0 +1,89 @@
+package registry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// helperTimeout limits how long a credential helper may run, since some
+// of them show an unlock prompt.
+const helperTimeout = time.Minute
+
+// helperTokenUsername is the username helpers report for identity tokens.
+const helperTokenUsername = "<token>"
+
+// helperNotFound is the message helpers print when they have no credentials for a server.
+const helperNotFound = "credentials not found in native keychain"
+
+// helperCredentials is the JSON format of the docker-credential-helpers protocol.
+type helperCredentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// helperGet asks the credential helper docker-credential-<helper> for the
+// credentials of a server. Missing credentials are not an error.
+func helperGet(helper, server string) (Credentials, error) {
+	out, err := runHelper(helper, "get", strings.NewReader(server))
+	if err != nil {
+		if strings.Contains(err.Error(), helperNotFound) {
+			return Credentials{}, nil
+		}
+		return Credentials{}, err
+	}
+
+	var hc helperCredentials
+	if err := json.Unmarshal(out, &hc); err != nil {
+		return Credentials{}, fmt.Errorf("docker-credential-%s: %w", helper, err)
+	}
+
+	creds := Credentials{ServerAddress: server}
+	if hc.Username == helperTokenUsername {
+		creds.IdentityToken = hc.Secret
+	} else {
+		creds.Username = hc.Username
+		creds.Password = hc.Secret
+	}
+	return creds, nil
+}
+
+// runHelper runs a credential helper command with input on stdin and returns its stdout.
+// Errors include the message the helper printed.
+func runHelper(helper, command string, input *strings.Reader) ([]byte, error) {
+	cmd := exec.Command("docker-credential-"+helper, command)
+	cmd.Stdin = input
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			// Helpers print their error message to stdout
+			msg := strings.TrimSpace(stdout.String())
+			if msg == "" {
+				msg = strings.TrimSpace(stderr.String())
+			}
+			if msg == "" {
+				return nil, fmt.Errorf("docker-credential-%s: %w", helper, err)
+			}
+			return nil, fmt.Errorf("docker-credential-%s: %s", helper, msg)
+		}
+		return stdout.Bytes(), nil
+	case <-time.After(helperTimeout):
+		_ = cmd.Process.Kill()
+		return nil, errors.New("docker-credential-" + helper + " did not respond")
+	}
+}
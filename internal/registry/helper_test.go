@@ -0,0 +1,71 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// installHelper puts a fake docker-credential-<name> running script on PATH.
+func installHelper(t *testing.T, name, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake credential helpers are shell scripts")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "docker-credential-"+name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestHelperGet(t *testing.T) {
+	installHelper(t, "fake", `read -r server
+[ "$1" = get ] || { echo "unknown command $1"; exit 1; }
+case "$server" in
+localhost:5000) echo '{"ServerURL":"localhost:5000","Username":"bob","Secret":"hunter2"}' ;;
+localhost:5001) echo '{"ServerURL":"localhost:5001","Username":"<token>","Secret":"refresh-token"}' ;;
+localhost:5002) echo 'not json' ;;
+localhost:5003) echo 'keychain is locked' >&2; exit 1 ;;
+*) echo "credentials not found in native keychain"; exit 1 ;;
+esac
+`)
+
+	tests := []struct {
+		server string
+		want   Credentials
+		err    string
+	}{
+		{server: "localhost:5000", want: Credentials{ServerAddress: "localhost:5000", Username: "bob", Password: "hunter2"}},
+		{server: "localhost:5001", want: Credentials{ServerAddress: "localhost:5001", IdentityToken: "refresh-token"}},
+		{server: "localhost:5002", err: "docker-credential-fake: invalid character"},
+		{server: "localhost:5003", err: "docker-credential-fake: keychain is locked"},
+		{server: "unknown.example"}, // Not found is not an error
+	}
+	for _, tt := range tests {
+		t.Run(tt.server, func(t *testing.T) {
+			got, err := helperGet("fake", tt.server)
+			if tt.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+					t.Fatalf("error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunHelperMissing(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if _, err := runHelper("missing", "get", strings.NewReader("localhost:5000")); err == nil {
+		t.Fatal("running a missing helper succeeded")
+	}
+}
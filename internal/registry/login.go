@@ -0,0 +1,264 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrUnauthorized is returned when a registry rejects the credentials.
+var ErrUnauthorized = errors.New("registry rejected the username or password")
+
+// dockerHubEndpoint is the API host of Docker Hub.
+const dockerHubEndpoint = "registry-1.docker.io"
+
+// loginClient is used for all registry API requests.
+var loginClient = &http.Client{Timeout: 30 * time.Second}
+
+// challenge is a parsed WWW-Authenticate header.
+type challenge struct {
+	scheme string // "bearer" or "basic"
+	params map[string]string
+}
+
+// tokenResponse is the answer of a token endpoint.
+type tokenResponse struct {
+	Token        string `json:"token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// apiHost returns the host serving the registry API for a registry host.
+func apiHost(host string) string {
+	if NormalizeHost(host) == DockerHubHost {
+		return dockerHubEndpoint
+	}
+	return host
+}
+
+// insecureHost reports whether the daemon talks plain HTTP to a registry by
+// default, which is the case for registries on the local machine.
+func insecureHost(host string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if hostname == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(hostname)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ping checks that host serves the registry API and returns the base URL
+// under which it is reachable along with the authentication challenge, if any.
+func ping(ctx context.Context, host string) (*url.URL, *challenge, error) {
+	schemes := []string{"https"}
+	if insecureHost(host) {
+		schemes = append(schemes, "http")
+	}
+
+	var lastErr error
+	for _, scheme := range schemes {
+		base := &url.URL{Scheme: scheme, Host: apiHost(host), Path: "/v2/"}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base.String(), nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		resp, err := loginClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return base, nil, nil
+		case http.StatusUnauthorized:
+			c := parseChallenge(resp.Header.Get("WWW-Authenticate"))
+			if c == nil {
+				return nil, nil, errors.New("registry requires authentication but sent no challenge")
+			}
+			return base, c, nil
+		default:
+			lastErr = fmt.Errorf("%s does not look like a registry (HTTP %d)", host, resp.StatusCode)
+		}
+	}
+	return nil, nil, lastErr
+}
+
+// Login validates credentials against the registry API of host. For registries
+// using token authentication it returns the identity (refresh) token issued by
+// the token server, if any.
+func Login(ctx context.Context, host, username, password string) (Credentials, error) {
+	host = NormalizeHost(host)
+	creds := Credentials{
+		ServerAddress: serverAddress(host),
+		Username:      username,
+		Password:      password,
+	}
+
+	base, c, err := ping(ctx, host)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if c == nil {
+		// The registry needs no authentication; store the credentials anyway
+		return creds, nil
+	}
+
+	switch c.scheme {
+	case "basic":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, base.String(), nil)
+		if err != nil {
+			return Credentials{}, err
+		}
+		req.SetBasicAuth(username, password)
+		resp, err := loginClient.Do(req)
+		if err != nil {
+			return Credentials{}, err
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusUnauthorized {
+			return Credentials{}, ErrUnauthorized
+		}
+		if resp.StatusCode != http.StatusOK {
+			return Credentials{}, fmt.Errorf("registry returned HTTP %d", resp.StatusCode)
+		}
+		return creds, nil
+	case "bearer":
+		token, err := fetchToken(ctx, c, Credentials{Username: username, Password: password}, "")
+		if err != nil {
+			return Credentials{}, err
+		}
+		if token.RefreshToken != "" {
+			creds.Password = ""
+			creds.IdentityToken = token.RefreshToken
+		}
+		return creds, nil
+	default:
+		return Credentials{}, fmt.Errorf("unsupported authentication scheme %q", c.scheme)
+	}
+}
+
+// tokenClientID is the OAuth client ID tokens are requested for. Refresh
+// tokens stored by "docker login" were issued to the Docker CLI's client ID,
+// and registries may only accept them from the client they were issued to.
+const tokenClientID = "docker"
+
+// fetchToken requests a token from the realm of a bearer challenge. Credentials
+// with an identity token use the OAuth refresh flow, others basic auth;
+// empty credentials request an anonymous token. scope may be empty for login.
+func fetchToken(ctx context.Context, c *challenge, creds Credentials, scope string) (tokenResponse, error) {
+	realm, err := url.Parse(c.params["realm"])
+	if err != nil || realm.Scheme == "" {
+		return tokenResponse{}, errors.New("registry sent an invalid token realm")
+	}
+
+	var req *http.Request
+	if creds.IdentityToken != "" {
+		form := url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {creds.IdentityToken},
+			"service":       {c.params["service"]},
+			"client_id":     {tokenClientID},
+		}
+		if scope != "" {
+			form.Set("scope", scope)
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, realm.String(), strings.NewReader(form.Encode()))
+		if err != nil {
+			return tokenResponse{}, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		query := realm.Query()
+		if service := c.params["service"]; service != "" {
+			query.Set("service", service)
+		}
+		if scope != "" {
+			query.Set("scope", scope)
+		}
+		if creds.Username != "" {
+			query.Set("account", creds.Username)
+			// Ask for a refresh token so the password does not need to be stored
+			query.Set("offline_token", "true")
+			query.Set("client_id", tokenClientID)
+		}
+		realm.RawQuery = query.Encode()
+
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return tokenResponse{}, err
+		}
+		if creds.Username != "" {
+			req.SetBasicAuth(creds.Username, creds.Password)
+		}
+	}
+
+	resp, err := loginClient.Do(req)
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return tokenResponse{}, ErrUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		return tokenResponse{}, fmt.Errorf("token server returned HTTP %d", resp.StatusCode)
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return tokenResponse{}, err
+	}
+	if token.Token == "" && token.AccessToken == "" {
+		return tokenResponse{}, errors.New("token server returned no token")
+	}
+	return token, nil
+}
+
+// bearer returns the token to send in the Authorization header.
+func (t tokenResponse) bearer() string {
+	if t.Token != "" {
+		return t.Token
+	}
+	return t.AccessToken
+}
+
+// parseChallenge parses a WWW-Authenticate header such as
+// `Bearer realm="https://auth.docker.io/token",service="registry.docker.io"`.
+func parseChallenge(header string) *challenge {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	if scheme == "" {
+		return nil
+	}
+	c := &challenge{scheme: strings.ToLower(scheme), params: make(map[string]string)}
+
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			c.params[key] = value
+		}
+	}
+	return c
+}
@@ -0,0 +1,104 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/distribution/reference"
+)
+
+// ErrManifestNotFound is returned when the registry does not know a tag.
+var ErrManifestNotFound = errors.New("tag not found in registry")
+
+// manifestAccept lists the manifest media types Harbor understands. Manifest
+// lists and OCI indexes come first so multi-arch tags resolve to the digest of
+// the list, which is what the daemon records in RepoDigests after a pull.
+var manifestAccept = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+}
+
+// ManifestDigest resolves the current manifest digest of a tag with a HEAD
+// request to the registry, e.g. "sha256:…" for "postgres:16".
+// Stored credentials are used if the registry requires authentication.
+func ManifestDigest(ctx context.Context, ref string) (string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return "", err
+	}
+	tagged, ok := reference.TagNameOnly(named).(reference.Tagged)
+	if !ok {
+		return "", fmt.Errorf("%s has no tag", ref)
+	}
+	host := reference.Domain(named)
+	repo := reference.Path(named)
+
+	base, c, err := ping(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	manifestURL := base.JoinPath(repo, "manifests", tagged.Tag()).String()
+
+	var authorization string
+	if c != nil {
+		creds, err := Lookup(host)
+		if err != nil {
+			return "", err
+		}
+		switch c.scheme {
+		case "bearer":
+			token, err := fetchToken(ctx, c, creds, "repository:"+repo+":pull")
+			if err != nil {
+				return "", err
+			}
+			authorization = "Bearer " + token.bearer()
+		case "basic":
+			if creds.Empty() {
+				return "", ErrUnauthorized
+			}
+			req, _ := http.NewRequest(http.MethodGet, "/", nil)
+			req.SetBasicAuth(creds.Username, creds.Password)
+			authorization = req.Header.Get("Authorization")
+		default:
+			return "", fmt.Errorf("unsupported authentication scheme %q", c.scheme)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", strings.Join(manifestAccept, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := loginClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", ErrManifestNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return "", errors.New("registry rate limit exceeded")
+	default:
+		return "", fmt.Errorf("registry returned HTTP %d", resp.StatusCode)
+	}
+
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", errors.New("registry did not report a digest")
+	}
+	return digest, nil
+}
@@ -0,0 +1,297 @@
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// ErrNoCredentialHelper is returned by Store when no credential helper is
+// configured or installed, since Harbor does not store passwords in plain text.
+var ErrNoCredentialHelper = errors.New("no Docker credential helper is configured or installed (docker-credential-*)")
+
+// Registry is a registry known from the Docker CLI config file.
+type Registry struct {
+	Host     string
+	Username string // Empty if unknown or not logged in
+	Helper   string // Credential helper storing the credentials, empty for plain "auths" entries
+	LoggedIn bool
+}
+
+// defaultHelpers are the credential helpers the Docker CLI uses by default, by platform.
+var defaultHelpers = map[string][]string{
+	"darwin":  {"osxkeychain", "desktop"},
+	"windows": {"wincred", "desktop"},
+	"linux":   {"secretservice", "pass", "desktop"},
+}
+
+// Registries lists the registries of the "auths" and "credHelpers" sections of
+// the config file and those stored in the default credential store.
+func Registries() ([]Registry, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	byHost := make(map[string]*Registry)
+	add := func(host string) *Registry {
+		host = NormalizeHost(host)
+		if r, ok := byHost[host]; ok {
+			return r
+		}
+		r := &Registry{Host: host, Helper: cfg.helperFor(host)}
+		byHost[host] = r
+		return r
+	}
+
+	for key, entry := range cfg.Auths {
+		r := add(key)
+		if creds, err := decodeAuthEntry(entry); err == nil && !creds.Empty() {
+			r.Username = creds.Username
+			r.LoggedIn = true
+		}
+	}
+	for key := range cfg.CredHelpers {
+		add(key)
+	}
+
+	// Ask every helper once which servers it has credentials for
+	helpers := make(map[string]bool)
+	for _, r := range byHost {
+		if r.Helper != "" {
+			helpers[r.Helper] = true
+		}
+	}
+	if cfg.CredsStore != "" {
+		helpers[cfg.CredsStore] = true
+	}
+	for helper := range helpers {
+		servers, err := helperList(helper)
+		if err != nil {
+			continue
+		}
+		for server, username := range servers {
+			host := NormalizeHost(server)
+			// The default store may hold servers that use another helper
+			if cfg.helperFor(host) != helper {
+				continue
+			}
+			if _, known := byHost[host]; !known && helper != cfg.CredsStore {
+				continue
+			}
+			r := add(host)
+			r.Username = username
+			r.LoggedIn = true
+		}
+	}
+
+	registries := make([]Registry, 0, len(byHost))
+	for _, r := range byHost {
+		registries = append(registries, *r)
+	}
+	sort.Slice(registries, func(i, j int) bool {
+		return registries[i].Host < registries[j].Host
+	})
+	return registries, nil
+}
+
+// Store saves credentials through the credential helper configured for their
+// registry. If none is configured, an installed default helper is configured
+// for this registry only (credHelpers); a global credsStore would hide the
+// plain "auths" credentials of all other registries from the Docker CLI.
+func Store(creds Credentials) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	host := NormalizeHost(creds.ServerAddress)
+	helper := cfg.helperFor(host)
+	if helper == "" {
+		helper = installedDefaultHelper()
+		if helper == "" {
+			return ErrNoCredentialHelper
+		}
+		if err := updateConfig(func(raw map[string]json.RawMessage) error {
+			credHelpers := make(map[string]string)
+			if data, ok := raw["credHelpers"]; ok {
+				if err := json.Unmarshal(data, &credHelpers); err != nil {
+					return err
+				}
+			}
+			credHelpers[serverAddress(host)] = helper
+			return setJSON(raw, "credHelpers", credHelpers)
+		}); err != nil {
+			return err
+		}
+	}
+
+	hc := helperCredentials{
+		ServerURL: serverAddress(host),
+		Username:  creds.Username,
+		Secret:    creds.Password,
+	}
+	if creds.IdentityToken != "" {
+		hc.Username = helperTokenUsername
+		hc.Secret = creds.IdentityToken
+	}
+	input, err := json.Marshal(hc)
+	if err != nil {
+		return err
+	}
+	if _, err := runHelper(helper, "store", strings.NewReader(string(input))); err != nil {
+		return err
+	}
+
+	// Like the CLI, keep an empty "auths" entry so the registry is listed,
+	// and drop any plain-text credentials stored before
+	return updateConfig(func(raw map[string]json.RawMessage) error {
+		auths, err := rawAuths(raw)
+		if err != nil {
+			return err
+		}
+		for key := range auths {
+			if NormalizeHost(key) == host {
+				delete(auths, key)
+			}
+		}
+		auths[serverAddress(host)] = json.RawMessage("{}")
+		return setJSON(raw, "auths", auths)
+	})
+}
+
+// Logout erases the credentials of a registry from its credential helper and
+// removes its "auths" entry from the config file.
+func Logout(host string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	host = NormalizeHost(host)
+	if helper := cfg.helperFor(host); helper != "" {
+		if _, err := runHelper(helper, "erase", strings.NewReader(serverAddress(host))); err != nil && !strings.Contains(err.Error(), helperNotFound) {
+			return err
+		}
+	}
+
+	return updateConfig(func(raw map[string]json.RawMessage) error {
+		auths, err := rawAuths(raw)
+		if err != nil {
+			return err
+		}
+		for key := range auths {
+			if NormalizeHost(key) == host {
+				delete(auths, key)
+			}
+		}
+		return setJSON(raw, "auths", auths)
+	})
+}
+
+// AllCredentials returns the stored credentials of every known registry with
+// credentials, keyed by the address the daemon expects (e.g. for builds).
+func AllCredentials() (map[string]Credentials, error) {
+	registries, err := Registries()
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string]Credentials)
+	for _, r := range registries {
+		if !r.LoggedIn {
+			continue
+		}
+		creds, err := Lookup(r.Host)
+		if err != nil || creds.Empty() {
+			continue
+		}
+		all[creds.ServerAddress] = creds
+	}
+	return all, nil
+}
+
+// helperList returns the servers a credential helper has credentials for, with their usernames.
+func helperList(helper string) (map[string]string, error) {
+	out, err := runHelper(helper, "list", strings.NewReader(""))
+	if err != nil {
+		return nil, err
+	}
+	var servers map[string]string
+	if err := json.Unmarshal(out, &servers); err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
+
+// installedDefaultHelper returns the first default credential helper found in PATH.
+func installedDefaultHelper() string {
+	for _, helper := range defaultHelpers[runtime.GOOS] {
+		if _, err := exec.LookPath("docker-credential-" + helper); err == nil {
+			return helper
+		}
+	}
+	return ""
+}
+
+// updateConfig rewrites the config file, keeping all fields Harbor does not know about.
+func updateConfig(fn func(raw map[string]json.RawMessage) error) error {
+	path, err := ConfigPath()
+	if err != nil {
+		return err
+	}
+
+	raw := make(map[string]json.RawMessage)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	if err := fn(raw); err != nil {
+		return err
+	}
+
+	data, err = json.MarshalIndent(raw, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	// Write atomically so a crash cannot leave a truncated config behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// rawAuths decodes the "auths" section of a raw config file.
+func rawAuths(raw map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	auths := make(map[string]json.RawMessage)
+	if data, ok := raw["auths"]; ok {
+		if err := json.Unmarshal(data, &auths); err != nil {
+			return nil, err
+		}
+	}
+	return auths, nil
+}
+
+// setJSON encodes value into raw[key].
+func setJSON(raw map[string]json.RawMessage, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	raw[key] = data
+	return nil
+}
@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Update checks are cached and rate limited to stay well below registry limits.
+const (
+	updateCacheTTL      = time.Hour
+	updateErrorTTL      = 15 * time.Minute
+	updateCheckInterval = 2 * time.Second
+	updateCheckTimeout  = 30 * time.Second
+)
+
+// DigestResult is the outcome of resolving the remote digest of a tag.
+type DigestResult struct {
+	Digest    string // Empty if the check failed
+	Err       error
+	CheckedAt time.Time
+}
+
+// UpdateChecker resolves the remote digests of tags in the background,
+// one at a time, and caches the results.
+type UpdateChecker struct {
+	onChange func() // Called after every completed check
+
+	mu      sync.Mutex
+	results map[string]DigestResult
+	queued  map[string]bool
+	queue   []string
+	running bool
+}
+
+// NewUpdateChecker creates an update checker. onChange is called from a
+// background goroutine whenever a result becomes available.
+func NewUpdateChecker(onChange func()) *UpdateChecker {
+	return &UpdateChecker{
+		onChange: onChange,
+		results:  make(map[string]DigestResult),
+		queued:   make(map[string]bool),
+	}
+}
+
+// Result returns the cached result for a tag and whether a check is pending.
+func (u *UpdateChecker) Result(ref string) (DigestResult, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.results[ref], u.queued[ref]
+}
+
+// Check queues a tag unless a fresh result is cached or it is already queued.
+func (u *UpdateChecker) Check(ref string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.queued[ref] {
+		return
+	}
+	if r, ok := u.results[ref]; ok {
+		ttl := updateCacheTTL
+		if r.Err != nil {
+			ttl = updateErrorTTL
+		}
+		if time.Since(r.CheckedAt) < ttl {
+			return
+		}
+	}
+	u.enqueue(ref)
+}
+
+// Recheck drops the cached result of a tag and queues it again, e.g. after a pull.
+func (u *UpdateChecker) Recheck(ref string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	delete(u.results, ref)
+	if !u.queued[ref] {
+		u.enqueue(ref)
+	}
+}
+
+// enqueue adds a tag to the queue and starts the worker. u.mu must be held.
+func (u *UpdateChecker) enqueue(ref string) {
+	u.queued[ref] = true
+	u.queue = append(u.queue, ref)
+	if !u.running {
+		u.running = true
+		go u.work()
+	}
+}
+
+// work processes the queue with a pause between requests and exits when it is empty.
+func (u *UpdateChecker) work() {
+	for {
+		u.mu.Lock()
+		if len(u.queue) == 0 {
+			u.running = false
+			u.mu.Unlock()
+			return
+		}
+		ref := u.queue[0]
+		u.queue = u.queue[1:]
+		u.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), updateCheckTimeout)
+		digest, err := ManifestDigest(ctx, ref)
+		cancel()
+
+		u.mu.Lock()
+		u.results[ref] = DigestResult{Digest: digest, Err: err, CheckedAt: time.Now()}
+		delete(u.queued, ref)
+		u.mu.Unlock()
+
+		if u.onChange != nil {
+			u.onChange()
+		}
+		time.Sleep(updateCheckInterval)
+	}
+}
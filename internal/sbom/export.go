@@ -0,0 +1,367 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"buf.build/go/spdx"
+
+	"github.com/tsukinoko-kun/harbor/internal/version"
+)
+
+// Format is a file format a package inventory can be exported as.
+type Format int
+
+const (
+	FormatSPDX      Format = iota // SPDX 2.3 JSON
+	FormatCycloneDX               // CycloneDX 1.5 JSON
+)
+
+// Extension returns the conventional file extension for a format.
+func (f Format) Extension() string {
+	if f == FormatCycloneDX {
+		return ".cdx.json"
+	}
+	return ".spdx.json"
+}
+
+// Write encodes an inventory in the given format.
+func Write(w io.Writer, inv Inventory, format Format) error {
+	var doc any
+	if format == FormatCycloneDX {
+		doc = cycloneDXDocument(inv)
+	} else {
+		doc = spdxDocument(inv)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// spdxExceptions are the SPDX license exceptions accepted after WITH, by
+// lower-case ID. Less common exceptions make the expression fall back to a
+// license comment.
+var spdxExceptions = map[string]string{}
+
+func init() {
+	for _, id := range []string{
+		"389-exception", "Autoconf-exception-2.0", "Autoconf-exception-3.0",
+		"Bison-exception-2.2", "Bootloader-exception", "Classpath-exception-2.0",
+		"CLISP-exception-2.0", "DigiRule-FOSS-exception", "eCos-exception-2.0",
+		"Fawkes-Runtime-exception", "FLTK-exception", "Font-exception-2.0",
+		"freertos-exception-2.0", "GCC-exception-2.0", "GCC-exception-3.1",
+		"gnu-javamail-exception", "GPL-3.0-linking-exception",
+		"GPL-3.0-linking-source-exception", "GPL-CC-1.0", "GStreamer-exception-2005",
+		"i2p-gpl-java-exception", "Libtool-exception", "Linux-syscall-note",
+		"LLVM-exception", "LZMA-exception", "mif-exception", "OCaml-LGPL-linking-exception",
+		"OCCT-exception-1.0", "OpenJDK-assembly-exception-1.0", "openvpn-openssl-exception",
+		"PS-or-PDF-font-exception-20170817", "Qt-GPL-exception-1.0", "Qt-LGPL-exception-1.1",
+		"Qwt-exception-1.0", "SHL-2.0", "SHL-2.1", "Swift-exception", "u-boot-exception-2.0",
+		"Universal-FOSS-exception-1.0", "WxWindows-exception-3.1", "x11vnc-openssl-exception",
+	} {
+		spdxExceptions[strings.ToLower(id)] = id
+	}
+}
+
+// licenseExpression returns a package license if it is a valid SPDX license
+// expression made of identifiers on the SPDX license list, e.g. "MIT" or
+// "GPL-2.0-or-later AND BSD-3-Clause", with the identifiers in their
+// canonical case. Free-form licenses like "GPLv2+" or "BSD" are not.
+func licenseExpression(license string) (string, bool) {
+	tokens := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(license))
+	if len(tokens) == 0 {
+		return "", false
+	}
+	p := licenseParser{tokens: tokens}
+	expr, ok := p.compound()
+	if !ok || p.pos != len(tokens) {
+		return "", false
+	}
+	return expr, true
+}
+
+// licenseParser parses SPDX license expressions:
+//
+//	compound = and { "OR" and }
+//	and      = simple { "AND" simple }
+//	simple   = "(" compound ")" | id [ "+" ] [ "WITH" exception ]
+type licenseParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *licenseParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *licenseParser) compound() (string, bool) {
+	return p.binary("OR", p.and)
+}
+
+func (p *licenseParser) and() (string, bool) {
+	return p.binary("AND", p.simple)
+}
+
+// binary parses operands joined by op.
+func (p *licenseParser) binary(op string, operand func() (string, bool)) (string, bool) {
+	expr, ok := operand()
+	for ok && p.next() == op {
+		p.pos++
+		var rhs string
+		rhs, ok = operand()
+		expr += " " + op + " " + rhs
+	}
+	return expr, ok
+}
+
+func (p *licenseParser) simple() (string, bool) {
+	token := p.next()
+	p.pos++
+	if token == "(" {
+		expr, ok := p.compound()
+		if !ok || p.next() != ")" {
+			return "", false
+		}
+		p.pos++
+		return "(" + expr + ")", true
+	}
+
+	id, plus := strings.CutSuffix(token, "+")
+	license, ok := spdx.LicenseForID(id)
+	if !ok && plus {
+		// IDs like "GPL-2.0+" are listed themselves
+		license, ok = spdx.LicenseForID(token)
+		plus = false
+	}
+	if !ok {
+		return "", false
+	}
+	expr := license.ID
+	if plus {
+		expr += "+"
+	}
+	if p.next() == "WITH" {
+		p.pos++
+		exception, ok := spdxExceptions[strings.ToLower(p.next())]
+		if !ok {
+			return "", false
+		}
+		p.pos++
+		expr += " WITH " + exception
+	}
+	return expr, true
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func created(inv Inventory) time.Time {
+	if inv.Created.IsZero() {
+		return time.Now().UTC()
+	}
+	return inv.Created.UTC()
+}
+
+// SPDX 2.3 JSON document
+
+type spdxDoc struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	LicenseComments  string            `json:"licenseComments,omitempty"`
+	SourceInfo       string            `json:"sourceInfo,omitempty"`
+	PrimaryPurpose   string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	Category string `json:"referenceCategory"`
+	Type     string `json:"referenceType"`
+	Locator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+func spdxDocument(inv Inventory) spdxDoc {
+	const imageID = "SPDXRef-Image"
+	doc := spdxDoc{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              inv.Image,
+		DocumentNamespace: "https://github.com/tsukinoko-kun/harbor/spdx/" + newUUID(),
+		CreationInfo: spdxCreationInfo{
+			Created:  created(inv).Format(time.RFC3339),
+			Creators: []string{"Tool: harbor-" + version.Version},
+		},
+	}
+
+	doc.Packages = append(doc.Packages, spdxPackage{
+		Name:             inv.Image,
+		SPDXID:           imageID,
+		VersionInfo:      inv.ImageID,
+		DownloadLocation: "NOASSERTION",
+		LicenseConcluded: "NOASSERTION",
+		LicenseDeclared:  "NOASSERTION",
+		PrimaryPurpose:   "CONTAINER",
+	})
+	doc.Relationships = append(doc.Relationships, spdxRelationship{
+		Element: doc.SPDXID,
+		Type:    "DESCRIBES",
+		Related: imageID,
+	})
+
+	for i, pkg := range inv.Packages {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		p := spdxPackage{
+			Name:             pkg.Name,
+			SPDXID:           id,
+			VersionInfo:      pkg.Version,
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  "NOASSERTION",
+			SourceInfo:       "found in " + pkg.Location,
+			ExternalRefs: []spdxExternalRef{{
+				Category: "PACKAGE-MANAGER",
+				Type:     "purl",
+				Locator:  pkg.PURL(inv.Distro),
+			}},
+		}
+		if license, ok := licenseExpression(pkg.License); ok {
+			p.LicenseDeclared = license
+		} else if pkg.License != "" {
+			p.LicenseComments = pkg.License
+		}
+		doc.Packages = append(doc.Packages, p)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			Element: imageID,
+			Type:    "CONTAINS",
+			Related: id,
+		})
+	}
+	return doc
+}
+
+// CycloneDX 1.5 JSON document
+
+type cdxDoc struct {
+	BOMFormat    string         `json:"bomFormat"`
+	SpecVersion  string         `json:"specVersion"`
+	SerialNumber string         `json:"serialNumber"`
+	Version      int            `json:"version"`
+	Metadata     cdxMetadata    `json:"metadata"`
+	Components   []cdxComponent `json:"components"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref,omitempty"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	PURL       string        `json:"purl,omitempty"`
+	Licenses   []cdxLicense  `json:"licenses,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxLicense struct {
+	Expression string           `json:"expression,omitempty"`
+	License    *cdxNamedLicense `json:"license,omitempty"`
+}
+
+type cdxNamedLicense struct {
+	Name string `json:"name"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func cycloneDXDocument(inv Inventory) cdxDoc {
+	doc := cdxDoc{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: created(inv).Format(time.RFC3339),
+			Tools: cdxTools{Components: []cdxComponent{{
+				Type:    "application",
+				Name:    "harbor",
+				Version: version.Version,
+			}}},
+			Component: cdxComponent{
+				Type:    "container",
+				BOMRef:  "image",
+				Name:    inv.Image,
+				Version: inv.ImageID,
+			},
+		},
+		Components: make([]cdxComponent, 0, len(inv.Packages)),
+	}
+
+	for i, pkg := range inv.Packages {
+		c := cdxComponent{
+			Type:       "library",
+			BOMRef:     fmt.Sprintf("pkg-%d", i+1),
+			Name:       pkg.Name,
+			Version:    pkg.Version,
+			PURL:       pkg.PURL(inv.Distro),
+			Properties: []cdxProperty{{Name: "harbor:location", Value: pkg.Location}},
+		}
+		if license, ok := licenseExpression(pkg.License); ok {
+			c.Licenses = []cdxLicense{{Expression: license}}
+		} else if pkg.License != "" {
+			c.Licenses = []cdxLicense{{License: &cdxNamedLicense{Name: pkg.License}}}
+		}
+		doc.Components = append(doc.Components, c)
+	}
+	return doc
+}
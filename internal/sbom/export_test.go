@@ -0,0 +1,82 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestLicenseExpression(t *testing.T) {
+	tests := []struct {
+		license string
+		want    string // Empty if the license is not a valid expression
+	}{
+		{"MIT", "MIT"},
+		{"mit", "MIT"},
+		{"  Apache-2.0 ", "Apache-2.0"},
+		{"GPL-2.0-or-later AND BSD-3-Clause", "GPL-2.0-or-later AND BSD-3-Clause"},
+		{"GPL-2.0+", "GPL-2.0+"},
+		{"LGPL-3.0+ OR MIT", "LGPL-3.0+ OR MIT"},
+		{"Apache-2.0+", "Apache-2.0+"},
+		{"(MIT OR Apache-2.0) AND Zlib", "(MIT OR Apache-2.0) AND Zlib"},
+		{"(MIT OR (Apache-2.0 AND BSD-2-Clause))", "(MIT OR (Apache-2.0 AND BSD-2-Clause))"},
+		{"GPL-2.0-only WITH Linux-syscall-note", "GPL-2.0-only WITH Linux-syscall-note"},
+		{"gpl-3.0-or-later with gcc-exception-3.1", ""}, // Operators are upper case
+
+		// Free-form licenses of RPM and apk packages
+		{"GPLv2+", ""},
+		{"BSD", ""},
+		{"Public", ""},
+		{"Public Domain", ""},
+		{"GPLv2+ and LGPLv2+", ""},
+		{"MIT AND BSD", ""},
+		{"GPL-2.0-only WITH Some-exception", ""},
+		{"LicenseRef-custom", ""},
+
+		// Malformed expressions
+		{"", ""},
+		{"MIT AND", ""},
+		{"AND MIT", ""},
+		{"(MIT", ""},
+		{"MIT)", ""},
+		{"()", ""},
+		{"MIT Apache-2.0", ""},
+		{"MIT WITH", ""},
+	}
+	for _, tt := range tests {
+		got, ok := licenseExpression(tt.license)
+		if ok != (tt.want != "") || got != tt.want {
+			t.Errorf("licenseExpression(%q) = %q, %v; want %q", tt.license, got, ok, tt.want)
+		}
+	}
+}
+
+func TestWriteSPDXLicenses(t *testing.T) {
+	inv := Inventory{
+		Image:  "example:1",
+		Distro: "rocky",
+		Packages: []Package{
+			{Name: "bash", Version: "5.1.8-9.el9", Type: TypeRPM, License: "GPLv3+", Location: "/var/lib/rpm/rpmdb.sqlite"},
+			{Name: "zlib", Version: "1.2.13", Type: TypeAPK, License: "Zlib", Location: "/lib/apk/db/installed"},
+		},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, inv, FormatSPDX); err != nil {
+		t.Fatal(err)
+	}
+	var doc spdxDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]spdxPackage)
+	for _, p := range doc.Packages {
+		byName[p.Name] = p
+	}
+	if p := byName["bash"]; p.LicenseDeclared != "NOASSERTION" || p.LicenseComments != "GPLv3+" {
+		t.Errorf("bash: licenseDeclared %q, licenseComments %q", p.LicenseDeclared, p.LicenseComments)
+	}
+	if p := byName["zlib"]; p.LicenseDeclared != "Zlib" || p.LicenseComments != "" {
+		t.Errorf("zlib: licenseDeclared %q, licenseComments %q", p.LicenseDeclared, p.LicenseComments)
+	}
+}
@@ -0,0 +1,162 @@
+package sbom
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+// packageLock is the subset of package-lock.json used to list dependencies.
+// Lockfile version 1 nests "dependencies"; versions 2 and 3 list "packages" by path.
+type packageLock struct {
+	Packages map[string]struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		License string `json:"license"`
+		Link    bool   `json:"link"`
+	} `json:"packages"`
+	Dependencies map[string]packageLockDependency `json:"dependencies"`
+}
+
+type packageLockDependency struct {
+	Version      string                           `json:"version"`
+	Dependencies map[string]packageLockDependency `json:"dependencies"`
+}
+
+// parsePackageLock reads the dependencies of an npm project from its lockfile.
+func parsePackageLock(r io.Reader) ([]Package, error) {
+	var lock packageLock
+	if err := json.NewDecoder(r).Decode(&lock); err != nil {
+		return nil, err
+	}
+
+	var pkgs []Package
+	if len(lock.Packages) > 0 {
+		for key, entry := range lock.Packages {
+			// The empty key is the project itself, links point to other entries
+			if key == "" || entry.Link || entry.Version == "" {
+				continue
+			}
+			name := entry.Name
+			if name == "" {
+				i := strings.LastIndex(key, "node_modules/")
+				name = key[i+len("node_modules/"):]
+			}
+			pkgs = append(pkgs, Package{
+				Name:    name,
+				Version: entry.Version,
+				Type:    TypeNPM,
+				License: entry.License,
+			})
+		}
+		return pkgs, nil
+	}
+
+	var walk func(deps map[string]packageLockDependency)
+	walk = func(deps map[string]packageLockDependency) {
+		for name, dep := range deps {
+			if dep.Version != "" && !strings.HasPrefix(dep.Version, "file:") {
+				pkgs = append(pkgs, Package{Name: name, Version: dep.Version, Type: TypeNPM})
+			}
+			walk(dep.Dependencies)
+		}
+	}
+	walk(lock.Dependencies)
+	return pkgs, nil
+}
+
+// parsePythonMetadata reads the METADATA or PKG-INFO file of an installed Python distribution.
+func parsePythonMetadata(r io.Reader) ([]Package, error) {
+	var pkg *Package
+	// Only the header stanza matters, the description follows the first blank line
+	err := readStanzas(r, func(fields map[string]string) {
+		if pkg != nil || fields["Name"] == "" {
+			return
+		}
+		license := fields["License-Expression"]
+		if license == "" {
+			license = fields["License"]
+		}
+		// Older packages put the whole license text into the field
+		if strings.Contains(license, "\n") || len(license) > 100 {
+			license = ""
+		}
+		pkg = &Package{
+			Name:    fields["Name"],
+			Version: fields["Version"],
+			Type:    TypePython,
+			License: license,
+		}
+	})
+	if pkg == nil {
+		return nil, err
+	}
+	return []Package{*pkg}, err
+}
+
+// Magic numbers of the executable formats Go builds
+var executableMagic = [][]byte{
+	[]byte("\x7fELF"),
+	[]byte("MZ"),
+	{0xfe, 0xed, 0xfa, 0xce}, {0xce, 0xfa, 0xed, 0xfe}, // Mach-O 32 bit
+	{0xfe, 0xed, 0xfa, 0xcf}, {0xcf, 0xfa, 0xed, 0xfe}, // Mach-O 64 bit
+}
+
+// parseGoBinary reads the build information embedded in Go executables: the
+// Go version as "stdlib", the main module and all dependency modules.
+// Other executables yield no packages.
+func parseGoBinary(r io.Reader) ([]Package, error) {
+	head := make([]byte, 4)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return nil, nil
+	}
+	isExecutable := false
+	for _, magic := range executableMagic {
+		if bytes.HasPrefix(head, magic) {
+			isExecutable = true
+			break
+		}
+	}
+	if !isExecutable {
+		return nil, nil
+	}
+
+	// Spool to a temporary file instead of memory: buildinfo needs random
+	// access, and executables can be hundreds of megabytes
+	f, err := os.CreateTemp("", "harbor-sbom-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+	if _, err := io.Copy(f, io.MultiReader(bytes.NewReader(head), r)); err != nil {
+		return nil, err
+	}
+
+	info, err := buildinfo.Read(f)
+	if err != nil {
+		// Not a Go binary, or built without module support
+		return nil, nil
+	}
+
+	pkgs := []Package{{Name: "stdlib", Version: info.GoVersion, Type: TypeGo}}
+	if info.Main.Path != "" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		pkgs = append(pkgs, Package{Name: info.Main.Path, Version: info.Main.Version, Type: TypeGo})
+	}
+	for _, dep := range info.Deps {
+		// Replaced modules are what actually got compiled in
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if dep.Version == "" {
+			continue
+		}
+		pkgs = append(pkgs, Package{Name: dep.Path, Version: dep.Version, Type: TypeGo})
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,73 @@
+package sbom
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCatalogGoBinary(t *testing.T) {
+	// The test binary is a Go executable with module information
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const p = "usr/local/bin/app"
+	if !Wants(p, info.Size(), true) {
+		t.Fatalf("Wants(%q) = false", p)
+	}
+	pkgs, err := Catalog(p, info.Size(), true, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]Package)
+	for _, pkg := range pkgs {
+		if pkg.Type != TypeGo || pkg.Location != "/"+p {
+			t.Errorf("unexpected package %+v", pkg)
+		}
+		byName[pkg.Name] = pkg
+	}
+	if got := byName["stdlib"].Version; got != runtime.Version() {
+		t.Errorf("stdlib version = %q, want %q", got, runtime.Version())
+	}
+	// A dependency of this package, linked into the test binary
+	if got := byName["buf.build/go/spdx"].Version; got != "v0.2.0" {
+		t.Errorf("buf.build/go/spdx version = %q, want v0.2.0", got)
+	}
+	// Development builds of the main module have no version to report
+	for name := range byName {
+		if strings.HasPrefix(name, "github.com/tsukinoko-kun/harbor") {
+			t.Errorf("main module %s listed without a version", name)
+		}
+	}
+}
+
+func TestParseGoBinaryOther(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":          nil,
+		"script":         []byte("#!/bin/sh\necho hello\n"),
+		"not Go":         append([]byte("\x7fELF\x02\x01\x01"), make([]byte, 1024)...),
+		"truncated Mach": {0xcf, 0xfa, 0xed, 0xfe},
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			pkgs, err := parseGoBinary(bytes.NewReader(data))
+			if err != nil || len(pkgs) != 0 {
+				t.Errorf("got %v, %v; want no packages", pkgs, err)
+			}
+		})
+	}
+}
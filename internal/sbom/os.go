@@ -0,0 +1,91 @@
+package sbom
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// parseDpkgStatus reads a dpkg status file, or a file of status.d used by
+// distroless images, and returns the installed packages.
+func parseDpkgStatus(r io.Reader) ([]Package, error) {
+	var pkgs []Package
+	err := readStanzas(r, func(fields map[string]string) {
+		// Packages removed but not purged keep a stanza in the status file
+		if status, ok := fields["Status"]; ok && !strings.HasSuffix(status, " installed") {
+			return
+		}
+		if fields["Package"] == "" {
+			return
+		}
+		pkgs = append(pkgs, Package{
+			Name:    fields["Package"],
+			Version: fields["Version"],
+			Type:    TypeDeb,
+			Arch:    fields["Architecture"],
+		})
+	})
+	return pkgs, err
+}
+
+// parseAPKInstalled reads the apk database of Alpine Linux.
+func parseAPKInstalled(r io.Reader) ([]Package, error) {
+	var pkgs []Package
+	err := readStanzas(r, func(fields map[string]string) {
+		if fields["P"] == "" {
+			return
+		}
+		pkgs = append(pkgs, Package{
+			Name:    fields["P"],
+			Version: fields["V"],
+			Type:    TypeAPK,
+			Arch:    fields["A"],
+			License: fields["L"],
+		})
+	})
+	return pkgs, err
+}
+
+// readStanzas calls fn for every block of "Key: value" lines separated by
+// blank lines. Continuation lines starting with whitespace are appended to the
+// previous field. Only the first occurrence of a key in a stanza is kept.
+func readStanzas(r io.Reader, fn func(fields map[string]string)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+
+	fields := make(map[string]string)
+	var last string
+	flush := func() {
+		if len(fields) > 0 {
+			fn(fields)
+			fields = make(map[string]string)
+		}
+		last = ""
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+		case line[0] == ' ' || line[0] == '\t':
+			if last != "" {
+				fields[last] += "\n" + strings.TrimSpace(line)
+			}
+		default:
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				last = ""
+				continue
+			}
+			if _, seen := fields[key]; seen {
+				last = ""
+				continue
+			}
+			fields[key] = strings.TrimSpace(value)
+			last = key
+		}
+	}
+	flush()
+	return scanner.Err()
+}
@@ -0,0 +1,75 @@
+package sbom
+
+import (
+	"os"
+	"testing"
+)
+
+// catalogFixture catalogs a file of testdata as if it were at p in an image.
+func catalogFixture(t *testing.T, fixture, p string) []Package {
+	t.Helper()
+	f, err := os.Open("testdata/" + fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Wants(p, info.Size(), false) {
+		t.Fatalf("Wants(%q) = false", p)
+	}
+	pkgs, err := Catalog(p, info.Size(), false, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkgs
+}
+
+// comparePackages fails the test unless got equals want in order.
+func comparePackages(t *testing.T, got, want []Package) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d packages, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("package %d:\ngot  %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCatalogDpkgStatus(t *testing.T) {
+	const p = "var/lib/dpkg/status"
+	pkgs := catalogFixture(t, "dpkg-status", p)
+	// vim-tiny was removed but not purged
+	comparePackages(t, pkgs, []Package{
+		{Name: "base-files", Version: "12.4+deb12u5", Type: TypeDeb, Arch: "amd64", Location: "/" + p},
+		{Name: "libssl3", Version: "3.0.11-1~deb12u2", Type: TypeDeb, Arch: "amd64", Location: "/" + p},
+		{Name: "tzdata", Version: "2024a-0+deb12u1", Type: TypeDeb, Arch: "all", Location: "/" + p},
+	})
+}
+
+func TestCatalogDpkgStatusD(t *testing.T) {
+	// Distroless images have one file per package and no Status field
+	const p = "var/lib/dpkg/status.d/libc6"
+	pkgs := catalogFixture(t, "dpkg-status.d-libc6", p)
+	comparePackages(t, pkgs, []Package{
+		{Name: "libc6", Version: "2.36-9+deb12u4", Type: TypeDeb, Arch: "arm64", Location: "/" + p},
+	})
+
+	if Wants("var/lib/dpkg/status.d/libc6.md5sums", 100, false) {
+		t.Error("checksum files of status.d are cataloged")
+	}
+}
+
+func TestCatalogAPKInstalled(t *testing.T) {
+	const p = "lib/apk/db/installed"
+	pkgs := catalogFixture(t, "apk-installed", p)
+	comparePackages(t, pkgs, []Package{
+		{Name: "musl", Version: "1.2.4_git20230717-r4", Type: TypeAPK, Arch: "x86_64", License: "MIT", Location: "/" + p},
+		{Name: "busybox", Version: "1.36.1-r15", Type: TypeAPK, Arch: "x86_64", License: "GPL-2.0-only", Location: "/" + p},
+		{Name: "ca-certificates-bundle", Version: "20240226-r0", Type: TypeAPK, Arch: "x86_64", License: "MPL-2.0 AND MIT", Location: "/" + p},
+	})
+}
@@ -0,0 +1,126 @@
+package sbom
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RPM header tags and data types used to describe a package
+const (
+	rpmTagName    = 1000
+	rpmTagVersion = 1001
+	rpmTagRelease = 1002
+	rpmTagEpoch   = 1003
+	rpmTagLicense = 1014
+	rpmTagArch    = 1022
+
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+)
+
+// parseRPMDB reads the packages of the SQLite rpm database used by Fedora,
+// RHEL 9 and openSUSE. Older Berkeley DB databases are not supported.
+func parseRPMDB(r io.Reader) ([]Package, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openSQLite(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkgs []Package
+	err = db.tableRows("Packages", func(cols []any) error {
+		// Columns are hnum (the rowid, stored as NULL) and the header blob
+		if len(cols) < 2 {
+			return nil
+		}
+		blob, ok := cols[1].([]byte)
+		if !ok {
+			return nil
+		}
+		pkg, err := parseRPMHeader(blob)
+		if err != nil {
+			return err
+		}
+		// The database also holds the public keys imported into rpm
+		if pkg.Name != "" && pkg.Name != "gpg-pubkey" {
+			pkgs = append(pkgs, pkg)
+		}
+		return nil
+	})
+	return pkgs, err
+}
+
+// parseRPMHeader decodes the tags of an rpm header blob as stored in the
+// database: the index length, the data length, the index and the data store.
+func parseRPMHeader(blob []byte) (Package, error) {
+	if len(blob) < 8 {
+		return Package{}, errors.New("truncated rpm header")
+	}
+	entries := int(binary.BigEndian.Uint32(blob[0:4]))
+	dataLen := int(binary.BigEndian.Uint32(blob[4:8]))
+	indexEnd := 8 + entries*16
+	if entries < 0 || dataLen < 0 || indexEnd+dataLen > len(blob) || indexEnd < 8 {
+		return Package{}, errors.New("invalid rpm header")
+	}
+	store := blob[indexEnd : indexEnd+dataLen]
+
+	str := func(offset int) string {
+		if offset < 0 || offset >= len(store) {
+			return ""
+		}
+		end := offset
+		for end < len(store) && store[end] != 0 {
+			end++
+		}
+		return string(store[offset:end])
+	}
+
+	var pkg Package
+	var version, release string
+	epoch := -1
+	for i := 0; i < entries; i++ {
+		entry := blob[8+i*16 : 8+(i+1)*16]
+		tag := binary.BigEndian.Uint32(entry[0:4])
+		kind := binary.BigEndian.Uint32(entry[4:8])
+		offset := int(int32(binary.BigEndian.Uint32(entry[8:12])))
+
+		isString := kind == rpmTypeString || kind == rpmTypeStringArray || kind == rpmTypeI18NString
+		switch {
+		case tag == rpmTagName && isString:
+			pkg.Name = str(offset)
+		case tag == rpmTagVersion && isString:
+			version = str(offset)
+		case tag == rpmTagRelease && isString:
+			release = str(offset)
+		case tag == rpmTagLicense && isString:
+			pkg.License = str(offset)
+		case tag == rpmTagArch && isString:
+			pkg.Arch = str(offset)
+		case tag == rpmTagEpoch && kind == rpmTypeInt32:
+			if offset >= 0 && offset+4 <= len(store) {
+				epoch = int(binary.BigEndian.Uint32(store[offset:]))
+			}
+		}
+	}
+
+	// Versions are written as [epoch:]version-release
+	var b strings.Builder
+	if epoch > 0 {
+		b.WriteString(strconv.Itoa(epoch) + ":")
+	}
+	b.WriteString(version)
+	if release != "" {
+		b.WriteString("-" + release)
+	}
+	pkg.Version = b.String()
+	pkg.Type = TypeRPM
+	return pkg, nil
+}
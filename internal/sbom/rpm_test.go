@@ -0,0 +1,103 @@
+package sbom
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// testdata/rpmdb.sqlite is generated by testdata/rpmdb.py.
+
+func TestCatalogRPMDB(t *testing.T) {
+	data, err := os.ReadFile("testdata/rpmdb.sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const p = "var/lib/rpm/rpmdb.sqlite"
+	if !Wants(p, int64(len(data)), false) {
+		t.Fatalf("Wants(%q) = false", p)
+	}
+	pkgs, err := Catalog(p, int64(len(data)), false, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 35 rows without the public key
+	if len(pkgs) != 34 {
+		t.Errorf("got %d packages, want 34", len(pkgs))
+	}
+	byName := make(map[string]Package)
+	for _, pkg := range pkgs {
+		byName[pkg.Name] = pkg
+	}
+	want := []Package{
+		{Name: "bash", Version: "5.1.8-9.el9", Type: TypeRPM, Arch: "x86_64", License: "GPLv3+", Location: "/" + p},
+		{Name: "openssl-libs", Version: "1:3.0.7-27.el9", Type: TypeRPM, Arch: "x86_64", License: "Apache-2.0", Location: "/" + p},
+		{Name: "tzdata", Version: "2024a-1.el9", Type: TypeRPM, Arch: "noarch", License: "Public Domain", Location: "/" + p},
+		// Spans overflow pages
+		{Name: "kernel-core", Version: "5.14.0-427.el9", Type: TypeRPM, Arch: "x86_64", License: "GPLv2 and Redistributable, no modification permitted", Location: "/" + p},
+		// In the last leaf page
+		{Name: "filler-29", Version: "1.29-1.el9", Type: TypeRPM, Arch: "noarch", License: "MIT", Location: "/" + p},
+	}
+	for _, w := range want {
+		if got := byName[w.Name]; got != w {
+			t.Errorf("got %+v\nwant %+v", got, w)
+		}
+	}
+	if _, ok := byName["gpg-pubkey"]; ok {
+		t.Error("imported public keys are listed as packages")
+	}
+}
+
+func TestParseRPMDBInvalid(t *testing.T) {
+	data, err := os.ReadFile("testdata/rpmdb.sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string][]byte{
+		"empty":         nil,
+		"not sqlite":    []byte("Berkeley DB is not supported, and this is not even that"),
+		"header only":   data[:100],
+		"first page":    data[:1024],
+		"half":          data[:len(data)/2],
+		"last page cut": data[:len(data)-1024],
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseRPMDB(bytes.NewReader(data)); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+}
+
+func TestParseRPMHeaderInvalid(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":              nil,
+		"truncated":          {0, 0, 0, 1},
+		"index out of range": {0, 0, 0, 9, 0, 0, 0, 0},
+		"data out of range":  {0, 0, 0, 0, 0, 0, 1, 0},
+		"negative count":     {0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0},
+	}
+	for name, blob := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseRPMHeader(blob); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+
+	// Offsets outside the data store are ignored rather than read
+	blob := []byte{
+		0, 0, 0, 1, 0, 0, 0, 4, // One entry, four bytes of data
+		0, 0, 0x03, 0xe8, 0, 0, 0, 6, 0, 0, 0x10, 0, 0, 0, 0, 1, // Name at offset 4096
+		'b', 'a', 's', 'h',
+	}
+	pkg, err := parseRPMHeader(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Name != "" {
+		t.Errorf("Name = %q, want empty", pkg.Name)
+	}
+}
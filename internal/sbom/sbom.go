@@ -0,0 +1,187 @@
+// Package sbom builds a software bill of materials from the files of a
+// container image and exports it as SPDX or CycloneDX JSON.
+package sbom
+
+import (
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Type is the ecosystem a package belongs to, named like purl types.
+type Type string
+
+const (
+	TypeDeb    Type = "deb"
+	TypeAPK    Type = "apk"
+	TypeRPM    Type = "rpm"
+	TypeGo     Type = "golang"
+	TypeNPM    Type = "npm"
+	TypePython Type = "pypi"
+)
+
+// Label returns a human-readable name of the package type.
+func (t Type) Label() string {
+	switch t {
+	case TypeDeb:
+		return "Debian"
+	case TypeAPK:
+		return "Alpine"
+	case TypeRPM:
+		return "RPM"
+	case TypeGo:
+		return "Go"
+	case TypeNPM:
+		return "npm"
+	case TypePython:
+		return "Python"
+	default:
+		return string(t)
+	}
+}
+
+// Package is a software package found in an image.
+type Package struct {
+	Name     string
+	Version  string
+	Type     Type
+	Arch     string // Empty if unknown or not applicable
+	License  string // As declared by the package, empty if unknown
+	Location string // Path of the file the package was found in
+}
+
+// Inventory is the list of packages of an image.
+type Inventory struct {
+	Image    string // Name the image was cataloged as, e.g. "postgres:16"
+	ImageID  string
+	Distro   string // ID from os-release, e.g. "debian", empty if unknown
+	Created  time.Time
+	Packages []Package
+}
+
+// PURL returns the package URL of a package, e.g. "pkg:deb/debian/bash@5.2.15-2?arch=amd64".
+// distro is the namespace of OS packages.
+func (p Package) PURL(distro string) string {
+	name := url.PathEscape(p.Name)
+	switch p.Type {
+	case TypeDeb, TypeAPK, TypeRPM:
+		if distro != "" {
+			name = url.PathEscape(distro) + "/" + name
+		}
+	case TypeGo:
+		// Module paths keep their slashes as namespace segments
+		name = escapePath(p.Name)
+	case TypeNPM:
+		if strings.HasPrefix(p.Name, "@") {
+			name = strings.Replace(url.PathEscape(p.Name), "%2F", "/", 1)
+		}
+	case TypePython:
+		name = url.PathEscape(strings.ToLower(strings.ReplaceAll(p.Name, "_", "-")))
+	}
+
+	purl := "pkg:" + string(p.Type) + "/" + name
+	if p.Version != "" {
+		purl += "@" + url.PathEscape(p.Version)
+	}
+	if p.Arch != "" {
+		purl += "?arch=" + url.QueryEscape(p.Arch)
+	}
+	return purl
+}
+
+// escapePath escapes every segment of a slash-separated path.
+func escapePath(p string) string {
+	parts := strings.Split(p, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// maxFileSize limits how much of a single file is read into memory.
+const maxFileSize = 256 << 20
+
+// Wants reports whether a file may contain package information and should be
+// passed to Catalog. p is a relative path inside the image.
+func Wants(p string, size int64, executable bool) bool {
+	if size <= 0 || size > maxFileSize {
+		return false
+	}
+	return catalogerFor(p, executable) != nil
+}
+
+// Catalog returns the packages recorded in a file of an image. Files that turn
+// out not to contain package information yield no packages.
+func Catalog(p string, size int64, executable bool, r io.Reader) ([]Package, error) {
+	fn := catalogerFor(p, executable)
+	if fn == nil || size > maxFileSize {
+		return nil, nil
+	}
+	pkgs, err := fn(io.LimitReader(r, maxFileSize))
+	for i := range pkgs {
+		pkgs[i].Location = "/" + p
+	}
+	return pkgs, err
+}
+
+// catalogFunc parses a file for packages.
+type catalogFunc func(r io.Reader) ([]Package, error)
+
+// catalogerFor returns the parser for a path, or nil if it holds no package information.
+func catalogerFor(p string, executable bool) catalogFunc {
+	base := path.Base(p)
+	dir := path.Dir(p)
+	switch {
+	case p == "var/lib/dpkg/status" || dir == "var/lib/dpkg/status.d" && !strings.HasSuffix(base, ".md5sums"):
+		return parseDpkgStatus
+	case p == "lib/apk/db/installed":
+		return parseAPKInstalled
+	case p == "var/lib/rpm/rpmdb.sqlite" || p == "usr/lib/sysimage/rpm/rpmdb.sqlite":
+		return parseRPMDB
+	case base == "package-lock.json" && !strings.Contains(dir, "node_modules"):
+		return parsePackageLock
+	case base == "METADATA" && strings.HasSuffix(dir, ".dist-info"):
+		return parsePythonMetadata
+	case base == "PKG-INFO" && strings.HasSuffix(dir, ".egg-info"), strings.HasSuffix(base, ".egg-info"):
+		return parsePythonMetadata
+	case executable:
+		return parseGoBinary
+	}
+	return nil
+}
+
+// IsOSRelease reports whether p is an os-release file naming the distribution.
+func IsOSRelease(p string) bool {
+	return p == "etc/os-release" || p == "usr/lib/os-release"
+}
+
+// ParseOSRelease returns the ID field of an os-release file, e.g. "debian".
+func ParseOSRelease(r io.Reader) string {
+	data, err := io.ReadAll(io.LimitReader(r, 64<<10))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "ID="); ok {
+			return strings.Trim(value, `"'`)
+		}
+	}
+	return ""
+}
+
+// Sort orders packages by name, version and location.
+func Sort(pkgs []Package) {
+	sort.Slice(pkgs, func(i, j int) bool {
+		a, b := pkgs[i], pkgs[j]
+		if !strings.EqualFold(a.Name, b.Name) {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Location < b.Location
+	})
+}
@@ -0,0 +1,280 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// sqliteDB is a minimal read-only reader for SQLite database files. It only
+// supports what is needed to read the rows of a table: table b-trees,
+// overflow pages and the record format. Journal and WAL files are ignored.
+type sqliteDB struct {
+	data     []byte
+	pageSize int
+	usable   int // Page size without the reserved bytes at the end of each page
+}
+
+var sqliteMagic = []byte("SQLite format 3\x00")
+
+// openSQLite checks the header of a database file loaded into memory.
+func openSQLite(data []byte) (*sqliteDB, error) {
+	if len(data) < 100 || !bytes.HasPrefix(data, sqliteMagic) {
+		return nil, errors.New("not a SQLite database")
+	}
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("invalid SQLite page size %d", pageSize)
+	}
+	return &sqliteDB{
+		data:     data,
+		pageSize: pageSize,
+		usable:   pageSize - int(data[20]),
+	}, nil
+}
+
+// page returns the contents of a page, numbered from 1.
+func (db *sqliteDB) page(n uint32) ([]byte, error) {
+	start := (int(n) - 1) * db.pageSize
+	if n == 0 || start+db.pageSize > len(db.data) {
+		return nil, fmt.Errorf("SQLite page %d out of range", n)
+	}
+	return db.data[start : start+db.pageSize], nil
+}
+
+// tableRows calls fn with the columns of every row of the named table.
+// Columns are int64, float64 (not decoded, nil), []byte or string values.
+func (db *sqliteDB) tableRows(table string, fn func(cols []any) error) error {
+	var root uint32
+	// The schema table is rooted at page 1: type, name, tbl_name, rootpage, sql
+	err := db.walkTable(1, func(cols []any) error {
+		if len(cols) >= 4 && cols[0] == "table" && cols[1] == table {
+			if n, ok := cols[3].(int64); ok {
+				root = uint32(n)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if root == 0 {
+		return fmt.Errorf("SQLite table %q not found", table)
+	}
+	return db.walkTable(root, fn)
+}
+
+// walkTable visits the rows of the table b-tree rooted at a page in rowid order.
+func (db *sqliteDB) walkTable(root uint32, fn func(cols []any) error) error {
+	return db.walkPage(root, fn, 0)
+}
+
+func (db *sqliteDB) walkPage(n uint32, fn func(cols []any) error, depth int) error {
+	if depth > 64 {
+		return errors.New("SQLite b-tree too deep")
+	}
+	page, err := db.page(n)
+	if err != nil {
+		return err
+	}
+	offset := 0
+	if n == 1 {
+		offset = 100 // The database header precedes the b-tree header
+	}
+	if offset+12 > len(page) {
+		return errors.New("truncated SQLite page")
+	}
+
+	kind := page[offset]
+	cells := int(binary.BigEndian.Uint16(page[offset+3 : offset+5]))
+	switch kind {
+	case 0x05: // Interior table page
+		pointers := offset + 12
+		for i := 0; i < cells; i++ {
+			cell, err := cellOffset(page, pointers, i)
+			if err != nil {
+				return err
+			}
+			if cell+4 > len(page) {
+				return errors.New("truncated SQLite cell")
+			}
+			if err := db.walkPage(binary.BigEndian.Uint32(page[cell:]), fn, depth+1); err != nil {
+				return err
+			}
+		}
+		right := binary.BigEndian.Uint32(page[offset+8 : offset+12])
+		return db.walkPage(right, fn, depth+1)
+	case 0x0d: // Leaf table page
+		pointers := offset + 8
+		for i := 0; i < cells; i++ {
+			cell, err := cellOffset(page, pointers, i)
+			if err != nil {
+				return err
+			}
+			payload, err := db.leafPayload(page, cell)
+			if err != nil {
+				return err
+			}
+			cols, err := decodeRecord(payload)
+			if err != nil {
+				return err
+			}
+			if err := fn(cols); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unexpected SQLite page type %#x", kind)
+	}
+}
+
+// cellOffset returns the offset of the i-th cell from the cell pointer array.
+func cellOffset(page []byte, pointers, i int) (int, error) {
+	p := pointers + 2*i
+	if p+2 > len(page) {
+		return 0, errors.New("truncated SQLite cell pointer array")
+	}
+	cell := int(binary.BigEndian.Uint16(page[p:]))
+	if cell >= len(page) {
+		return 0, errors.New("SQLite cell out of range")
+	}
+	return cell, nil
+}
+
+// leafPayload returns the full record of a table leaf cell, following overflow pages.
+func (db *sqliteDB) leafPayload(page []byte, cell int) ([]byte, error) {
+	size, n := readVarint(page[cell:])
+	cell += n
+	_, n = readVarint(page[cell:]) // rowid
+	cell += n
+
+	// Local payload size as defined by the file format
+	u := db.usable
+	maxLocal := u - 35
+	local := int(size)
+	if int(size) > maxLocal {
+		minLocal := (u-12)*32/255 - 23
+		local = minLocal + (int(size)-minLocal)%(u-4)
+		if local > maxLocal {
+			local = minLocal
+		}
+	}
+	if cell+local > len(page) {
+		return nil, errors.New("truncated SQLite payload")
+	}
+	payload := make([]byte, 0, size)
+	payload = append(payload, page[cell:cell+local]...)
+	if local == int(size) {
+		return payload, nil
+	}
+
+	if cell+local+4 > len(page) {
+		return nil, errors.New("truncated SQLite payload")
+	}
+	next := binary.BigEndian.Uint32(page[cell+local:])
+	for next != 0 && len(payload) < int(size) {
+		overflow, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+		next = binary.BigEndian.Uint32(overflow)
+		chunk := overflow[4:u]
+		if remaining := int(size) - len(payload); len(chunk) > remaining {
+			chunk = chunk[:remaining]
+		}
+		payload = append(payload, chunk...)
+	}
+	if len(payload) != int(size) {
+		return nil, errors.New("truncated SQLite overflow chain")
+	}
+	return payload, nil
+}
+
+// decodeRecord decodes the columns of a record in the SQLite record format.
+func decodeRecord(rec []byte) ([]any, error) {
+	headerSize, n := readVarint(rec)
+	if n == 0 || int(headerSize) > len(rec) {
+		return nil, errors.New("invalid SQLite record")
+	}
+	var types []uint64
+	for pos := n; pos < int(headerSize); {
+		t, n := readVarint(rec[pos:headerSize])
+		if n == 0 {
+			return nil, errors.New("invalid SQLite record header")
+		}
+		types = append(types, t)
+		pos += n
+	}
+
+	cols := make([]any, len(types))
+	body := rec[headerSize:]
+	for i, t := range types {
+		var size int
+		switch {
+		case t == 0, t == 8, t == 9:
+			size = 0
+		case t <= 4:
+			size = int(t)
+		case t == 5:
+			size = 6
+		case t == 6, t == 7:
+			size = 8
+		case t >= 12:
+			size = int(t-12) / 2
+		default:
+			return nil, fmt.Errorf("invalid SQLite serial type %d", t)
+		}
+		if size > len(body) {
+			return nil, errors.New("truncated SQLite record")
+		}
+		value := body[:size]
+		body = body[size:]
+
+		switch {
+		case t == 0:
+			cols[i] = nil
+		case t == 8:
+			cols[i] = int64(0)
+		case t == 9:
+			cols[i] = int64(1)
+		case t <= 6:
+			// Big-endian two's complement integer
+			var v int64
+			if value[0]&0x80 != 0 {
+				v = -1
+			}
+			for _, b := range value {
+				v = v<<8 | int64(b)
+			}
+			cols[i] = v
+		case t == 7:
+			cols[i] = nil
+		case t%2 == 0:
+			cols[i] = value
+		default:
+			cols[i] = string(value)
+		}
+	}
+	return cols, nil
+}
+
+// readVarint decodes a SQLite variable-length integer and returns its size in bytes,
+// or 0 if b is too short.
+func readVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9 && i < len(b); i++ {
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
@@ -0,0 +1,69 @@
+#!/usr/bin/env python3
+"""Generates rpmdb.sqlite, a small rpm database in the schema of rpm 4.16+.
+
+The header blobs hold the tags Harbor reads plus a large description, so the
+table spans interior b-tree pages and rows continue on overflow pages.
+Run from this directory: python3 rpmdb.py
+"""
+import os
+import sqlite3
+import struct
+
+TYPE_INT32, TYPE_STRING, TYPE_BIN, TYPE_I18NSTRING = 4, 6, 7, 9
+
+
+def header(name, version, release, arch, license, epoch=None, description=""):
+    tags = [
+        (1000, TYPE_STRING, name),
+        (1001, TYPE_STRING, version),
+        (1002, TYPE_STRING, release),
+        (1022, TYPE_STRING, arch),
+        (1014, TYPE_STRING, license),
+        (1005, TYPE_I18NSTRING, description or name + " package"),
+    ]
+    if epoch is not None:
+        tags.append((1003, TYPE_INT32, epoch))
+
+    index, store = [], b""
+    for tag, kind, value in tags:
+        if kind == TYPE_INT32:
+            store += b"\0" * (-len(store) % 4)
+            index.append(struct.pack(">IIiI", tag, kind, len(store), 1))
+            store += struct.pack(">I", value)
+        else:
+            index.append(struct.pack(">IIiI", tag, kind, len(store), 1))
+            store += value.encode() + b"\0"
+    # Immutable region trailer, as written by rpm
+    index.insert(0, struct.pack(">IIiI", 63, TYPE_BIN, len(store), 16))
+    store += struct.pack(">IIiI", 63, TYPE_BIN, -16 * len(index), 16)
+    return name, struct.pack(">II", len(index), len(store)) + b"".join(index) + store
+
+
+def main():
+    if os.path.exists("rpmdb.sqlite"):
+        os.remove("rpmdb.sqlite")
+    db = sqlite3.connect("rpmdb.sqlite")
+    db.execute("PRAGMA page_size = 1024")
+    db.execute("CREATE TABLE 'Packages' (hnum INTEGER PRIMARY KEY AUTOINCREMENT,blob BLOB NOT NULL)")
+    db.execute("CREATE TABLE 'Name' (key TEXT NOT NULL,hnum INTEGER NOT NULL,idx INTEGER NOT NULL,"
+               "FOREIGN KEY (hnum) REFERENCES 'Packages'(hnum))")
+
+    packages = [
+        header("bash", "5.1.8", "9.el9", "x86_64", "GPLv3+"),
+        header("openssl-libs", "3.0.7", "27.el9", "x86_64", "Apache-2.0", epoch=1),
+        header("gpg-pubkey", "fd431d51", "4ae0493b", "(none)", "pubkey (none)"),
+        header("tzdata", "2024a", "1.el9", "noarch", "Public Domain", epoch=0),
+        header("kernel-core", "5.14.0", "427.el9", "x86_64", "GPLv2 and Redistributable, no modification permitted",
+               description="The kernel package contains the Linux kernel. " * 120),
+    ]
+    packages += [header("filler-%02d" % i, "1.%d" % i, "1.el9", "noarch", "MIT") for i in range(30)]
+    for name, blob in packages:
+        cur = db.execute("INSERT INTO Packages (blob) VALUES (?)", (blob,))
+        db.execute("INSERT INTO Name VALUES (?, ?, 0)", (name, cur.lastrowid))
+    db.commit()
+    db.execute("VACUUM")
+    db.close()
+
+
+if __name__ == "__main__":
+    main()
@@ -18,7 +18,9 @@ import (
 	"github.com/tsukinoko-kun/harbor/internal/config"
 	"github.com/tsukinoko-kun/harbor/internal/docker"
 	"github.com/tsukinoko-kun/harbor/internal/models"
+	"github.com/tsukinoko-kun/harbor/internal/notify"
 	"github.com/tsukinoko-kun/harbor/internal/version"
+	"github.com/tsukinoko-kun/harbor/internal/watch"
 )
 
 // App represents the main application.
@@ -27,6 +29,7 @@ type App struct {
 	theme    *Theme
 	docker   *docker.Client
 	settings *config.Settings
+	watcher  *watch.Watcher
 
 	// UI State
 	currentView models.View
@@ -35,7 +38,9 @@ type App struct {
 	images      *ImagesView
 	volumes     *VolumesView
 	networks    *NetworksView
+	topology    *TopologyView
 	settingsUI  *SettingsView
+	notices     *NotificationCenter
 
 	// Data
 	mu              sync.RWMutex
@@ -43,6 +48,7 @@ type App struct {
 	imageList       []docker.Image
 	volumeList      []docker.Volume
 	networkList     []docker.Network
+	networkAliases  map[string]map[string][]string // By container ID and network ID, for the topology view
 	lastError       error
 }
 
@@ -58,12 +64,15 @@ func NewApp(dockerClient *docker.Client, settings *config.Settings) *App {
 		currentView: models.ViewContainers,
 	}
 
+	a.watcher = watch.New(dockerClient, settings.WatchRules, a.onWatchMatch)
+	a.notices = NewNotificationCenter(theme)
 	a.sidebar = NewSidebar(theme, a.onViewChange)
-	a.containers = NewContainersView(theme, dockerClient, settings)
-	a.images = NewImagesView(theme)
-	a.volumes = NewVolumesView(theme)
-	a.networks = NewNetworksView(theme)
-	a.settingsUI = NewSettingsView(theme, settings)
+	a.containers = NewContainersView(theme, dockerClient, settings, a.watcher, a.invalidate)
+	a.images = NewImagesView(theme, dockerClient, a.invalidate, a.showContainers)
+	a.volumes = NewVolumesView(theme, dockerClient, settings, a.invalidate, a.showContainers)
+	a.networks = NewNetworksView(theme, dockerClient, a.invalidate, a.showContainers)
+	a.topology = NewTopologyView(theme, dockerClient, a.showContainers)
+	a.settingsUI = NewSettingsView(theme, settings, a.watcher, a.invalidate)
 
 	return a
 }
@@ -80,6 +89,9 @@ func (a *App) Run() error {
 	// Start data refresh goroutine
 	go a.refreshLoop()
 
+	// Evaluate log watch rules in the background
+	go a.watcher.Run(context.Background())
+
 	// Run the event loop
 	var ops op.Ops
 	for {
@@ -142,7 +154,56 @@ func (a *App) refreshData() {
 		} else {
 			a.lastError = err
 		}
+	case models.ViewTopology:
+		if groups, err := a.docker.ListContainersGrouped(ctx); err == nil {
+			a.containerGroups = groups
+			var containers []docker.Container
+			for _, g := range groups {
+				containers = append(containers, g.Containers...)
+			}
+			a.networkAliases = a.docker.NetworkAliases(ctx, containers)
+		} else {
+			a.lastError = err
+		}
+		if networks, err := a.docker.ListNetworks(ctx); err == nil {
+			a.networkList = networks
+		} else {
+			a.lastError = err
+		}
+	}
+}
+
+// onWatchMatch reports a log line that matched a watch rule.
+// Desktop notifications are used for the notify action when available;
+// otherwise the match is shown as an in-app notification.
+func (a *App) onWatchMatch(m watch.Match) {
+	title := "Log alert: " + m.Container.Name
+	body := m.Line.Message
+	if runes := []rune(body); len(runes) > 200 {
+		body = string(runes[:200]) + "…"
+	}
+
+	if m.Rule.Action == config.WatchActionNotify {
+		if err := notify.Send(title, body); err == nil {
+			return
+		}
 	}
+
+	a.notices.Push(title, body)
+	a.invalidate()
+}
+
+// invalidate requests a redraw of the main window. It is safe to call from any goroutine.
+func (a *App) invalidate() {
+	if a.window != nil {
+		a.window.Invalidate()
+	}
+}
+
+// showContainers switches to the containers view and highlights the given containers.
+func (a *App) showContainers(containerIDs []string) {
+	a.containers.Reveal(containerIDs)
+	a.onViewChange(models.ViewContainers)
 }
 
 func (a *App) onViewChange(view models.View) {
@@ -168,6 +229,14 @@ func (a *App) layout(gtx layout.Context) layout.Dimensions {
 	// Fill background
 	paint.FillShape(gtx.Ops, a.theme.Colors.Background, clip.Rect{Max: gtx.Constraints.Max}.Op())
 
+	return layout.Stack{}.Layout(gtx,
+		layout.Stacked(a.layoutMain),
+		// In-app notifications
+		layout.Expanded(a.notices.Layout),
+	)
+}
+
+func (a *App) layoutMain(gtx layout.Context) layout.Dimensions {
 	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
 		// Main area (sidebar + content)
 		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
@@ -225,6 +294,8 @@ func (a *App) layoutContent(gtx layout.Context) layout.Dimensions {
 		return a.volumes.Layout(gtx, a.volumeList)
 	case models.ViewNetworks:
 		return a.networks.Layout(gtx, a.networkList)
+	case models.ViewTopology:
+		return a.topology.Layout(gtx, a.containerGroups, a.networkList, a.networkAliases)
 	case models.ViewSettings:
 		return a.settingsUI.Layout(gtx)
 	default:
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"sync"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// actionBanner shows the result of the last action above a list, optionally
+// with the containers involved, e.g. those blocking a removal.
+type actionBanner struct {
+	mu         sync.RWMutex
+	message    string
+	isErr      bool
+	containers []docker.Container
+	dismiss    widget.Clickable
+	show       widget.Clickable
+}
+
+// set shows a message. It may be called from background goroutines.
+func (b *actionBanner) set(msg string, isErr bool, containers []docker.Container) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.message = msg
+	b.isErr = isErr
+	b.containers = containers
+}
+
+// clear hides the banner.
+func (b *actionBanner) clear() {
+	b.set("", false, nil)
+}
+
+// update handles the banner buttons. If "Show containers" was clicked, the
+// banner is hidden and the IDs of its containers are returned.
+func (b *actionBanner) update(gtx layout.Context) []string {
+	if b.dismiss.Clicked(gtx) {
+		b.clear()
+	}
+	if !b.show.Clicked(gtx) {
+		return nil
+	}
+	b.mu.RLock()
+	ids := make([]string, len(b.containers))
+	for i, ctr := range b.containers {
+		ids[i] = ctr.ID
+	}
+	b.mu.RUnlock()
+	b.clear()
+	return ids
+}
+
+func (b *actionBanner) layout(gtx layout.Context, theme *Theme) layout.Dimensions {
+	b.mu.RLock()
+	msg := b.message
+	isErr := b.isErr
+	containers := b.containers
+	b.mu.RUnlock()
+
+	if msg == "" {
+		return layout.Dimensions{}
+	}
+
+	bgColor := theme.Colors.CardBg
+	textColor := theme.Colors.Text
+	if isErr {
+		bgColor = theme.Colors.ErrorBg
+		textColor = theme.Colors.ErrorText
+	}
+
+	return layout.Inset{
+		Top:   unit.Dp(8),
+		Left:  unit.Dp(16),
+		Right: unit.Dp(16),
+	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Stack{}.Layout(gtx,
+			layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+				return fillRounded(gtx, bgColor, 6)
+			}),
+			layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{
+					Top:    unit.Dp(12),
+					Bottom: unit.Dp(12),
+					Left:   unit.Dp(16),
+					Right:  unit.Dp(16),
+				}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						// Message
+						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+							label := material.Body2(theme.Material, msg)
+							label.Color = textColor
+							return label.Layout(gtx)
+						}),
+						// Jump to the containers involved
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							if len(containers) == 0 {
+								return layout.Dimensions{}
+							}
+							return layout.Inset{Left: unit.Dp(12), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+								return layoutActionButton(gtx, theme, &b.show, "Show containers", false, false)
+							})
+						}),
+						// Dismiss button
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							return b.dismiss.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+								label := material.Body2(theme.Material, "✕")
+								label.Color = textColor
+								return label.Layout(gtx)
+							})
+						}),
+					)
+				})
+			}),
+		)
+	})
+}
@@ -0,0 +1,324 @@
+package ui
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gioui.org/app"
+	"gioui.org/font"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// maxBuildLines limits the output kept by a build window.
+const maxBuildLines = 20000
+
+// Build states
+const (
+	buildRunning = iota
+	buildSucceeded
+	buildFailed
+	buildCancelled
+)
+
+// BuildWindow represents a window showing the output of an image build.
+type BuildWindow struct {
+	window *app.Window
+	theme  *Theme
+	docker *docker.Client
+	opts   docker.BuildOptions
+
+	// Build output
+	mu       sync.RWMutex
+	lines    []docker.BuildLine
+	progress docker.BuildProgress
+	step     string // Instruction of the current step
+	state    int
+	errMsg   string
+	cancel   context.CancelFunc
+	list     widget.List
+
+	cancelButton  widget.Clickable
+	rebuildButton widget.Clickable
+
+	closed bool
+}
+
+// NewBuildWindow creates a window and starts building an image in it.
+func NewBuildWindow(theme *Theme, dockerClient *docker.Client, opts docker.BuildOptions) {
+	bw := &BuildWindow{
+		theme:  theme,
+		docker: dockerClient,
+		opts:   opts,
+		list: widget.List{
+			List: layout.List{
+				Axis:        layout.Vertical,
+				ScrollToEnd: true,
+			},
+		},
+	}
+
+	go bw.run()
+}
+
+// buildTitle names a build by its first tag or its context directory.
+func buildTitle(opts docker.BuildOptions) string {
+	if len(opts.Tags) > 0 {
+		return opts.Tags[0]
+	}
+	return filepath.Base(opts.ContextDir)
+}
+
+func (bw *BuildWindow) run() {
+	bw.window = new(app.Window)
+	bw.window.Option(
+		app.Title("Build: "+buildTitle(bw.opts)),
+		app.Size(unit.Dp(900), unit.Dp(650)),
+		app.MinSize(unit.Dp(500), unit.Dp(300)),
+	)
+
+	bw.startBuild()
+
+	// Run the event loop
+	var ops op.Ops
+	for {
+		switch e := bw.window.Event().(type) {
+		case app.DestroyEvent:
+			bw.closed = true
+			bw.mu.RLock()
+			if bw.cancel != nil {
+				bw.cancel()
+			}
+			bw.mu.RUnlock()
+			return
+		case app.FrameEvent:
+			gtx := app.NewContext(&ops, e)
+			bw.layout(gtx)
+			e.Frame(gtx.Ops)
+		}
+	}
+}
+
+// startBuild clears the output and runs the build in the background.
+func (bw *BuildWindow) startBuild() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	bw.mu.Lock()
+	bw.lines = nil
+	bw.progress = docker.BuildProgress{}
+	bw.step = ""
+	bw.state = buildRunning
+	bw.errMsg = ""
+	bw.cancel = cancel
+	bw.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		defer bw.invalidate()
+
+		progress, err := bw.docker.BuildImage(ctx, bw.opts, bw.appendLine)
+
+		bw.mu.Lock()
+		defer bw.mu.Unlock()
+		bw.progress = progress
+		switch {
+		case ctx.Err() != nil:
+			bw.state = buildCancelled
+		case err != nil:
+			bw.state = buildFailed
+			bw.errMsg = err.Error()
+			// Errors before the build started are not part of the output
+			if len(bw.lines) == 0 || bw.lines[len(bw.lines)-1].Kind != docker.BuildLineError {
+				bw.lines = append(bw.lines, docker.BuildLine{Kind: docker.BuildLineError, Text: err.Error()})
+			}
+		default:
+			bw.state = buildSucceeded
+		}
+	}()
+}
+
+// appendLine adds a line of build output. Consecutive status lines of the
+// same layer replace each other so pulling a base image stays readable.
+func (bw *BuildWindow) appendLine(line docker.BuildLine, progress docker.BuildProgress) {
+	bw.mu.Lock()
+	bw.progress = progress
+	if line.Kind == docker.BuildLineStep {
+		if _, instruction, ok := strings.Cut(line.Text, " : "); ok {
+			bw.step = instruction
+		}
+	}
+
+	replaced := false
+	if n := len(bw.lines); n > 0 && line.Kind == docker.BuildLineStatus {
+		last := bw.lines[n-1]
+		id, _, ok := strings.Cut(line.Text, ": ")
+		if ok && last.Kind == docker.BuildLineStatus && strings.HasPrefix(last.Text, id+": ") {
+			bw.lines[n-1] = line
+			replaced = true
+		}
+	}
+	if !replaced {
+		bw.lines = append(bw.lines, line)
+		if len(bw.lines) > maxBuildLines {
+			bw.lines = bw.lines[len(bw.lines)-maxBuildLines:]
+		}
+	}
+	bw.mu.Unlock()
+
+	bw.invalidate()
+}
+
+func (bw *BuildWindow) invalidate() {
+	if bw.window != nil && !bw.closed {
+		bw.window.Invalidate()
+	}
+}
+
+func (bw *BuildWindow) layout(gtx layout.Context) layout.Dimensions {
+	// Fill background
+	paint.FillShape(gtx.Ops, bw.theme.Colors.Background, clip.Rect{Max: gtx.Constraints.Max}.Op())
+
+	bw.mu.RLock()
+	state := bw.state
+	cancel := bw.cancel
+	bw.mu.RUnlock()
+
+	if bw.cancelButton.Clicked(gtx) && state == buildRunning && cancel != nil {
+		cancel()
+	}
+	if bw.rebuildButton.Clicked(gtx) && state != buildRunning {
+		bw.startBuild()
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return bw.layoutHeader(gtx)
+		}),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Left: unit.Dp(16), Right: unit.Dp(16), Bottom: unit.Dp(8)}.Layout(gtx, bw.layoutOutput)
+		}),
+	)
+}
+
+func (bw *BuildWindow) layoutHeader(gtx layout.Context) layout.Dimensions {
+	bw.mu.RLock()
+	state := bw.state
+	progress := bw.progress
+	step := bw.step
+	errMsg := bw.errMsg
+	bw.mu.RUnlock()
+
+	status := "Sending build context…"
+	statusColor := bw.theme.Colors.TextSecondary
+	switch state {
+	case buildRunning:
+		if progress.StepCount > 0 {
+			status = "Step " + intToStr(progress.Step) + "/" + intToStr(progress.StepCount) + ": " + step
+		}
+	case buildSucceeded:
+		status = "Build succeeded"
+		if progress.ImageID != "" {
+			id := strings.TrimPrefix(progress.ImageID, "sha256:")
+			status += " • " + id[:min(len(id), 12)]
+		}
+		statusColor = bw.theme.Colors.StatusRunning
+	case buildFailed:
+		status = "Build failed"
+		if progress.StepCount > 0 {
+			status += " at step " + intToStr(progress.Step) + "/" + intToStr(progress.StepCount)
+		}
+		status += ": " + errMsg
+		statusColor = bw.theme.Colors.StatusStopped
+	case buildCancelled:
+		status = "Build cancelled"
+		statusColor = bw.theme.Colors.StatusPaused
+	}
+
+	var fraction float32
+	if progress.StepCount > 0 {
+		// A step counts as done once the next one starts
+		fraction = float32(progress.Step-1) / float32(progress.StepCount)
+	}
+	if state == buildSucceeded {
+		fraction = 1
+	}
+
+	return layout.Inset{Top: unit.Dp(12), Bottom: unit.Dp(12), Left: unit.Dp(16), Right: unit.Dp(16)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						label := material.Body1(bw.theme.Material, status)
+						label.Color = statusColor
+						label.MaxLines = 2
+						return label.Layout(gtx)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						if state == buildRunning {
+							return layoutActionButton(gtx, bw.theme, &bw.cancelButton, "Cancel", true, false)
+						}
+						return layoutActionButton(gtx, bw.theme, &bw.rebuildButton, "Rebuild", false, false)
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutProgressBar(gtx, bw.theme, fraction)
+			}),
+		)
+	})
+}
+
+func (bw *BuildWindow) layoutOutput(gtx layout.Context) layout.Dimensions {
+	// Status lines are replaced in place, so hold the lock while laying out
+	bw.mu.RLock()
+	defer bw.mu.RUnlock()
+	lines := bw.lines
+
+	return material.List(bw.theme.Material, &bw.list).Layout(gtx, len(lines), func(gtx layout.Context, index int) layout.Dimensions {
+		return bw.layoutLine(gtx, lines[index], index == 0)
+	})
+}
+
+func (bw *BuildWindow) layoutLine(gtx layout.Context, line docker.BuildLine, first bool) layout.Dimensions {
+	label := material.Caption(bw.theme.Material, line.Text)
+	label.Font = font.Font{Typeface: "monospace"}
+	label.Color = bw.theme.Colors.Text
+
+	switch line.Kind {
+	case docker.BuildLineStep:
+		label.Color = bw.theme.Colors.Primary
+		label.Font.Weight = font.Bold
+		inset := layout.Inset{Top: unit.Dp(8), Bottom: unit.Dp(2)}
+		if first {
+			inset.Top = 0
+		}
+		return inset.Layout(gtx, label.Layout)
+	case docker.BuildLineStatus:
+		label.Color = bw.theme.Colors.TextMuted
+	case docker.BuildLineError:
+		label.Color = bw.theme.Colors.ErrorText
+		return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Stack{}.Layout(gtx,
+				layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+					return fillRounded(gtx, bw.theme.Colors.ErrorBg, 4)
+				}),
+				layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Constraints.Max.X
+					return layout.UniformInset(unit.Dp(6)).Layout(gtx, label.Layout)
+				}),
+			)
+		})
+	}
+	return label.Layout(gtx)
+}
@@ -0,0 +1,383 @@
+package ui
+
+import (
+	"context"
+	"image/color"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/app"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/config"
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// ConnectivityWindow represents a window that checks whether a container can
+// resolve and connect to another container or host.
+type ConnectivityWindow struct {
+	window   *app.Window
+	theme    *Theme
+	docker   *docker.Client
+	settings *config.Settings
+	source   docker.Container
+
+	target      widget.Editor
+	port        widget.Editor
+	check       widget.Clickable
+	pick        map[string]*widget.Clickable // By container ID
+	targetList  widget.List
+	resultsList widget.List
+
+	// Updated from background goroutines
+	mu         sync.Mutex
+	containers []docker.Container // Possible targets
+	checking   bool
+	checked    docker.ConnectivityCheck
+	result     docker.ConnectivityResult
+	checkErr   string
+
+	closed bool
+}
+
+// NewConnectivityWindow creates and runs a new connectivity window for a container.
+func NewConnectivityWindow(theme *Theme, dockerClient *docker.Client, settings *config.Settings, ctr docker.Container) {
+	cw := &ConnectivityWindow{
+		theme:       theme,
+		docker:      dockerClient,
+		settings:    settings,
+		source:      ctr,
+		pick:        make(map[string]*widget.Clickable),
+		targetList:  widget.List{List: layout.List{Axis: layout.Vertical}},
+		resultsList: widget.List{List: layout.List{Axis: layout.Vertical}},
+	}
+	cw.target.SingleLine = true
+	cw.port.SingleLine = true
+	cw.port.Filter = "0123456789"
+
+	go cw.run()
+}
+
+func (cw *ConnectivityWindow) run() {
+	cw.window = new(app.Window)
+	cw.window.Option(
+		app.Title("Connectivity: "+cw.source.Name),
+		app.Size(unit.Dp(720), unit.Dp(640)),
+		app.MinSize(unit.Dp(500), unit.Dp(400)),
+	)
+
+	go cw.loadContainers()
+
+	// Run the event loop
+	var ops op.Ops
+	for {
+		switch e := cw.window.Event().(type) {
+		case app.DestroyEvent:
+			cw.closed = true
+			return
+		case app.FrameEvent:
+			gtx := app.NewContext(&ops, e)
+			cw.layout(gtx)
+			e.Frame(gtx.Ops)
+		}
+	}
+}
+
+// loadContainers lists the other containers as possible targets.
+func (cw *ConnectivityWindow) loadContainers() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	containers, err := cw.docker.ListContainers(ctx)
+	if err != nil {
+		return
+	}
+	var targets []docker.Container
+	for _, ctr := range containers {
+		if ctr.ID != cw.source.ID {
+			targets = append(targets, ctr)
+		}
+	}
+
+	cw.mu.Lock()
+	cw.containers = targets
+	cw.mu.Unlock()
+	cw.invalidate()
+}
+
+func (cw *ConnectivityWindow) invalidate() {
+	if cw.window != nil && !cw.closed {
+		cw.window.Invalidate()
+	}
+}
+
+// targetName returns the name the source most likely uses for a container:
+// the compose service if both are in the same project, else the container name.
+func (cw *ConnectivityWindow) targetName(ctr docker.Container) string {
+	if ctr.Service != "" && ctr.Project == cw.source.Project {
+		return ctr.Service
+	}
+	return ctr.Name
+}
+
+// startCheck runs the check in the background.
+func (cw *ConnectivityWindow) startCheck(check docker.ConnectivityCheck) {
+	cw.mu.Lock()
+	cw.checking = true
+	cw.checked = check
+	cw.result = docker.ConnectivityResult{}
+	cw.checkErr = ""
+	cw.mu.Unlock()
+
+	go func() {
+		defer cw.invalidate()
+
+		// Pulling the helper image may take a while
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		result, err := cw.docker.CheckConnectivity(ctx, check)
+
+		cw.mu.Lock()
+		defer cw.mu.Unlock()
+		cw.checking = false
+		cw.result = result
+		if err != nil {
+			cw.checkErr = err.Error()
+		}
+	}()
+}
+
+func (cw *ConnectivityWindow) layout(gtx layout.Context) layout.Dimensions {
+	// Fill background
+	paint.FillShape(gtx.Ops, cw.theme.Colors.Background, clip.Rect{Max: gtx.Constraints.Max}.Op())
+
+	cw.mu.Lock()
+	containers := cw.containers
+	checking := cw.checking
+	checked := cw.checked
+	result := cw.result
+	checkErr := cw.checkErr
+	cw.mu.Unlock()
+
+	for _, ctr := range containers {
+		if btn, ok := cw.pick[ctr.ID]; ok && btn.Clicked(gtx) {
+			cw.target.SetText(cw.targetName(ctr))
+			cw.port.SetText("")
+			if len(ctr.Ports) > 0 {
+				cw.port.SetText(strconv.Itoa(int(ctr.Ports[0].PrivatePort)))
+			}
+		}
+	}
+	if cw.check.Clicked(gtx) && !checking {
+		target := strings.TrimSpace(cw.target.Text())
+		port, _ := strconv.ParseUint(strings.TrimSpace(cw.port.Text()), 10, 16)
+		if target != "" {
+			cw.startCheck(docker.ConnectivityCheck{
+				Source:      cw.source.ID,
+				Target:      target,
+				Port:        uint16(port),
+				HelperImage: cw.settings.Volumes.HelperImage,
+			})
+			checking = true
+		}
+	}
+
+	checkLabel := "Check"
+	if checking {
+		checkLabel = "Checking…"
+	}
+
+	return layout.Inset{
+		Top:    unit.Dp(12),
+		Bottom: unit.Dp(12),
+		Left:   unit.Dp(16),
+		Right:  unit.Dp(16),
+	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.H6(cw.theme.Material, "Connectivity from "+cw.source.Name)
+				label.Color = cw.theme.Colors.Text
+				return label.Layout(gtx)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogText(gtx, cw.theme, "Checks name resolution and TCP connections from inside the container. Leave the port empty to only resolve the name.")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			// Target and port
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.End}.Layout(gtx,
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return layoutTextField(gtx, cw.theme, &cw.target, "Target container or hostname", "db")
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Dp(unit.Dp(100))
+						gtx.Constraints.Max.X = gtx.Constraints.Min.X
+						return layoutTextField(gtx, cw.theme, &cw.port, "Port", "5432")
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layoutActionButton(gtx, cw.theme, &cw.check, checkLabel, false, checking)
+					}),
+				)
+			}),
+			// Other containers to pick the target from
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutSectionLabel(gtx, cw.theme, "Containers")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return cw.layoutTargets(gtx, containers)
+			}),
+			// Results
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutSectionLabel(gtx, cw.theme, "Result")
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				switch {
+				case checking:
+					return layoutDialogText(gtx, cw.theme, "Checking "+checked.Target+"…")
+				case checked.Target == "":
+					return layoutDialogText(gtx, cw.theme, "Pick a container or enter a hostname.")
+				}
+				return cw.layoutResult(gtx, connectivityLines(cw.theme, cw.source, checked, result, checkErr))
+			}),
+		)
+	})
+}
+
+func (cw *ConnectivityWindow) layoutTargets(gtx layout.Context, containers []docker.Container) layout.Dimensions {
+	if len(containers) == 0 {
+		return layoutDialogText(gtx, cw.theme, "No other containers.")
+	}
+	gtx.Constraints.Max.Y = min(gtx.Constraints.Max.Y, gtx.Dp(unit.Dp(180)))
+	return material.List(cw.theme.Material, &cw.targetList).Layout(gtx, len(containers), func(gtx layout.Context, index int) layout.Dimensions {
+		ctr := containers[index]
+		btn, ok := cw.pick[ctr.ID]
+		if !ok {
+			btn = new(widget.Clickable)
+			cw.pick[ctr.ID] = btn
+		}
+
+		info := ctr.State
+		var nets []string
+		for _, n := range ctr.Networks {
+			nets = append(nets, n.Name)
+		}
+		if len(nets) > 0 {
+			info += " • on " + strings.Join(nets, ", ")
+		}
+		if len(ctr.Ports) > 0 {
+			ports := make([]string, len(ctr.Ports))
+			for i, p := range ctr.Ports {
+				ports[i] = p.String()
+			}
+			info += " • " + strings.Join(ports, ", ")
+		}
+
+		return material.Clickable(gtx, btn, func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4), Left: unit.Dp(4), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						label := material.Body2(cw.theme.Material, ctr.Name)
+						label.Color = cw.theme.Colors.Text
+						return label.Layout(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						label := material.Caption(cw.theme.Material, info)
+						label.Color = cw.theme.Colors.TextMuted
+						label.MaxLines = 1
+						return label.Layout(gtx)
+					}),
+				)
+			})
+		})
+	})
+}
+
+// resultLine is a line of the check result.
+type resultLine struct {
+	text  string
+	color color.NRGBA
+}
+
+// connectivityLines explains the result of a check: what the name resolved
+// to, whether the port is open, and which networks make the name resolvable.
+func connectivityLines(theme *Theme, source docker.Container, check docker.ConnectivityCheck, result docker.ConnectivityResult, checkErr string) []resultLine {
+	ok, bad, info := theme.Colors.StatusRunning, theme.Colors.StatusStopped, theme.Colors.TextSecondary
+	var lines []resultLine
+
+	if checkErr != "" {
+		lines = append(lines, resultLine{"The check failed: " + checkErr, bad})
+	}
+	if result.DNSTool == "" {
+		return lines
+	}
+
+	if result.Helper != "" {
+		lines = append(lines, resultLine{"Checked from a " + result.Helper + " helper container sharing the network of " + source.Name + ", which lacks the tools", info})
+	} else {
+		method := "Checked inside " + source.Name + " with " + result.DNSTool
+		if result.TCPTool != "" {
+			method += " and " + result.TCPTool
+		}
+		lines = append(lines, resultLine{method, info})
+	}
+
+	if len(result.Addresses) > 0 {
+		lines = append(lines, resultLine{"✓ " + check.Target + " resolves to " + strings.Join(result.Addresses, ", "), ok})
+	} else {
+		lines = append(lines, resultLine{"✗ " + check.Target + " does not resolve", bad})
+	}
+
+	if result.TCPTool != "" {
+		target := check.Target + ":" + strconv.Itoa(int(check.Port))
+		if result.Reachable {
+			lines = append(lines, resultLine{"✓ Connected to " + target, ok})
+		} else {
+			msg := "✗ Cannot connect to " + target
+			if result.TCPError != "" {
+				msg += ": " + result.TCPError
+			}
+			lines = append(lines, resultLine{msg, bad})
+		}
+	}
+
+	// Why the name does or does not resolve
+	for _, m := range result.Names {
+		switch {
+		case m.NoDNS:
+			lines = append(lines, resultLine{m.Container + " is on " + m.Network + ", where Docker provides no DNS for container names; connect both to a user-defined network", bad})
+		case m.Shared:
+			lines = append(lines, resultLine{"Resolvable by the " + m.Via + " of " + m.Container + " on " + m.Network + ", which " + source.Name + " is also on", ok})
+		default:
+			lines = append(lines, resultLine{m.Container + " answers to " + check.Target + " on " + m.Network + ", but " + source.Name + " is not connected to it", bad})
+		}
+	}
+	if len(result.Names) == 0 {
+		if len(result.Addresses) > 0 {
+			lines = append(lines, resultLine{check.Target + " is not a container name or alias; it was resolved by the DNS servers of " + source.Name, info})
+		} else {
+			lines = append(lines, resultLine{"No container answers to " + check.Target + "; check the spelling or use the compose service name", info})
+		}
+	}
+	return lines
+}
+
+func (cw *ConnectivityWindow) layoutResult(gtx layout.Context, lines []resultLine) layout.Dimensions {
+	return material.List(cw.theme.Material, &cw.resultsList).Layout(gtx, len(lines), func(gtx layout.Context, index int) layout.Dimensions {
+		return layout.Inset{Bottom: unit.Dp(6), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			label := material.Body2(cw.theme.Material, lines[index].text)
+			label.Color = lines[index].color
+			return label.Layout(gtx)
+		})
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"image"
 	"image/color"
 	"io"
+	"net"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,19 +19,25 @@ import (
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 
+	"github.com/tsukinoko-kun/harbor/internal/browser"
 	"github.com/tsukinoko-kun/harbor/internal/config"
 	"github.com/tsukinoko-kun/harbor/internal/docker"
 	"github.com/tsukinoko-kun/harbor/internal/models"
+	"github.com/tsukinoko-kun/harbor/internal/probe"
 	"github.com/tsukinoko-kun/harbor/internal/ui/widgets"
+	"github.com/tsukinoko-kun/harbor/internal/watch"
 )
 
 // containerRowButtons holds the button states for a container row.
 type containerRowButtons struct {
-	delete     widget.Clickable
-	toggle     widget.Clickable
-	terminal   widget.Clickable
-	logs       widget.Clickable
-	processing bool // true when an action is in progress
+	delete       widget.Clickable
+	toggle       widget.Clickable
+	terminal     widget.Clickable
+	connectivity widget.Clickable
+	logs         widget.Clickable
+	alerts       widget.Clickable
+	ports        map[string]*widget.Clickable // Port chips, by ContainerPort.String
+	processing   bool                         // true when an action is in progress
 }
 
 // projectRowButtons holds the button states for a project row.
@@ -44,6 +52,8 @@ type ContainersView struct {
 	theme            *Theme
 	docker           *docker.Client
 	settings         *config.Settings
+	watcher          *watch.Watcher
+	prober           *probe.Prober
 	list             widget.List
 	containerButtons map[string]*containerRowButtons
 	projectButtons   map[string]*projectRowButtons
@@ -67,14 +77,21 @@ type ContainersView struct {
 	pendingDeleteName string           // Display name for the dialog
 	confirmDelete     widget.Clickable // Confirm button
 	cancelDelete      widget.Clickable // Cancel button
+
+	// Containers revealed from another view (e.g. the users of an image)
+	revealIDs    map[string]bool
+	revealExpiry time.Time
+	revealScroll bool
 }
 
 // NewContainersView creates a new containers view.
-func NewContainersView(theme *Theme, dockerClient *docker.Client, settings *config.Settings) *ContainersView {
+func NewContainersView(theme *Theme, dockerClient *docker.Client, settings *config.Settings, watcher *watch.Watcher, invalidate func()) *ContainersView {
 	return &ContainersView{
 		theme:            theme,
 		docker:           dockerClient,
 		settings:         settings,
+		watcher:          watcher,
+		prober:           probe.New(invalidate),
 		list:             widget.List{List: layout.List{Axis: layout.Vertical}},
 		containerButtons: make(map[string]*containerRowButtons),
 		projectButtons:   make(map[string]*projectRowButtons),
@@ -86,7 +103,7 @@ func (v *ContainersView) getContainerButtons(containerID string) *containerRowBu
 	if btns, ok := v.containerButtons[containerID]; ok {
 		return btns
 	}
-	btns := &containerRowButtons{}
+	btns := &containerRowButtons{ports: make(map[string]*widget.Clickable)}
 	v.containerButtons[containerID] = btns
 	return btns
 }
@@ -101,6 +118,21 @@ func (v *ContainersView) getProjectButtons(projectName string) *projectRowButton
 	return btns
 }
 
+// Reveal scrolls to and briefly highlights the given containers.
+func (v *ContainersView) Reveal(containerIDs []string) {
+	v.revealIDs = make(map[string]bool, len(containerIDs))
+	for _, id := range containerIDs {
+		v.revealIDs[id] = true
+	}
+	v.revealExpiry = time.Now().Add(5 * time.Second)
+	v.revealScroll = true
+}
+
+// isRevealed reports whether a container is highlighted by Reveal.
+func (v *ContainersView) isRevealed(containerID string) bool {
+	return v.revealIDs[containerID] && time.Now().Before(v.revealExpiry)
+}
+
 // setError sets an error message to display.
 func (v *ContainersView) setError(msg string) {
 	v.errorMu.Lock()
@@ -194,6 +226,17 @@ func (v *ContainersView) Layout(gtx layout.Context, groups []docker.ContainerGro
 		}
 	}
 
+	// Scroll to the first revealed container
+	if v.revealScroll {
+		for i, item := range items {
+			if !item.isHeader && v.revealIDs[item.container.ID] {
+				v.list.Position = layout.Position{First: i}
+				v.revealScroll = false
+				break
+			}
+		}
+	}
+
 	return layout.Stack{}.Layout(gtx,
 		// Main content
 		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
@@ -502,13 +545,50 @@ func (v *ContainersView) layoutContainer(gtx layout.Context, c docker.Container)
 				}()
 			}
 		}
+		if btns.connectivity.Clicked(gtx) {
+			NewConnectivityWindow(v.theme, v.docker, v.settings, c)
+		}
 		if btns.logs.Clicked(gtx) {
-			containerID := c.ID
-			containerName := c.Name
-			NewLogsWindow(v.theme, v.docker, containerID, containerName)
+			NewLogsWindow(v.theme, v.docker, v.settings, c)
+		}
+		if btns.alerts.Clicked(gtx) {
+			// Opening the logs acknowledges the alerts
+			v.watcher.ClearAlerts(c.ID)
+			NewLogsWindow(v.theme, v.docker, v.settings, c)
+		}
+	}
+	// Port chips stay usable while an action is in progress
+	for _, p := range c.Ports {
+		if click, ok := btns.ports[p.String()]; ok && click.Clicked(gtx) && p.Type == "tcp" {
+			url := "http://" + net.JoinHostPort(portHost(p), strconv.Itoa(int(p.PublicPort)))
+			go func() {
+				if err := browser.Open(url); err != nil {
+					v.setError("Failed to open browser: " + err.Error())
+				}
+			}()
 		}
 	}
 
+	alerts := v.watcher.Alerts(c.ID)
+
+	return layout.Stack{}.Layout(gtx,
+		// Highlight rows matched by a watch rule with the highlight action
+		// or revealed from another view
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			if !alerts.Highlighted && !v.isRevealed(c.ID) {
+				return layout.Dimensions{}
+			}
+			return fillRounded(gtx, v.theme.Colors.SelectedBg, 4)
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutContainerRow(gtx, c, btns, alerts)
+		}),
+	)
+}
+
+func (v *ContainersView) layoutContainerRow(gtx layout.Context, c docker.Container, btns *containerRowButtons, alerts watch.ContainerAlerts) layout.Dimensions {
+	isRunning := c.State == "running"
+
 	return layout.Inset{
 		Top:    unit.Dp(4),
 		Bottom: unit.Dp(4),
@@ -547,16 +627,57 @@ func (v *ContainersView) layoutContainer(gtx layout.Context, c docker.Container)
 								label.Color = v.theme.Colors.TextMuted
 								return label.Layout(gtx)
 							}),
+							// Networks, to tell why two containers cannot reach each other
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								if len(c.Networks) == 0 {
+									return layout.Dimensions{}
+								}
+								names := make([]string, len(c.Networks))
+								for i, n := range c.Networks {
+									names[i] = n.Name
+								}
+								return layout.Inset{Left: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+									label := material.Caption(v.theme.Material, "• on "+strings.Join(names, ", "))
+									label.Color = v.theme.Colors.TextMuted
+									return label.Layout(gtx)
+								})
+							}),
 						)
 					}),
+					// Published ports
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return v.layoutPortChips(gtx, c, btns)
+					}),
 				)
 			}),
-			// Buttons (right-aligned): Logs, Terminal, Start/Stop, Delete
+			// Buttons (right-aligned): Alerts, Logs, Connectivity, Terminal, Start/Stop, Delete
+			// Alerts button (only shown when a watch rule matched)
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if alerts.Count == 0 {
+					return layout.Dimensions{}
+				}
+				label := intToStr(alerts.Count) + " alerts"
+				if alerts.Count == 1 {
+					label = "1 alert"
+				}
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, v.theme, &btns.alerts, label, true, false)
+				})
+			}),
 			// Logs button (available for all containers)
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 				return v.layoutButton(gtx, &btns.logs, "Logs", false, false)
 			}),
 			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			// Connectivity button (only shown when running)
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if !isRunning {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return v.layoutButton(gtx, &btns.connectivity, "Connectivity", false, false)
+				})
+			}),
 			// Terminal button (only shown when running)
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 				if !isRunning {
@@ -583,73 +704,123 @@ func (v *ContainersView) layoutContainer(gtx layout.Context, c docker.Container)
 	})
 }
 
-// toggleLabel returns the appropriate label for a start/stop button.
-func (v *ContainersView) toggleLabel(isRunning bool) string {
-	if isRunning {
-		return "Stop"
+// layoutPortChips renders a chip per published port. TCP ports of running
+// containers are probed for HTTP and open in the browser when clicked.
+func (v *ContainersView) layoutPortChips(gtx layout.Context, c docker.Container, btns *containerRowButtons) layout.Dimensions {
+	var chips []layout.FlexChild
+	for _, p := range c.Ports {
+		if !p.Published() {
+			continue
+		}
+		click, ok := btns.ports[p.String()]
+		if !ok {
+			click = new(widget.Clickable)
+			btns.ports[p.String()] = click
+		}
+		chips = append(chips, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(4), Right: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return v.layoutPortChip(gtx, c, p, click)
+			})
+		}))
 	}
-	return "Start"
+	if len(chips) == 0 {
+		return layout.Dimensions{}
+	}
+	return layout.Flex{Axis: layout.Horizontal}.Layout(gtx, chips...)
 }
 
-// layoutButton renders a small action button.
-func (v *ContainersView) layoutButton(gtx layout.Context, clickable *widget.Clickable, label string, isDanger bool, disabled bool) layout.Dimensions {
-	// When disabled, don't process clicks
-	if disabled {
-		return v.layoutButtonContent(gtx, label, isDanger, disabled, false)
+// layoutPortChip renders a single port like "localhost:8080 → 80/tcp" with
+// the outcome of the latest HTTP probe.
+func (v *ContainersView) layoutPortChip(gtx layout.Context, c docker.Container, p docker.ContainerPort, click *widget.Clickable) layout.Dimensions {
+	host := portHost(p)
+	text := net.JoinHostPort(host, strconv.Itoa(int(p.PublicPort))) + " → " + strconv.Itoa(int(p.PrivatePort)) + "/" + p.Type
+
+	status, statusColor := "", v.theme.Colors.TextMuted
+	if p.Type == "tcp" && c.State == "running" {
+		status, statusColor = v.probeStatus(v.prober.Result(host, p.PublicPort))
 	}
 
-	return clickable.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-		return v.layoutButtonContent(gtx, label, isDanger, disabled, clickable.Hovered())
-	})
+	content := func(gtx layout.Context) layout.Dimensions {
+		return layout.Background{}.Layout(gtx,
+			func(gtx layout.Context) layout.Dimensions {
+				bg := v.theme.Colors.ButtonBg
+				if click.Hovered() && p.Type == "tcp" {
+					bg = v.theme.Colors.ButtonHover
+				}
+				return fillRounded(gtx, bg, 4)
+			},
+			func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Top: unit.Dp(2), Bottom: unit.Dp(2), Left: unit.Dp(6), Right: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							label := material.Caption(v.theme.Material, text)
+							label.Color = v.theme.Colors.TextSecondary
+							return label.Layout(gtx)
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							if status == "" {
+								return layout.Dimensions{}
+							}
+							return layout.Inset{Left: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+								label := material.Caption(v.theme.Material, status)
+								label.Color = statusColor
+								return label.Layout(gtx)
+							})
+						}),
+					)
+				})
+			},
+		)
+	}
+	// Only TCP ports can be opened in the browser
+	if p.Type != "tcp" {
+		return content(gtx)
+	}
+	return material.Clickable(gtx, click, content)
 }
 
-// layoutButtonContent renders the button content with appropriate styling.
-func (v *ContainersView) layoutButtonContent(gtx layout.Context, label string, isDanger bool, disabled bool, hovered bool) layout.Dimensions {
-	// Determine colors
-	bgColor := v.theme.Colors.ButtonBg
-	textColor := v.theme.Colors.Text
-
-	if disabled {
-		textColor = v.theme.Colors.TextMuted
-	} else if hovered {
-		if isDanger {
-			bgColor = v.theme.Colors.ButtonDangerHov
-		} else {
-			bgColor = v.theme.Colors.ButtonHover
+// probeStatus returns the text and color showing the outcome of an HTTP probe.
+func (v *ContainersView) probeStatus(r probe.Result) (string, color.NRGBA) {
+	switch r.State {
+	case probe.StateHTTP:
+		switch {
+		case r.StatusCode >= 500:
+			return intToStr(r.StatusCode), v.theme.Colors.StatusStopped
+		case r.StatusCode >= 400:
+			return intToStr(r.StatusCode), v.theme.Colors.StatusPaused
+		default:
+			return intToStr(r.StatusCode), v.theme.Colors.StatusRunning
 		}
-	} else if isDanger {
-		bgColor = v.theme.Colors.ButtonDanger
+	case probe.StateNotHTTP:
+		return "no HTTP", v.theme.Colors.TextMuted
+	case probe.StateUnreachable:
+		return "unreachable", v.theme.Colors.StatusStopped
+	default:
+		return "…", v.theme.Colors.TextMuted
 	}
+}
 
-	// Show "..." when disabled (processing)
-	displayLabel := label
-	if disabled {
-		displayLabel = label + "..."
+// portHost returns the host address to reach a published port on; ports
+// published on all interfaces are reached through localhost.
+func portHost(p docker.ContainerPort) string {
+	switch p.IP {
+	case "", "0.0.0.0", "::":
+		return "localhost"
 	}
+	return p.IP
+}
 
-	return layout.Stack{}.Layout(gtx,
-		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
-			rr := gtx.Dp(unit.Dp(4))
-			rect := clip.RRect{
-				Rect: image.Rectangle{Max: gtx.Constraints.Min},
-				NE:   rr, NW: rr, SE: rr, SW: rr,
-			}
-			paint.FillShape(gtx.Ops, bgColor, rect.Op(gtx.Ops))
-			return layout.Dimensions{Size: gtx.Constraints.Min}
-		}),
-		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
-			return layout.Inset{
-				Top:    unit.Dp(4),
-				Bottom: unit.Dp(4),
-				Left:   unit.Dp(8),
-				Right:  unit.Dp(8),
-			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				lbl := material.Caption(v.theme.Material, displayLabel)
-				lbl.Color = textColor
-				return lbl.Layout(gtx)
-			})
-		}),
-	)
+// toggleLabel returns the appropriate label for a start/stop button.
+func (v *ContainersView) toggleLabel(isRunning bool) string {
+	if isRunning {
+		return "Stop"
+	}
+	return "Start"
+}
+
+// layoutButton renders a small action button.
+func (v *ContainersView) layoutButton(gtx layout.Context, clickable *widget.Clickable, label string, isDanger bool, disabled bool) layout.Dimensions {
+	return layoutActionButton(gtx, v.theme, clickable, label, isDanger, disabled)
 }
 
 // layoutConfirmDialog renders a modal confirmation dialog overlay.
@@ -705,128 +876,38 @@ func (v *ContainersView) layoutConfirmDialog(gtx layout.Context) layout.Dimensio
 		return layout.Dimensions{Size: gtx.Constraints.Max}
 	}
 
-	// Semi-transparent backdrop
-	return layout.Stack{}.Layout(gtx,
-		// Backdrop
-		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
-			paint.FillShape(gtx.Ops, rgba(0x000000, 0xCC), clip.Rect{Max: gtx.Constraints.Max}.Op())
-			return layout.Dimensions{Size: gtx.Constraints.Max}
-		}),
-		// Dialog (use Expanded to fill the space, then Center within it)
-		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
-			return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				// Constrain dialog width
-				gtx.Constraints.Max.X = gtx.Dp(unit.Dp(400))
-				gtx.Constraints.Min.X = gtx.Dp(unit.Dp(300))
-
-				return layout.Stack{}.Layout(gtx,
-					// Dialog background
-					layout.Expanded(func(gtx layout.Context) layout.Dimensions {
-						rr := gtx.Dp(unit.Dp(8))
-						rect := clip.RRect{
-							Rect: image.Rectangle{Max: gtx.Constraints.Min},
-							NE:   rr, NW: rr, SE: rr, SW: rr,
-						}
-						paint.FillShape(gtx.Ops, v.theme.Colors.Surface, rect.Op(gtx.Ops))
-						return layout.Dimensions{Size: gtx.Constraints.Min}
-					}),
-					// Dialog content
-					layout.Stacked(func(gtx layout.Context) layout.Dimensions {
-						return layout.Inset{
-							Top:    unit.Dp(20),
-							Bottom: unit.Dp(20),
-							Left:   unit.Dp(24),
-							Right:  unit.Dp(24),
-						}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-							return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
-								// Title
-								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-									title := material.H6(v.theme.Material, "Confirm Delete")
-									title.Color = v.theme.Colors.Text
-									return title.Layout(gtx)
-								}),
-								// Spacing
-								layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
-								// Message
-								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-									msg := "Are you sure you want to delete \"" + v.pendingDeleteName + "\"?"
-									label := material.Body1(v.theme.Material, msg)
-									label.Color = v.theme.Colors.TextSecondary
-									return label.Layout(gtx)
-								}),
-								// Spacing
-								layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
-								// Buttons
-								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-									return layout.Flex{
-										Axis:    layout.Horizontal,
-										Spacing: layout.SpaceStart,
-									}.Layout(gtx,
-										layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
-											return layout.Dimensions{}
-										}),
-										// Cancel button
-										layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-											return v.cancelDelete.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-												return v.layoutDialogButton(gtx, "Cancel", false, v.cancelDelete.Hovered())
-											})
-										}),
-										layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
-										// Delete button
-										layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-											return v.confirmDelete.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-												return v.layoutDialogButton(gtx, "Delete", true, v.confirmDelete.Hovered())
-											})
-										}),
-									)
-								}),
-							)
-						})
-					}),
+	return layoutModal(gtx, v.theme, unit.Dp(400), func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := material.H6(v.theme.Material, "Confirm Delete")
+				title.Color = v.theme.Colors.Text
+				return title.Layout(gtx)
+			}),
+			// Spacing
+			layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+			// Message
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				msg := "Are you sure you want to delete \"" + v.pendingDeleteName + "\"?"
+				label := material.Body1(v.theme.Material, msg)
+				label.Color = v.theme.Colors.TextSecondary
+				return label.Layout(gtx)
+			}),
+			// Spacing
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &v.cancelDelete, "Cancel", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &v.confirmDelete, "Delete", true)
+					},
 				)
-			})
-		}),
-	)
-}
-
-// layoutDialogButton renders a button for the confirmation dialog.
-func (v *ContainersView) layoutDialogButton(gtx layout.Context, label string, isDanger bool, hovered bool) layout.Dimensions {
-	bgColor := v.theme.Colors.ButtonBg
-	textColor := v.theme.Colors.Text
-
-	if hovered {
-		if isDanger {
-			bgColor = v.theme.Colors.ButtonDangerHov
-		} else {
-			bgColor = v.theme.Colors.ButtonHover
-		}
-	} else if isDanger {
-		bgColor = v.theme.Colors.ButtonDanger
-	}
-
-	return layout.Stack{}.Layout(gtx,
-		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
-			rr := gtx.Dp(unit.Dp(4))
-			rect := clip.RRect{
-				Rect: image.Rectangle{Max: gtx.Constraints.Min},
-				NE:   rr, NW: rr, SE: rr, SW: rr,
-			}
-			paint.FillShape(gtx.Ops, bgColor, rect.Op(gtx.Ops))
-			return layout.Dimensions{Size: gtx.Constraints.Min}
-		}),
-		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
-			return layout.Inset{
-				Top:    unit.Dp(8),
-				Bottom: unit.Dp(8),
-				Left:   unit.Dp(16),
-				Right:  unit.Dp(16),
-			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				lbl := material.Body2(v.theme.Material, label)
-				lbl.Color = textColor
-				return lbl.Layout(gtx)
-			})
-		}),
-	)
+			}),
+		)
+	})
 }
 
 func (v *ContainersView) layoutEmpty(gtx layout.Context) layout.Dimensions {
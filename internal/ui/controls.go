@@ -0,0 +1,442 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// layoutActionButton renders a small action button as used in list rows.
+func layoutActionButton(gtx layout.Context, theme *Theme, clickable *widget.Clickable, label string, isDanger bool, disabled bool) layout.Dimensions {
+	// When disabled, don't process clicks
+	if disabled {
+		return layoutActionButtonContent(gtx, theme, label, isDanger, disabled, false)
+	}
+
+	return clickable.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layoutActionButtonContent(gtx, theme, label, isDanger, disabled, clickable.Hovered())
+	})
+}
+
+// layoutActionButtonContent renders the button content with appropriate styling.
+func layoutActionButtonContent(gtx layout.Context, theme *Theme, label string, isDanger bool, disabled bool, hovered bool) layout.Dimensions {
+	// Determine colors
+	bgColor := theme.Colors.ButtonBg
+	textColor := theme.Colors.Text
+
+	if disabled {
+		textColor = theme.Colors.TextMuted
+	} else if hovered {
+		if isDanger {
+			bgColor = theme.Colors.ButtonDangerHov
+		} else {
+			bgColor = theme.Colors.ButtonHover
+		}
+	} else if isDanger {
+		bgColor = theme.Colors.ButtonDanger
+	}
+
+	// Show "..." when disabled (processing)
+	displayLabel := label
+	if disabled {
+		displayLabel = label + "..."
+	}
+
+	return layout.Stack{}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return fillRounded(gtx, bgColor, 4)
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{
+				Top:    unit.Dp(4),
+				Bottom: unit.Dp(4),
+				Left:   unit.Dp(8),
+				Right:  unit.Dp(8),
+			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				lbl := material.Caption(theme.Material, displayLabel)
+				lbl.Color = textColor
+				return lbl.Layout(gtx)
+			})
+		}),
+	)
+}
+
+// layoutDialogButton renders a larger button for use in dialogs.
+func layoutDialogButton(gtx layout.Context, theme *Theme, clickable *widget.Clickable, label string, isDanger bool) layout.Dimensions {
+	return clickable.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layoutDialogButtonContent(gtx, theme, label, isDanger, clickable.Hovered())
+	})
+}
+
+// layoutDialogButtonContent renders the dialog button content with appropriate styling.
+func layoutDialogButtonContent(gtx layout.Context, theme *Theme, label string, isDanger bool, hovered bool) layout.Dimensions {
+	bgColor := theme.Colors.ButtonBg
+	textColor := theme.Colors.Text
+
+	if hovered {
+		if isDanger {
+			bgColor = theme.Colors.ButtonDangerHov
+		} else {
+			bgColor = theme.Colors.ButtonHover
+		}
+	} else if isDanger {
+		bgColor = theme.Colors.ButtonDanger
+	}
+
+	return layout.Stack{}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return fillRounded(gtx, bgColor, 4)
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{
+				Top:    unit.Dp(8),
+				Bottom: unit.Dp(8),
+				Left:   unit.Dp(16),
+				Right:  unit.Dp(16),
+			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				lbl := material.Body2(theme.Material, label)
+				lbl.Color = textColor
+				return lbl.Layout(gtx)
+			})
+		}),
+	)
+}
+
+// layoutModal renders content in a centered dialog card over a dimmed backdrop.
+func layoutModal(gtx layout.Context, theme *Theme, width unit.Dp, content layout.Widget) layout.Dimensions {
+	return layout.Stack{}.Layout(gtx,
+		// Backdrop
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			paint.FillShape(gtx.Ops, rgba(0x000000, 0xCC), clip.Rect{Max: gtx.Constraints.Max}.Op())
+			return layout.Dimensions{Size: gtx.Constraints.Max}
+		}),
+		// Dialog (use Expanded to fill the space, then Center within it)
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				// Constrain dialog width
+				gtx.Constraints.Max.X = min(gtx.Dp(width), gtx.Constraints.Max.X)
+				gtx.Constraints.Min.X = min(gtx.Dp(unit.Dp(300)), gtx.Constraints.Max.X)
+
+				return layout.Stack{}.Layout(gtx,
+					// Dialog background
+					layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+						return fillRounded(gtx, theme.Colors.Surface, 8)
+					}),
+					// Dialog content
+					layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{
+							Top:    unit.Dp(20),
+							Bottom: unit.Dp(20),
+							Left:   unit.Dp(24),
+							Right:  unit.Dp(24),
+						}.Layout(gtx, content)
+					}),
+				)
+			})
+		}),
+	)
+}
+
+// layoutDialogButtons renders right-aligned dialog buttons.
+func layoutDialogButtons(gtx layout.Context, buttons ...layout.Widget) layout.Dimensions {
+	children := []layout.FlexChild{
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return layout.Dimensions{}
+		}),
+	}
+	for i, button := range buttons {
+		if i > 0 {
+			children = append(children, layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout))
+		}
+		children = append(children, layout.Rigid(button))
+	}
+	return layout.Flex{Axis: layout.Horizontal, Spacing: layout.SpaceStart}.Layout(gtx, children...)
+}
+
+// layoutTextField renders a single-line text input with a label above it.
+func layoutTextField(gtx layout.Context, theme *Theme, editor *widget.Editor, label, hint string) layout.Dimensions {
+	editor.SingleLine = true
+	return layoutEditorField(gtx, theme, editor, label, hint)
+}
+
+// layoutTextArea renders a multi-line text input with a label above it.
+func layoutTextArea(gtx layout.Context, theme *Theme, editor *widget.Editor, label, hint string) layout.Dimensions {
+	editor.SingleLine = false
+	return layoutEditorField(gtx, theme, editor, label, hint)
+}
+
+// layoutEditorField renders a labeled editor on a card background.
+func layoutEditorField(gtx layout.Context, theme *Theme, editor *widget.Editor, label, hint string) layout.Dimensions {
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if label == "" {
+				return layout.Dimensions{}
+			}
+			return layout.Inset{Bottom: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				lbl := material.Caption(theme.Material, label)
+				lbl.Color = theme.Colors.TextSecondary
+				return lbl.Layout(gtx)
+			})
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Stack{}.Layout(gtx,
+				layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+					return fillRounded(gtx, theme.Colors.CardBg, 4)
+				}),
+				layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Constraints.Max.X
+					return layout.Inset{
+						Top:    unit.Dp(6),
+						Bottom: unit.Dp(6),
+						Left:   unit.Dp(8),
+						Right:  unit.Dp(8),
+					}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						ed := material.Editor(theme.Material, editor, hint)
+						ed.Color = theme.Colors.Text
+						ed.HintColor = theme.Colors.TextMuted
+						ed.SelectionColor = theme.Colors.SelectedBg
+						ed.TextSize = unit.Sp(14)
+						return ed.Layout(gtx)
+					})
+				}),
+			)
+		}),
+	)
+}
+
+// layoutRadioButton renders a radio option bound to an enum.
+func layoutRadioButton(gtx layout.Context, theme *Theme, group *widget.Enum, key, label string) layout.Dimensions {
+	rb := material.RadioButton(theme.Material, group, key, label)
+	rb.Color = theme.Colors.Text
+	rb.IconColor = theme.Colors.Primary
+	rb.Size = unit.Dp(20)
+	rb.TextSize = unit.Sp(14)
+	return rb.Layout(gtx)
+}
+
+// radioOption is a single choice of a radio button group.
+type radioOption struct {
+	key   string
+	label string
+}
+
+// layoutRadioRow renders radio buttons for a set of options in a single row.
+func layoutRadioRow(gtx layout.Context, theme *Theme, group *widget.Enum, options []radioOption) layout.Dimensions {
+	children := make([]layout.FlexChild, 0, len(options)*2)
+	for _, opt := range options {
+		children = append(children,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutRadioButton(gtx, theme, group, opt.key, opt.label)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(16)}.Layout),
+		)
+	}
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx, children...)
+}
+
+// layoutRadioGrid renders radio buttons for a set of options in rows of equal-width columns.
+func layoutRadioGrid(gtx layout.Context, theme *Theme, group *widget.Enum, options []radioOption, columns int) layout.Dimensions {
+	var rows []layout.FlexChild
+	for start := 0; start < len(options); start += columns {
+		row := options[start:min(start+columns, len(options))]
+		rows = append(rows, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			children := make([]layout.FlexChild, columns)
+			for i := range children {
+				if i >= len(row) {
+					children[i] = layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return layout.Dimensions{}
+					})
+					continue
+				}
+				opt := row[i]
+				children[i] = layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					return layoutRadioButton(gtx, theme, group, opt.key, opt.label)
+				})
+			}
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx, children...)
+		}))
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx, rows...)
+}
+
+// layoutTabBar renders a row of tabs bound to an enum. The selected tab is underlined.
+func layoutTabBar(gtx layout.Context, theme *Theme, group *widget.Enum, tabs []radioOption) layout.Dimensions {
+	children := make([]layout.FlexChild, 0, len(tabs))
+	for _, tab := range tabs {
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return group.Layout(gtx, tab.key, func(gtx layout.Context) layout.Dimensions {
+				selected := group.Value == tab.key
+				textColor := theme.Colors.TextSecondary
+				if selected {
+					textColor = theme.Colors.Text
+				} else if hovered, ok := group.Hovered(); ok && hovered == tab.key {
+					textColor = theme.Colors.Text
+				}
+
+				return layout.Stack{}.Layout(gtx,
+					layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{Top: unit.Dp(6), Bottom: unit.Dp(8), Left: unit.Dp(12), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							lbl := material.Body2(theme.Material, tab.label)
+							lbl.Color = textColor
+							return lbl.Layout(gtx)
+						})
+					}),
+					// Underline of the selected tab
+					layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+						if selected {
+							height := gtx.Dp(unit.Dp(2))
+							rect := image.Rect(0, gtx.Constraints.Min.Y-height, gtx.Constraints.Min.X, gtx.Constraints.Min.Y)
+							paint.FillShape(gtx.Ops, theme.Colors.Primary, clip.Rect(rect).Op())
+						}
+						return layout.Dimensions{Size: gtx.Constraints.Min}
+					}),
+				)
+			})
+		}))
+	}
+	return layout.Flex{Axis: layout.Horizontal}.Layout(gtx, children...)
+}
+
+// layoutCheckBox renders a check box bound to a bool.
+func layoutCheckBox(gtx layout.Context, theme *Theme, value *widget.Bool, label string) layout.Dimensions {
+	cb := material.CheckBox(theme.Material, value, label)
+	cb.Color = theme.Colors.Text
+	cb.IconColor = theme.Colors.Primary
+	cb.Size = unit.Dp(20)
+	cb.TextSize = unit.Sp(14)
+	return cb.Layout(gtx)
+}
+
+// layoutSectionLabel renders a small muted label used above groups of dialog controls.
+func layoutSectionLabel(gtx layout.Context, theme *Theme, text string) layout.Dimensions {
+	return layout.Inset{Top: unit.Dp(12), Bottom: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		lbl := material.Caption(theme.Material, text)
+		lbl.Color = theme.Colors.TextSecondary
+		return lbl.Layout(gtx)
+	})
+}
+
+// fillRounded fills the minimum constraints with a rounded rectangle.
+func fillRounded(gtx layout.Context, c color.NRGBA, radius unit.Dp) layout.Dimensions {
+	rr := gtx.Dp(radius)
+	rect := clip.RRect{
+		Rect: image.Rectangle{Max: gtx.Constraints.Min},
+		NE:   rr, NW: rr, SE: rr, SW: rr,
+	}
+	paint.FillShape(gtx.Ops, c, rect.Op(gtx.Ops))
+	return layout.Dimensions{Size: gtx.Constraints.Min}
+}
+
+// layoutProgressBar renders a thin progress bar.
+func layoutProgressBar(gtx layout.Context, theme *Theme, fraction float32) layout.Dimensions {
+	bar := material.ProgressBar(theme.Material, fraction)
+	bar.Color = theme.Colors.Primary
+	bar.TrackColor = theme.Colors.CardBg
+	bar.Height = unit.Dp(4)
+	bar.Radius = unit.Dp(2)
+	return bar.Layout(gtx)
+}
+
+// layoutDialogText renders a paragraph of dialog text.
+func layoutDialogText(gtx layout.Context, theme *Theme, text string) layout.Dimensions {
+	label := material.Body2(theme.Material, text)
+	label.Color = theme.Colors.TextSecondary
+	return label.Layout(gtx)
+}
+
+// layoutItemList renders a scrollable list of monospace lines with a limited height.
+func layoutItemList(gtx layout.Context, theme *Theme, list *widget.List, lines []string) layout.Dimensions {
+	list.Axis = layout.Vertical
+	return layout.Inset{Top: unit.Dp(8), Bottom: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		gtx.Constraints.Max.Y = min(gtx.Constraints.Max.Y, gtx.Dp(unit.Dp(200)))
+		return material.List(theme.Material, list).Layout(gtx, len(lines), func(gtx layout.Context, index int) layout.Dimensions {
+			label := material.Caption(theme.Material, lines[index])
+			label.Color = theme.Colors.Text
+			label.Font.Typeface = "monospace"
+			return layout.Inset{Bottom: unit.Dp(2)}.Layout(gtx, label.Layout)
+		})
+	})
+}
+
+// Usage filters of the image and volume lists, keyed for widget.Enum
+const (
+	usageFilterAll    = "all"
+	usageFilterInUse  = "used"
+	usageFilterUnused = "unused"
+)
+
+var usageFilters = []radioOption{
+	{key: usageFilterAll, label: "All"},
+	{key: usageFilterInUse, label: "In use"},
+	{key: usageFilterUnused, label: "Unused"},
+}
+
+// layoutUsedBy renders the number of running and stopped containers using an
+// image or volume. Clicking it shows them in the containers view.
+func layoutUsedBy(gtx layout.Context, theme *Theme, click *widget.Clickable, containers []docker.Container) layout.Dimensions {
+	var running, stopped int
+	for _, ctr := range containers {
+		if ctr.State == "running" {
+			running++
+		} else {
+			stopped++
+		}
+	}
+
+	var parts []string
+	if running > 0 {
+		parts = append(parts, intToStr(running)+" running")
+	}
+	if stopped > 0 {
+		parts = append(parts, intToStr(stopped)+" stopped")
+	}
+
+	gtx.Constraints.Min.X = gtx.Dp(unit.Dp(140))
+	return layout.Inset{Right: unit.Dp(16)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		if len(parts) == 0 {
+			label := material.Caption(theme.Material, "Unused")
+			label.Color = theme.Colors.TextMuted
+			return label.Layout(gtx)
+		}
+		return click.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			label := material.Caption(theme.Material, "Used by "+strings.Join(parts, ", "))
+			label.Color = theme.Colors.Primary
+			if click.Hovered() {
+				label.Color = theme.Colors.Text
+			}
+			return label.Layout(gtx)
+		})
+	})
+}
+
+// layoutTransferProgress renders the bytes transferred, with a bar if the total is known.
+func layoutTransferProgress(gtx layout.Context, theme *Theme, sent, total int64) layout.Dimensions {
+	text := docker.FormatSize(sent)
+	if total > 0 {
+		text += " of " + docker.FormatSize(total)
+	}
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			label := material.Caption(theme.Material, text)
+			label.Color = theme.Colors.TextMuted
+			return label.Layout(gtx)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if total <= 0 {
+				return layout.Dimensions{}
+			}
+			return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layoutProgressBar(gtx, theme, float32(sent)/float32(total))
+			})
+		}),
+	)
+}
@@ -0,0 +1,493 @@
+package ui
+
+import (
+	"context"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// wastedSelection is the layer index used to show the wasted-space list instead of a layer.
+const wastedSelection = -1
+
+// maxWastedFiles limits the wasted-space list.
+const maxWastedFiles = 200
+
+// imageFilesState holds the layer explorer of an image window.
+type imageFilesState struct {
+	analyze    widget.Clickable
+	analyzing  bool
+	bytesRead  int64
+	analysis   *docker.ImageAnalysis
+	analyzeErr string
+
+	shown        *docker.ImageAnalysis // Analysis the selection below belongs to
+	selected     int
+	layerButtons []widget.Clickable
+	layerList    widget.List
+	treeList     widget.List
+
+	// Tree of the selected layer
+	tree       *changeNode
+	treeLayer  int
+	expanded   map[string]bool
+	dirToggles map[string]*widget.Clickable
+}
+
+// changeNode is a directory or file in the change tree of a layer.
+type changeNode struct {
+	name     string
+	path     string
+	size     int64
+	isDir    bool
+	change   docker.FileChange
+	explicit bool // The layer itself changed this path
+	children []*changeNode
+}
+
+// changeRow is a visible row of the flattened change tree.
+type changeRow struct {
+	node  *changeNode
+	depth int
+}
+
+// startAnalysis exports the image and analyzes its layers in the background.
+func (iw *ImageWindow) startAnalysis() {
+	f := &iw.files
+	iw.mu.Lock()
+	f.analyzing = true
+	f.bytesRead = 0
+	f.analyzeErr = ""
+	iw.mu.Unlock()
+
+	go func() {
+		defer iw.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		analysis, err := iw.docker.AnalyzeImage(ctx, iw.image.ID, func(n int64) {
+			iw.mu.Lock()
+			f.bytesRead = n
+			iw.mu.Unlock()
+			iw.invalidate()
+		})
+
+		iw.mu.Lock()
+		defer iw.mu.Unlock()
+		f.analyzing = false
+		if err != nil {
+			f.analyzeErr = err.Error()
+			return
+		}
+		f.analysis = &analysis
+	}()
+}
+
+// buildChangeTree arranges the changes of a layer as a tree. Directories that
+// the layer did not change itself are added so every change has a parent.
+func buildChangeTree(changes []docker.LayerChange) *changeNode {
+	root := &changeNode{isDir: true}
+	nodes := map[string]*changeNode{"": root}
+
+	var ensure func(p string) *changeNode
+	ensure = func(p string) *changeNode {
+		if n, ok := nodes[p]; ok {
+			return n
+		}
+		dir := path.Dir(p)
+		if dir == "." {
+			dir = ""
+		}
+		parent := ensure(dir)
+		n := &changeNode{name: path.Base(p), path: p, isDir: true}
+		parent.children = append(parent.children, n)
+		nodes[p] = n
+		return n
+	}
+
+	for _, c := range changes {
+		n := ensure(c.Path)
+		n.isDir = c.IsDir
+		n.change = c.Change
+		n.explicit = true
+		n.size = c.Size
+	}
+
+	// Sum sizes of implicit directories and sort directories first
+	var finish func(n *changeNode) int64
+	finish = func(n *changeNode) int64 {
+		var total int64
+		for _, child := range n.children {
+			total += finish(child)
+		}
+		sort.Slice(n.children, func(i, j int) bool {
+			a, b := n.children[i], n.children[j]
+			if a.isDir != b.isDir {
+				return a.isDir
+			}
+			return a.name < b.name
+		})
+		if n.isDir && (!n.explicit || n.change != docker.FileDeleted) {
+			n.size = total
+		}
+		return n.size
+	}
+	finish(root)
+	return root
+}
+
+// visibleRows flattens the expanded part of the tree.
+func (f *imageFilesState) visibleRows() []changeRow {
+	var rows []changeRow
+	var walk func(n *changeNode, depth int)
+	walk = func(n *changeNode, depth int) {
+		for _, child := range n.children {
+			rows = append(rows, changeRow{node: child, depth: depth})
+			if len(child.children) > 0 && f.expanded[child.path] {
+				walk(child, depth+1)
+			}
+		}
+	}
+	if f.tree != nil {
+		walk(f.tree, 0)
+	}
+	return rows
+}
+
+// dirToggle returns the clickable used to expand a directory.
+func (f *imageFilesState) dirToggle(p string) *widget.Clickable {
+	if c, ok := f.dirToggles[p]; ok {
+		return c
+	}
+	c := new(widget.Clickable)
+	f.dirToggles[p] = c
+	return c
+}
+
+// layerInstructions returns the instruction of every non-empty history step,
+// which correspond to the layers of the image in order.
+func layerInstructions(detail docker.ImageDetail, layers int) []string {
+	var instructions []string
+	for _, l := range detail.Layers {
+		if !l.Empty() {
+			instructions = append(instructions, l.Instruction)
+		}
+	}
+	if len(instructions) != layers {
+		return nil
+	}
+	return instructions
+}
+
+func (iw *ImageWindow) layoutFiles(gtx layout.Context, detail docker.ImageDetail) layout.Dimensions {
+	f := &iw.files
+
+	iw.mu.RLock()
+	analyzing := f.analyzing
+	bytesRead := f.bytesRead
+	analysis := f.analysis
+	analyzeErr := f.analyzeErr
+	iw.mu.RUnlock()
+
+	if f.analyze.Clicked(gtx) && !analyzing {
+		iw.startAnalysis()
+		analyzing = true
+	}
+
+	if analysis == nil {
+		return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			msg := "Reads every layer of the image to show which files each layer adds, modifies and deletes, and how much space is wasted by files that later layers overwrite or delete."
+			switch {
+			case analyzing:
+				msg = "Reading image… " + docker.FormatSize(bytesRead)
+			case analyzeErr != "":
+				msg = "Failed to analyze image: " + analyzeErr
+			}
+			return layout.Flex{Axis: layout.Vertical, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(unit.Dp(480)))
+					label := material.Body2(iw.theme.Material, msg)
+					label.Color = iw.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				}),
+				layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, iw.theme, &f.analyze, "Analyze Layers", false, analyzing)
+				}),
+			)
+		})
+	}
+
+	// A new analysis resets the selection to the lowest layer
+	if analysis != f.shown {
+		f.shown = analysis
+		f.selected = 0
+		f.treeLayer = math.MinInt
+		f.layerButtons = make([]widget.Clickable, len(analysis.Layers)+1)
+	}
+	for i := range f.layerButtons {
+		if f.layerButtons[i].Clicked(gtx) {
+			f.selected = i - 1 // The first button selects the wasted-space list
+		}
+	}
+	if f.treeLayer != f.selected && f.selected >= 0 {
+		f.tree = buildChangeTree(analysis.Layers[f.selected].Changes)
+		f.treeLayer = f.selected
+		f.expanded = make(map[string]bool)
+		f.dirToggles = make(map[string]*widget.Clickable)
+	}
+
+	instructions := layerInstructions(detail, len(analysis.Layers))
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		// Efficiency summary
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Bottom: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return iw.layoutEfficiency(gtx, analysis)
+			})
+		}),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+				// Layers
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Dp(unit.Dp(300))
+					gtx.Constraints.Max.X = gtx.Constraints.Min.X
+					f.layerList.Axis = layout.Vertical
+					return material.List(iw.theme.Material, &f.layerList).Layout(gtx, len(f.layerButtons), func(gtx layout.Context, index int) layout.Dimensions {
+						if index == 0 {
+							return iw.layoutLayerButton(gtx, &f.layerButtons[0], "Wasted space", docker.FormatSize(analysis.WastedBytes)+" in "+intToStr(len(analysis.Wasted))+" paths", f.selected == wastedSelection)
+						}
+						layer := analysis.Layers[index-1]
+						title := "Layer " + intToStr(index)
+						if instructions != nil {
+							title = instructions[index-1]
+						}
+						return iw.layoutLayerButton(gtx, &f.layerButtons[index], title, layerChangeSummary(layer), f.selected == index-1)
+					})
+				}),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+				// Selected layer or wasted space
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					if f.selected == wastedSelection {
+						return iw.layoutWastedFiles(gtx, analysis.Wasted)
+					}
+					return iw.layoutChangeTree(gtx)
+				}),
+			)
+		}),
+	)
+}
+
+// layerChangeSummary returns the size and the number of added, modified and deleted paths of a layer.
+func layerChangeSummary(layer docker.LayerContents) string {
+	var added, modified, deleted int
+	for _, c := range layer.Changes {
+		switch c.Change {
+		case docker.FileAdded:
+			added++
+		case docker.FileModified:
+			modified++
+		case docker.FileDeleted:
+			deleted++
+		}
+	}
+	return docker.FormatSize(layer.Size) + " • +" + intToStr(added) + " ~" + intToStr(modified) + " -" + intToStr(deleted)
+}
+
+func (iw *ImageWindow) layoutEfficiency(gtx layout.Context, analysis *docker.ImageAnalysis) layout.Dimensions {
+	efficiency := analysis.Efficiency()
+	scoreColor := iw.theme.Colors.StatusRunning
+	switch {
+	case efficiency < 0.8:
+		scoreColor = iw.theme.Colors.StatusStopped
+	case efficiency < 0.95:
+		scoreColor = iw.theme.Colors.StatusPaused
+	}
+	percent := int(math.Floor(efficiency * 1000)) // Tenths of a percent
+	score := intToStr(percent/10) + "." + intToStr(percent%10) + "%"
+
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Baseline}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			label := material.Body1(iw.theme.Material, "Efficiency "+score)
+			label.Color = scoreColor
+			return label.Layout(gtx)
+		}),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			label := material.Body2(iw.theme.Material, docker.FormatSize(analysis.WastedBytes)+" wasted of "+docker.FormatSize(analysis.TotalBytes)+" stored in "+intToStr(len(analysis.Layers))+" layers")
+			label.Color = iw.theme.Colors.TextMuted
+			return label.Layout(gtx)
+		}),
+	)
+}
+
+func (iw *ImageWindow) layoutLayerButton(gtx layout.Context, clickable *widget.Clickable, title, details string, selected bool) layout.Dimensions {
+	return layout.Inset{Bottom: unit.Dp(4), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return clickable.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Stack{}.Layout(gtx,
+				layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+					bgColor := iw.theme.Colors.CardBg
+					if selected {
+						bgColor = iw.theme.Colors.SelectedBg
+					} else if clickable.Hovered() {
+						bgColor = iw.theme.Colors.ButtonHover
+					}
+					return fillRounded(gtx, bgColor, 4)
+				}),
+				layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Constraints.Max.X
+					return layout.Inset{Top: unit.Dp(6), Bottom: unit.Dp(6), Left: unit.Dp(8), Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								label := material.Caption(iw.theme.Material, title)
+								label.Color = iw.theme.Colors.Text
+								label.Font.Typeface = "monospace"
+								label.MaxLines = 2
+								return label.Layout(gtx)
+							}),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								label := material.Caption(iw.theme.Material, details)
+								label.Color = iw.theme.Colors.TextMuted
+								return label.Layout(gtx)
+							}),
+						)
+					})
+				}),
+			)
+		})
+	})
+}
+
+func (iw *ImageWindow) layoutChangeTree(gtx layout.Context) layout.Dimensions {
+	f := &iw.files
+
+	// Handle directory toggles before flattening the tree
+	for p, toggle := range f.dirToggles {
+		if toggle.Clicked(gtx) {
+			f.expanded[p] = !f.expanded[p]
+		}
+	}
+
+	rows := f.visibleRows()
+	if len(rows) == 0 {
+		return iw.layoutMessage(gtx, "This layer does not change any files")
+	}
+
+	f.treeList.Axis = layout.Vertical
+	return material.List(iw.theme.Material, &f.treeList).Layout(gtx, len(rows), func(gtx layout.Context, index int) layout.Dimensions {
+		return iw.layoutChangeRow(gtx, rows[index])
+	})
+}
+
+func (iw *ImageWindow) layoutChangeRow(gtx layout.Context, row changeRow) layout.Dimensions {
+	n := row.node
+	f := &iw.files
+
+	nameColor := iw.theme.Colors.TextSecondary
+	marker := " "
+	if n.explicit {
+		switch n.change {
+		case docker.FileAdded:
+			nameColor = iw.theme.Colors.StatusRunning
+			marker = "+"
+		case docker.FileModified:
+			nameColor = iw.theme.Colors.StatusPaused
+			marker = "~"
+		case docker.FileDeleted:
+			nameColor = iw.theme.Colors.StatusStopped
+			marker = "-"
+		}
+	}
+
+	name := n.name
+	if n.isDir {
+		name += "/"
+	}
+
+	content := func(gtx layout.Context) layout.Dimensions {
+		return layout.Inset{Top: unit.Dp(2), Bottom: unit.Dp(2), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(layout.Spacer{Width: unit.Dp(16 * row.depth)}.Layout),
+				// Expand indicator
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Dp(unit.Dp(16))
+					indicator := ""
+					if len(n.children) > 0 {
+						indicator = "▸"
+						if f.expanded[n.path] {
+							indicator = "▾"
+						}
+					}
+					label := material.Caption(iw.theme.Material, indicator)
+					label.Color = iw.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				}),
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					label := material.Caption(iw.theme.Material, marker+" "+name)
+					label.Color = nameColor
+					label.Font.Typeface = "monospace"
+					label.MaxLines = 1
+					return label.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					if n.size == 0 {
+						return layout.Dimensions{}
+					}
+					label := material.Caption(iw.theme.Material, docker.FormatSize(n.size))
+					label.Color = iw.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				}),
+			)
+		})
+	}
+
+	if len(n.children) == 0 {
+		return content(gtx)
+	}
+	return f.dirToggle(n.path).Layout(gtx, content)
+}
+
+func (iw *ImageWindow) layoutWastedFiles(gtx layout.Context, wasted []docker.WastedFile) layout.Dimensions {
+	if len(wasted) == 0 {
+		return iw.layoutMessage(gtx, "No file is overwritten or deleted by a later layer")
+	}
+	wasted = wasted[:min(len(wasted), maxWastedFiles)]
+
+	f := &iw.files
+	f.treeList.Axis = layout.Vertical
+	return material.List(iw.theme.Material, &f.treeList).Layout(gtx, len(wasted), func(gtx layout.Context, index int) layout.Dimensions {
+		w := wasted[index]
+		return layout.Inset{Top: unit.Dp(2), Bottom: unit.Dp(2), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Dp(unit.Dp(80))
+					label := material.Caption(iw.theme.Material, docker.FormatSize(w.WastedBytes))
+					label.Color = iw.theme.Colors.StatusPaused
+					return label.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Dp(unit.Dp(40))
+					label := material.Caption(iw.theme.Material, "×"+intToStr(w.Occurrences))
+					label.Color = iw.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				}),
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					label := material.Caption(iw.theme.Material, "/"+strings.TrimPrefix(w.Path, "/"))
+					label.Color = iw.theme.Colors.Text
+					label.Font.Typeface = "monospace"
+					label.MaxLines = 1
+					return label.Layout(gtx)
+				}),
+			)
+		})
+	})
+}
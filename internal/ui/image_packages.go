@@ -0,0 +1,303 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+	"github.com/tsukinoko-kun/harbor/internal/sbom"
+)
+
+// SBOM export formats, keyed for widget.Enum
+var sbomFormats = []struct {
+	key    string
+	label  string
+	format sbom.Format
+}{
+	{"spdx", "SPDX", sbom.FormatSPDX},
+	{"cyclonedx", "CycloneDX", sbom.FormatCycloneDX},
+}
+
+// imagePackagesState holds the package inventory of an image window.
+type imagePackagesState struct {
+	generate   widget.Clickable
+	generating bool
+	bytesRead  int64
+	inventory  *sbom.Inventory
+	genErr     string
+
+	search     widget.Editor
+	format     widget.Enum
+	export     widget.Clickable
+	exporting  bool
+	exportMsg  string
+	exportErr  bool
+	list       widget.List
+	filtered   []int           // Indices of the packages matching the search
+	filteredOf *sbom.Inventory // Inventory and query the filter was computed for
+	query      string
+}
+
+// startInventory exports the image and catalogs its packages in the background.
+func (iw *ImageWindow) startInventory(detail docker.ImageDetail) {
+	p := &iw.packages
+	iw.mu.Lock()
+	p.generating = true
+	p.bytesRead = 0
+	p.genErr = ""
+	iw.mu.Unlock()
+
+	go func() {
+		defer iw.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		inv, err := iw.docker.ImagePackages(ctx, iw.image.ID, func(n int64) {
+			iw.mu.Lock()
+			p.bytesRead = n
+			iw.mu.Unlock()
+			iw.invalidate()
+		})
+
+		iw.mu.Lock()
+		defer iw.mu.Unlock()
+		p.generating = false
+		if err != nil {
+			p.genErr = err.Error()
+			return
+		}
+		inv.Image = imageDisplayName(iw.image)
+		inv.ImageID = detail.ID
+		p.inventory = &inv
+	}()
+}
+
+// exportInventory writes the inventory to the downloads directory in the background.
+func (iw *ImageWindow) exportInventory(inv *sbom.Inventory, format sbom.Format) {
+	p := &iw.packages
+	name := strings.NewReplacer("/", "-", ":", "-", "@", "-").Replace(inv.Image)
+	path := filepath.Join(downloadsDir(), name+"-sbom-"+time.Now().Format("20060102-150405")+format.Extension())
+
+	iw.mu.Lock()
+	p.exporting = true
+	p.exportMsg = "Exporting…"
+	p.exportErr = false
+	iw.mu.Unlock()
+
+	go func() {
+		defer iw.invalidate()
+
+		err := writeInventory(path, *inv, format)
+
+		iw.mu.Lock()
+		defer iw.mu.Unlock()
+		p.exporting = false
+		if err != nil {
+			p.exportMsg = "Failed to export: " + err.Error()
+			p.exportErr = true
+			return
+		}
+		p.exportMsg = "Saved to " + path
+	}()
+}
+
+// writeInventory creates the file at path and writes the inventory into it.
+func writeInventory(path string, inv sbom.Inventory, format sbom.Format) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := sbom.Write(f, inv, format); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// selectedSBOMFormat returns the export format chosen in the packages tab.
+func (p *imagePackagesState) selectedSBOMFormat() sbom.Format {
+	for _, f := range sbomFormats {
+		if f.key == p.format.Value {
+			return f.format
+		}
+	}
+	return sbom.FormatSPDX
+}
+
+// filterPackages returns the indices of the packages matching every word of the query
+// in their name, version, type or location, e.g. "openssl 3.0".
+func filterPackages(pkgs []sbom.Package, query string) []int {
+	words := strings.Fields(strings.ToLower(query))
+	indices := make([]int, 0, len(pkgs))
+	for i, pkg := range pkgs {
+		text := strings.ToLower(pkg.Name + " " + pkg.Version + " " + pkg.Type.Label() + " " + pkg.Location)
+		match := true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+func (iw *ImageWindow) layoutPackages(gtx layout.Context, detail docker.ImageDetail) layout.Dimensions {
+	p := &iw.packages
+
+	iw.mu.RLock()
+	generating := p.generating
+	bytesRead := p.bytesRead
+	inv := p.inventory
+	genErr := p.genErr
+	exporting := p.exporting
+	exportMsg := p.exportMsg
+	exportErr := p.exportErr
+	iw.mu.RUnlock()
+
+	if p.generate.Clicked(gtx) && !generating {
+		iw.startInventory(detail)
+		generating = true
+	}
+
+	if inv == nil {
+		return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			msg := "Reads the image filesystem to list the installed Debian, Alpine and RPM packages, Go modules compiled into binaries, npm dependencies from package-lock.json and Python distributions. No network access is needed."
+			switch {
+			case generating:
+				msg = "Reading image… " + docker.FormatSize(bytesRead)
+			case genErr != "":
+				msg = "Failed to list packages: " + genErr
+			}
+			return layout.Flex{Axis: layout.Vertical, Alignment: layout.Middle}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(unit.Dp(480)))
+					label := material.Body2(iw.theme.Material, msg)
+					label.Color = iw.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				}),
+				layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, iw.theme, &p.generate, "List Packages", false, generating)
+				}),
+			)
+		})
+	}
+
+	if p.format.Value == "" {
+		p.format.Value = sbomFormats[0].key
+	}
+	if p.export.Clicked(gtx) && !exporting {
+		iw.exportInventory(inv, p.selectedSBOMFormat())
+		exporting = true
+	}
+
+	query := p.search.Text()
+	if p.filteredOf != inv || p.query != query {
+		p.filtered = filterPackages(inv.Packages, query)
+		p.filteredOf = inv
+		p.query = query
+	}
+	filtered := p.filtered
+
+	formats := make([]radioOption, len(sbomFormats))
+	for i, f := range sbomFormats {
+		formats[i] = radioOption{f.key, f.label}
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		// Search and export
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					return layoutTextField(gtx, iw.theme, &p.search, "", "Search packages, e.g. openssl 3.0")
+				}),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					label := material.Caption(iw.theme.Material, intToStr(len(filtered))+" of "+intToStr(len(inv.Packages))+" packages")
+					label.Color = iw.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				}),
+				layout.Rigid(layout.Spacer{Width: unit.Dp(16)}.Layout),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutRadioRow(gtx, iw.theme, &p.format, formats)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, iw.theme, &p.export, "Export", false, exporting)
+				}),
+			)
+		}),
+		// Export result
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if exportMsg == "" {
+				return layout.Dimensions{}
+			}
+			return layout.Inset{Top: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				label := material.Caption(iw.theme.Material, exportMsg)
+				label.Color = iw.theme.Colors.TextSecondary
+				if exportErr {
+					label.Color = iw.theme.Colors.StatusStopped
+				}
+				return label.Layout(gtx)
+			})
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+		// Column headers
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return iw.layoutPackageRow(gtx, "Name", "Version", "Type", "Location", true)
+		}),
+		// Packages
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			if len(filtered) == 0 {
+				msg := "No packages found"
+				if query != "" {
+					msg = "No packages match the search"
+				}
+				return iw.layoutMessage(gtx, msg)
+			}
+			p.list.Axis = layout.Vertical
+			return material.List(iw.theme.Material, &p.list).Layout(gtx, len(filtered), func(gtx layout.Context, index int) layout.Dimensions {
+				pkg := inv.Packages[filtered[index]]
+				return iw.layoutPackageRow(gtx, pkg.Name, pkg.Version, pkg.Type.Label(), pkg.Location, false)
+			})
+		}),
+	)
+}
+
+// layoutPackageRow renders a row of the package table, or its header.
+func (iw *ImageWindow) layoutPackageRow(gtx layout.Context, name, version, kind, location string, header bool) layout.Dimensions {
+	cell := func(text string, mono bool) layout.Widget {
+		return func(gtx layout.Context) layout.Dimensions {
+			label := material.Caption(iw.theme.Material, text)
+			label.Color = iw.theme.Colors.Text
+			if header {
+				label.Color = iw.theme.Colors.TextSecondary
+			} else if mono {
+				label.Font.Typeface = "monospace"
+				label.Color = iw.theme.Colors.TextMuted
+			}
+			label.MaxLines = 1
+			return layout.Inset{Right: unit.Dp(12)}.Layout(gtx, label.Layout)
+		}
+	}
+
+	return layout.Inset{Top: unit.Dp(2), Bottom: unit.Dp(2)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			layout.Flexed(3, cell(name, false)),
+			layout.Flexed(2, cell(version, false)),
+			layout.Flexed(1, cell(kind, false)),
+			layout.Flexed(4, cell(location, true)),
+		)
+	})
+}
@@ -0,0 +1,392 @@
+package ui
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/app"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// Image window tabs, keyed for widget.Enum
+const (
+	imageTabLayers   = "layers"
+	imageTabConfig   = "config"
+	imageTabFiles    = "files"
+	imageTabPackages = "packages"
+)
+
+var imageTabs = []radioOption{
+	{imageTabLayers, "Layers"},
+	{imageTabConfig, "Config"},
+	{imageTabFiles, "Files"},
+	{imageTabPackages, "Packages"},
+}
+
+// bigLayerCount is how many of the largest layers are highlighted.
+const bigLayerCount = 3
+
+// ImageWindow represents a window showing the layers and configuration of an image.
+type ImageWindow struct {
+	window *app.Window
+	theme  *Theme
+	docker *docker.Client
+	image  docker.Image
+
+	// Loaded in the background
+	mu      sync.RWMutex
+	detail  docker.ImageDetail
+	loaded  bool
+	loadErr string
+
+	tab        widget.Enum
+	layerList  widget.List
+	configList widget.List
+	files      imageFilesState
+	packages   imagePackagesState
+
+	closed bool
+}
+
+// NewImageWindow creates and runs a new detail window for an image.
+func NewImageWindow(theme *Theme, dockerClient *docker.Client, img docker.Image) {
+	iw := &ImageWindow{
+		theme:      theme,
+		docker:     dockerClient,
+		image:      img,
+		tab:        widget.Enum{Value: imageTabLayers},
+		layerList:  widget.List{List: layout.List{Axis: layout.Vertical}},
+		configList: widget.List{List: layout.List{Axis: layout.Vertical}},
+	}
+
+	go iw.run()
+}
+
+func (iw *ImageWindow) run() {
+	iw.window = new(app.Window)
+	iw.window.Option(
+		app.Title("Image: "+imageDisplayName(iw.image)),
+		app.Size(unit.Dp(900), unit.Dp(650)),
+		app.MinSize(unit.Dp(500), unit.Dp(400)),
+	)
+
+	go iw.load()
+
+	// Run the event loop
+	var ops op.Ops
+	for {
+		switch e := iw.window.Event().(type) {
+		case app.DestroyEvent:
+			iw.closed = true
+			return
+		case app.FrameEvent:
+			gtx := app.NewContext(&ops, e)
+			iw.layout(gtx)
+			e.Frame(gtx.Ops)
+		}
+	}
+}
+
+// load inspects the image and its history.
+func (iw *ImageWindow) load() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	detail, err := iw.docker.InspectImage(ctx, iw.image.ID)
+
+	iw.mu.Lock()
+	iw.loaded = true
+	if err != nil {
+		iw.loadErr = err.Error()
+	} else {
+		iw.detail = detail
+	}
+	iw.mu.Unlock()
+	iw.invalidate()
+}
+
+func (iw *ImageWindow) invalidate() {
+	if iw.window != nil && !iw.closed {
+		iw.window.Invalidate()
+	}
+}
+
+func (iw *ImageWindow) layout(gtx layout.Context) layout.Dimensions {
+	// Fill background
+	paint.FillShape(gtx.Ops, iw.theme.Colors.Background, clip.Rect{Max: gtx.Constraints.Max}.Op())
+
+	iw.mu.RLock()
+	loaded := iw.loaded
+	loadErr := iw.loadErr
+	detail := iw.detail
+	iw.mu.RUnlock()
+
+	return layout.Inset{
+		Top:    unit.Dp(12),
+		Bottom: unit.Dp(8),
+		Left:   unit.Dp(16),
+		Right:  unit.Dp(16),
+	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		switch {
+		case !loaded:
+			return iw.layoutMessage(gtx, "Loading image…")
+		case loadErr != "":
+			return iw.layoutMessage(gtx, "Failed to inspect image: "+loadErr)
+		}
+
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Summary
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return iw.layoutSummary(gtx, detail)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			// Tabs
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTabBar(gtx, iw.theme, &iw.tab, imageTabs)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			// Tab content
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				switch iw.tab.Value {
+				case imageTabConfig:
+					return iw.layoutConfig(gtx, detail)
+				case imageTabFiles:
+					return iw.layoutFiles(gtx, detail)
+				case imageTabPackages:
+					return iw.layoutPackages(gtx, detail)
+				default:
+					return iw.layoutLayers(gtx, detail)
+				}
+			}),
+		)
+	})
+}
+
+func (iw *ImageWindow) layoutMessage(gtx layout.Context, msg string) layout.Dimensions {
+	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		label := material.Body1(iw.theme.Material, msg)
+		label.Color = iw.theme.Colors.TextMuted
+		return label.Layout(gtx)
+	})
+}
+
+func (iw *ImageWindow) layoutSummary(gtx layout.Context, detail docker.ImageDetail) layout.Dimensions {
+	title := imageDisplayName(iw.image)
+	info := detail.ID + " • " + docker.FormatSize(detail.Size) + " • " + intToStr(len(detail.Layers)) + " steps"
+	if !detail.Created.IsZero() {
+		info += " • created " + formatAge(detail.Created)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			label := material.H6(iw.theme.Material, title)
+			label.Color = iw.theme.Colors.Text
+			return label.Layout(gtx)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			label := material.Caption(iw.theme.Material, info)
+			label.Color = iw.theme.Colors.TextMuted
+			return label.Layout(gtx)
+		}),
+	)
+}
+
+// biggestLayers returns the indices of the largest non-empty layers.
+func biggestLayers(layers []docker.ImageLayer, n int) map[int]bool {
+	indices := make([]int, 0, len(layers))
+	for i, l := range layers {
+		if !l.Empty() {
+			indices = append(indices, i)
+		}
+	}
+	sort.Slice(indices, func(a, b int) bool {
+		return layers[indices[a]].Size > layers[indices[b]].Size
+	})
+
+	big := make(map[int]bool, n)
+	for _, i := range indices[:min(n, len(indices))] {
+		big[i] = true
+	}
+	return big
+}
+
+func (iw *ImageWindow) layoutLayers(gtx layout.Context, detail docker.ImageDetail) layout.Dimensions {
+	big := biggestLayers(detail.Layers, bigLayerCount)
+	var largest int64
+	for _, l := range detail.Layers {
+		largest = max(largest, l.Size)
+	}
+
+	return material.List(iw.theme.Material, &iw.layerList).Layout(gtx, len(detail.Layers), func(gtx layout.Context, index int) layout.Dimensions {
+		return iw.layoutLayer(gtx, detail.Layers[index], largest, big[index])
+	})
+}
+
+func (iw *ImageWindow) layoutLayer(gtx layout.Context, l docker.ImageLayer, largest int64, isBig bool) layout.Dimensions {
+	sizeColor := iw.theme.Colors.TextSecondary
+	barColor := iw.theme.Colors.Primary
+	if l.Empty() {
+		sizeColor = iw.theme.Colors.TextMuted
+	}
+	if isBig {
+		sizeColor = iw.theme.Colors.StatusPaused
+		barColor = iw.theme.Colors.StatusPaused
+	}
+
+	return layout.Inset{Bottom: unit.Dp(4), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Stack{}.Layout(gtx,
+			layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+				if !isBig {
+					return layout.Dimensions{}
+				}
+				return fillRounded(gtx, iw.theme.Colors.CardBg, 4)
+			}),
+			layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Top: unit.Dp(6), Bottom: unit.Dp(6), Left: unit.Dp(8), Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						// Size and relative size bar
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							gtx.Constraints.Min.X = gtx.Dp(unit.Dp(90))
+							gtx.Constraints.Max.X = gtx.Constraints.Min.X
+							return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+									label := material.Body2(iw.theme.Material, docker.FormatSize(l.Size))
+									label.Color = sizeColor
+									return label.Layout(gtx)
+								}),
+								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+									if largest == 0 || l.Empty() {
+										return layout.Dimensions{}
+									}
+									width := max(int(float64(gtx.Dp(unit.Dp(80)))*float64(l.Size)/float64(largest)), 1)
+									height := gtx.Dp(unit.Dp(3))
+									gtx.Constraints.Min.X = width
+									gtx.Constraints.Min.Y = height
+									return fillRounded(gtx, barColor, 1)
+								}),
+							)
+						}),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+						// Instruction
+						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+							label := material.Caption(iw.theme.Material, l.Instruction)
+							label.Color = iw.theme.Colors.Text
+							if l.Empty() {
+								label.Color = iw.theme.Colors.TextMuted
+							}
+							label.Font.Typeface = "monospace"
+							label.MaxLines = 3
+							return label.Layout(gtx)
+						}),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+						// Age
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							label := material.Caption(iw.theme.Material, formatAge(l.Created))
+							label.Color = iw.theme.Colors.TextMuted
+							return label.Layout(gtx)
+						}),
+					)
+				})
+			}),
+		)
+	})
+}
+
+// configRow is a labelled value of the image configuration.
+type configRow struct {
+	label string
+	value string
+}
+
+// imageConfigRows returns the configuration as labelled rows. Empty values are omitted.
+func imageConfigRows(cfg docker.ImageConfig) []configRow {
+	rows := []configRow{
+		{"Entrypoint", strings.Join(cfg.Entrypoint, " ")},
+		{"Cmd", strings.Join(cfg.Cmd, " ")},
+		{"User", cfg.User},
+		{"Working dir", cfg.WorkingDir},
+		{"Platform", cfg.OS + "/" + cfg.Architecture},
+		{"Exposed ports", strings.Join(cfg.ExposedPorts, ", ")},
+		{"Volumes", strings.Join(cfg.Volumes, ", ")},
+		{"Env", strings.Join(cfg.Env, "\n")},
+	}
+
+	rows = append(rows, configRow{"Labels", formatKeyValueLines(cfg.Labels)})
+	return nonEmptyRows(rows)
+}
+
+// nonEmptyRows drops the rows without a value.
+func nonEmptyRows(rows []configRow) []configRow {
+	result := rows[:0]
+	for _, row := range rows {
+		if row.value != "" {
+			result = append(result, row)
+		}
+	}
+	return result
+}
+
+func (iw *ImageWindow) layoutConfig(gtx layout.Context, detail docker.ImageDetail) layout.Dimensions {
+	return layoutConfigRows(gtx, iw.theme, &iw.configList, imageConfigRows(detail.Config))
+}
+
+// layoutConfigRows renders labelled values in a scrollable list.
+func layoutConfigRows(gtx layout.Context, theme *Theme, list *widget.List, rows []configRow) layout.Dimensions {
+	return material.List(theme.Material, list).Layout(gtx, len(rows), func(gtx layout.Context, index int) layout.Dimensions {
+		row := rows[index]
+		return layout.Inset{Bottom: unit.Dp(10), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					gtx.Constraints.Min.X = gtx.Dp(unit.Dp(120))
+					label := material.Body2(theme.Material, row.label)
+					label.Color = theme.Colors.TextMuted
+					return label.Layout(gtx)
+				}),
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(theme.Material, row.value)
+					label.Color = theme.Colors.Text
+					label.Font.Typeface = "monospace"
+					return label.Layout(gtx)
+				}),
+			)
+		})
+	})
+}
+
+// formatAge returns a short relative description of a time, e.g. "3 days ago".
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return pluralize(int(d/time.Minute), "minute") + " ago"
+	case d < 24*time.Hour:
+		return pluralize(int(d/time.Hour), "hour") + " ago"
+	case d < 30*24*time.Hour:
+		return pluralize(int(d/(24*time.Hour)), "day") + " ago"
+	case d < 365*24*time.Hour:
+		return pluralize(int(d/(30*24*time.Hour)), "month") + " ago"
+	default:
+		return pluralize(int(d/(365*24*time.Hour)), "year") + " ago"
+	}
+}
+
+// pluralize returns "1 day" or "3 days".
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return intToStr(n) + " " + noun + "s"
+}
@@ -9,45 +9,169 @@ import (
 	"gioui.org/widget/material"
 
 	"github.com/tsukinoko-kun/harbor/internal/docker"
+	"github.com/tsukinoko-kun/harbor/internal/registry"
 )
 
+// imageRowButtons holds the button states for an image row.
+type imageRowButtons struct {
+	details    widget.Clickable
+	export     widget.Clickable
+	tag        widget.Clickable
+	push       widget.Clickable
+	update     widget.Clickable
+	usedBy     widget.Clickable
+	remove     widget.Clickable
+	untag      widget.Clickable
+	processing bool // true when an action is in progress
+}
+
 // ImagesView displays the list of Docker images.
 type ImagesView struct {
-	theme *Theme
-	list  widget.List
+	theme          *Theme
+	docker         *docker.Client
+	invalidate     func()             // Requests a redraw from background goroutines
+	showContainers func(ids []string) // Switches to the containers view
+	list           widget.List
+	imageButtons   map[string]*imageRowButtons
+	updates        *registry.UpdateChecker // Remote digests of the tags
+	filter         widget.Enum
+
+	pullButton  widget.Clickable
+	pullDialog  imagePullDialog
+	buildButton widget.Clickable
+	buildDialog imageBuildDialog
+
+	importButton  widget.Clickable
+	exportButton  widget.Clickable
+	archiveDialog imageArchiveDialog
+	pruneButton   widget.Clickable
+	pruning       bool
+
+	removeDialog imageRemoveDialog
+	tagDialog    imageTagDialog
+	pushDialog   imagePushDialog
+
+	// Result of the last action, with the containers blocking a removal
+	banner actionBanner
 }
 
 // NewImagesView creates a new images view.
-func NewImagesView(theme *Theme) *ImagesView {
+func NewImagesView(theme *Theme, dockerClient *docker.Client, invalidate func(), showContainers func(ids []string)) *ImagesView {
 	return &ImagesView{
-		theme: theme,
+		theme:          theme,
+		docker:         dockerClient,
+		invalidate:     invalidate,
+		showContainers: showContainers,
 		list: widget.List{
 			List: layout.List{Axis: layout.Vertical},
 		},
+		imageButtons: make(map[string]*imageRowButtons),
+		updates:      registry.NewUpdateChecker(invalidate),
+		filter:       widget.Enum{Value: usageFilterAll},
 	}
 }
 
+// filterImages returns the images matching the selected usage filter.
+func (v *ImagesView) filterImages(images []docker.Image) []docker.Image {
+	if v.filter.Value == usageFilterAll {
+		return images
+	}
+	filtered := make([]docker.Image, 0, len(images))
+	for _, img := range images {
+		if img.InUse() == (v.filter.Value == usageFilterInUse) {
+			filtered = append(filtered, img)
+		}
+	}
+	return filtered
+}
+
+// getImageButtons returns or creates button state for an image.
+func (v *ImagesView) getImageButtons(imageID string) *imageRowButtons {
+	if btns, ok := v.imageButtons[imageID]; ok {
+		return btns
+	}
+	btns := &imageRowButtons{}
+	v.imageButtons[imageID] = btns
+	return btns
+}
+
 // Layout renders the images view.
 func (v *ImagesView) Layout(gtx layout.Context, images []docker.Image) layout.Dimensions {
-	if len(images) == 0 {
-		return v.layoutEmpty(gtx)
+	if v.pullButton.Clicked(gtx) {
+		v.openPullDialog()
+	}
+	if v.importButton.Clicked(gtx) {
+		v.openImportDialog()
+	}
+	if v.exportButton.Clicked(gtx) {
+		v.openExportDialog()
+	}
+	if v.buildButton.Clicked(gtx) {
+		v.openBuildDialog()
+	}
+	if v.pruneButton.Clicked(gtx) && !v.pruning {
+		v.openPruneDialog()
+	}
+	if ids := v.banner.update(gtx); ids != nil {
+		v.showContainers(ids)
 	}
 
-	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
-		// Header
-		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-			return v.layoutHeader(gtx, len(images))
+	shown := v.filterImages(images)
+
+	return layout.Stack{}.Layout(gtx,
+		// Main content
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				// Result of the last action (if any)
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return v.banner.layout(gtx, v.theme)
+				}),
+				// Header
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return v.layoutHeader(gtx, len(images))
+				}),
+				// Usage filter
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return v.layoutFilter(gtx, images)
+				}),
+				// Image list
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					if len(shown) == 0 {
+						return v.layoutEmpty(gtx, len(images) > 0)
+					}
+					return layout.Inset{
+						Left:  unit.Dp(16),
+						Right: unit.Dp(16),
+					}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return v.list.Layout(gtx, len(shown), func(gtx layout.Context, index int) layout.Dimensions {
+							return v.layoutImage(gtx, shown[index])
+						})
+					})
+				}),
+			)
 		}),
-		// Image list
-		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
-			return layout.Inset{
-				Left:  unit.Dp(16),
-				Right: unit.Dp(16),
-			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				return v.list.Layout(gtx, len(images), func(gtx layout.Context, index int) layout.Dimensions {
-					return v.layoutImage(gtx, images[index])
-				})
-			})
+		// Pull dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutPullDialog(gtx)
+		}),
+		// Tag and push dialog overlays
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutTagDialog(gtx)
+		}),
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutPushDialog(gtx)
+		}),
+		// Export and import dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutArchiveDialog(gtx, images)
+		}),
+		// Build dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutBuildDialog(gtx)
+		}),
+		// Confirmation dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutRemoveDialog(gtx)
 		}),
 	)
 }
@@ -71,11 +195,105 @@ func (v *ImagesView) layoutHeader(gtx layout.Context, count int) layout.Dimensio
 				label.Color = v.theme.Colors.TextMuted
 				return label.Layout(gtx)
 			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Dimensions{}
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.pruneButton, "Prune", false, v.pruning)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.importButton, "Import…", false, false)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.exportButton, "Export…", false, false)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.buildButton, "Build", false, false)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.pullButton, "Pull Image", false, false)
+			}),
+		)
+	})
+}
+
+// layoutFilter renders the usage filter with the space unused images take up.
+func (v *ImagesView) layoutFilter(gtx layout.Context, images []docker.Image) layout.Dimensions {
+	var unused int
+	var reclaimable int64
+	for _, img := range images {
+		if !img.InUse() {
+			unused++
+			reclaimable += img.UniqueSize()
+		}
+	}
+
+	return layout.Inset{
+		Bottom: unit.Dp(8),
+		Left:   unit.Dp(16),
+		Right:  unit.Dp(16),
+	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutRadioRow(gtx, v.theme, &v.filter, usageFilters)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Dimensions{}
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if unused == 0 {
+					return layout.Dimensions{}
+				}
+				text := intToStr(unused) + " unused, " + docker.FormatSize(reclaimable) + " reclaimable"
+				label := material.Caption(v.theme.Material, text)
+				label.Color = v.theme.Colors.TextMuted
+				return label.Layout(gtx)
+			}),
 		)
 	})
 }
 
 func (v *ImagesView) layoutImage(gtx layout.Context, img docker.Image) layout.Dimensions {
+	btns := v.getImageButtons(img.ID)
+	outdated := v.outdatedTags(img)
+
+	if btns.details.Clicked(gtx) {
+		NewImageWindow(v.theme, v.docker, img)
+	}
+	if btns.export.Clicked(gtx) {
+		v.openExportDialog(img.ID)
+	}
+	if btns.tag.Clicked(gtx) {
+		v.openTagDialog(img)
+	}
+	if btns.push.Clicked(gtx) {
+		v.openPushDialog(img, "")
+	}
+	if btns.usedBy.Clicked(gtx) && img.InUse() {
+		ids := make([]string, len(img.Containers))
+		for i, ctr := range img.Containers {
+			ids[i] = ctr.ID
+		}
+		v.showContainers(ids)
+	}
+
+	// Handle button clicks (only if not processing)
+	if !btns.processing {
+		if btns.update.Clicked(gtx) && len(outdated) > 0 {
+			v.startUpdate(img, outdated)
+		}
+		if btns.remove.Clicked(gtx) {
+			v.openRemoveDialog(imageActionRemove, img)
+		}
+		if btns.untag.Clicked(gtx) {
+			v.openRemoveDialog(imageActionUntag, img)
+		}
+	}
+
 	return layout.Inset{
 		Top:    unit.Dp(8),
 		Bottom: unit.Dp(8),
@@ -102,21 +320,85 @@ func (v *ImagesView) layoutImage(gtx layout.Context, img docker.Image) layout.Di
 							layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
 							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
 								sizeStr := docker.FormatSize(img.Size)
+								if img.SharedSize > 0 {
+									sizeStr += " (" + docker.FormatSize(img.UniqueSize()) + " unique, " + docker.FormatSize(img.SharedSize) + " shared)"
+								}
 								label := material.Caption(v.theme.Material, sizeStr)
 								label.Color = v.theme.Colors.TextMuted
 								return label.Layout(gtx)
 							}),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								if len(outdated) == 0 {
+									return layout.Dimensions{}
+								}
+								return layout.Inset{Left: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+									label := material.Caption(v.theme.Material, "Update available")
+									label.Color = v.theme.Colors.StatusPaused
+									return label.Layout(gtx)
+								})
+							}),
 						)
 					}),
 				)
 			}),
+			// Containers using the image
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutUsedBy(gtx, v.theme, &btns.usedBy, img.Containers)
+			}),
+			// Buttons (right-aligned): Update, Details, Export, Tag, Push, Untag, Delete
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if len(outdated) == 0 {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, v.theme, &btns.update, "Update", false, btns.processing)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, v.theme, &btns.details, "Details", false, false)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, v.theme, &btns.export, "Export", false, false)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, v.theme, &btns.tag, "Tag", false, false)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if img.Dangling {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, v.theme, &btns.push, "Push", false, false)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if img.Dangling {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, v.theme, &btns.untag, "Untag", false, btns.processing)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &btns.remove, "Delete", true, btns.processing)
+			}),
 		)
 	})
 }
 
-func (v *ImagesView) layoutEmpty(gtx layout.Context) layout.Dimensions {
+func (v *ImagesView) layoutEmpty(gtx layout.Context, filtered bool) layout.Dimensions {
+	text := "No images found"
+	if filtered {
+		text = "No images match the filter"
+	}
 	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-		label := material.Body1(v.theme.Material, "No images found")
+		label := material.Body1(v.theme.Material, text)
 		label.Color = v.theme.Colors.TextMuted
 		return label.Layout(gtx)
 	})
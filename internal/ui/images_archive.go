@@ -0,0 +1,392 @@
+package ui
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// Image archive formats, keyed for widget.Enum
+var imageArchiveFormats = []struct {
+	key    string
+	label  string
+	format docker.ArchiveFormat
+}{
+	{"tar", "Docker archive (.tar)", docker.ArchiveTar},
+	{"targz", "Gzip-compressed Docker archive (.tar.gz)", docker.ArchiveTarGzip},
+	{"oci", "OCI image layout (directory)", docker.ArchiveOCILayout},
+}
+
+// imageArchiveDialog holds the state of the "Export Images" and "Import Images" dialogs.
+type imageArchiveDialog struct {
+	exporting  bool // Which of the two dialogs is open
+	open       bool
+	path       widget.Editor
+	format     widget.Enum
+	selected   map[string]*widget.Bool // Export selection by image ID
+	images     widget.List
+	loadedList widget.List
+	start      widget.Clickable
+	cancel     widget.Clickable
+
+	// Updated from background goroutines
+	mu        sync.Mutex
+	running   bool
+	transfer  context.CancelFunc
+	sent      int64
+	total     int64
+	loaded    []string
+	status    string
+	statusErr bool
+}
+
+// openExportDialog shows the export dialog with the given images preselected.
+func (v *ImagesView) openExportDialog(preselect ...string) {
+	d := &v.archiveDialog
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running {
+		d.open = true
+		return
+	}
+
+	d.open = true
+	d.exporting = true
+	d.status = ""
+	d.sent = 0
+	d.total = 0
+	d.selected = make(map[string]*widget.Bool)
+	for _, id := range preselect {
+		d.selected[id] = &widget.Bool{Value: true}
+	}
+	if d.format.Value == "" {
+		d.format.Value = imageArchiveFormats[0].key
+	}
+	d.path.SetText(filepath.Join(downloadsDir(), "images-"+time.Now().Format("20060102-150405")+v.selectedArchiveFormat().Extension()))
+}
+
+// openImportDialog shows the import dialog, keeping the last path.
+func (v *ImagesView) openImportDialog() {
+	d := &v.archiveDialog
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running {
+		d.open = true
+		return
+	}
+
+	d.open = true
+	d.exporting = false
+	d.status = ""
+	d.sent = 0
+	d.total = 0
+	d.loaded = nil
+}
+
+// selectedArchiveFormat returns the format currently selected in the export dialog.
+func (v *ImagesView) selectedArchiveFormat() docker.ArchiveFormat {
+	for _, f := range imageArchiveFormats {
+		if f.key == v.archiveDialog.format.Value {
+			return f.format
+		}
+	}
+	return docker.ArchiveTar
+}
+
+// replaceArchiveExtension swaps a known archive extension for the one of a format.
+func replaceArchiveExtension(path string, format docker.ArchiveFormat) string {
+	for _, known := range []string{".tar.gz", ".tgz", ".tar"} {
+		if strings.HasSuffix(path, known) {
+			path = strings.TrimSuffix(path, known)
+			break
+		}
+	}
+	return path + format.Extension()
+}
+
+// exportRefs returns the references to save for the selected images.
+// Tags are used where possible so the archive keeps the image names.
+func exportRefs(images []docker.Image, selected map[string]*widget.Bool) []string {
+	var refs []string
+	for _, img := range images {
+		if sel, ok := selected[img.ID]; !ok || !sel.Value {
+			continue
+		}
+		if img.Dangling {
+			refs = append(refs, img.ID)
+		} else {
+			refs = append(refs, img.Tags...)
+		}
+	}
+	return refs
+}
+
+// startExport saves the selected images in the background.
+func (v *ImagesView) startExport(refs []string, path string, format docker.ArchiveFormat) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d := &v.archiveDialog
+	d.mu.Lock()
+	d.running = true
+	d.transfer = cancel
+	d.sent = 0
+	d.status = "Exporting " + pluralize(len(refs), "image") + "…"
+	d.statusErr = false
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+		defer cancel()
+
+		err := v.docker.ExportImages(ctx, refs, path, format, func(n int64) {
+			d.mu.Lock()
+			d.sent = n
+			d.mu.Unlock()
+			v.invalidate()
+		})
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.running = false
+		d.transfer = nil
+		switch {
+		case ctx.Err() != nil:
+			d.status = "Export cancelled"
+			d.statusErr = true
+		case err != nil:
+			d.status = "Failed to export images: " + err.Error()
+			d.statusErr = true
+		default:
+			d.status = "Exported " + pluralize(len(refs), "image") + " (" + docker.FormatSize(d.sent) + ") to " + path
+		}
+	}()
+}
+
+// startImport loads the images of an archive in the background.
+func (v *ImagesView) startImport(path string) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d := &v.archiveDialog
+	d.mu.Lock()
+	d.running = true
+	d.transfer = cancel
+	d.sent = 0
+	d.total = 0
+	d.loaded = nil
+	d.status = "Importing…"
+	d.statusErr = false
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+		defer cancel()
+
+		loaded, err := v.docker.ImportImages(ctx, path, func(sent, total int64) {
+			d.mu.Lock()
+			d.sent = sent
+			d.total = total
+			d.mu.Unlock()
+			v.invalidate()
+		})
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.running = false
+		d.transfer = nil
+		d.loaded = loaded
+		switch {
+		case ctx.Err() != nil:
+			d.status = "Import cancelled"
+			d.statusErr = true
+		case err != nil:
+			d.status = "Failed to import images: " + err.Error()
+			d.statusErr = true
+		default:
+			d.status = "Imported " + pluralize(len(loaded), "image")
+		}
+	}()
+}
+
+// layoutArchiveDialog renders the export or import dialog overlay when it is open.
+func (v *ImagesView) layoutArchiveDialog(gtx layout.Context, images []docker.Image) layout.Dimensions {
+	d := &v.archiveDialog
+	if !d.open {
+		return layout.Dimensions{}
+	}
+
+	d.mu.Lock()
+	running := d.running
+	transfer := d.transfer
+	sent := d.sent
+	total := d.total
+	loaded := d.loaded
+	status := d.status
+	statusErr := d.statusErr
+	d.mu.Unlock()
+
+	if d.format.Update(gtx) {
+		d.path.SetText(replaceArchiveExtension(d.path.Text(), v.selectedArchiveFormat()))
+	}
+
+	if d.cancel.Clicked(gtx) {
+		if running && transfer != nil {
+			transfer()
+		} else {
+			d.open = false
+			return layout.Dimensions{Size: gtx.Constraints.Max}
+		}
+	}
+	if d.start.Clicked(gtx) && !running {
+		path := strings.TrimSpace(d.path.Text())
+		switch {
+		case path == "":
+			d.mu.Lock()
+			d.status = "Please enter a path"
+			d.statusErr = true
+			d.mu.Unlock()
+		case d.exporting:
+			refs := exportRefs(images, d.selected)
+			if len(refs) == 0 {
+				d.mu.Lock()
+				d.status = "Please select at least one image"
+				d.statusErr = true
+				d.mu.Unlock()
+			} else {
+				v.startExport(refs, path, v.selectedArchiveFormat())
+			}
+		default:
+			v.startImport(path)
+		}
+	}
+
+	title := "Import Images"
+	startLabel := "Import"
+	pathLabel := "Archive or OCI layout directory"
+	pathHint := "/media/usb/images.tar.gz"
+	if d.exporting {
+		title = "Export Images"
+		startLabel = "Export"
+		pathLabel = "Destination"
+		pathHint = "/media/usb/images.tar"
+	}
+	if running {
+		startLabel += "ing…"
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(560), func(gtx layout.Context) layout.Dimensions {
+		children := []layout.FlexChild{
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.H6(v.theme.Material, title)
+				label.Color = v.theme.Colors.Text
+				return label.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+		}
+
+		if d.exporting {
+			children = append(children,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutSectionLabel(gtx, v.theme, "Images")
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return v.layoutExportSelection(gtx, images)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutSectionLabel(gtx, v.theme, "Format")
+				}),
+			)
+			for _, f := range imageArchiveFormats {
+				f := f
+				children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutRadioButton(gtx, v.theme, &d.format, f.key, f.label)
+				}))
+			}
+			children = append(children, layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout))
+		}
+
+		children = append(children,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.path, pathLabel, pathHint)
+			}),
+			// Progress
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if !running {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutTransferProgress(gtx, v.theme, sent, total)
+				})
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, status)
+					label.Color = v.theme.Colors.TextSecondary
+					if statusErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			// Loaded images
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if d.exporting || len(loaded) == 0 {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutItemList(gtx, v.theme, &d.loadedList, loaded)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				cancelLabel := "Close"
+				if running {
+					cancelLabel = "Cancel"
+				}
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, cancelLabel, false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.start, startLabel, false)
+					},
+				)
+			}),
+		)
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+// layoutExportSelection renders a checkbox for every image.
+func (v *ImagesView) layoutExportSelection(gtx layout.Context, images []docker.Image) layout.Dimensions {
+	d := &v.archiveDialog
+	gtx.Constraints.Max.Y = min(gtx.Constraints.Max.Y, gtx.Dp(unit.Dp(200)))
+	d.images.Axis = layout.Vertical
+	return material.List(v.theme.Material, &d.images).Layout(gtx, len(images), func(gtx layout.Context, index int) layout.Dimensions {
+		img := images[index]
+		sel, ok := d.selected[img.ID]
+		if !ok {
+			sel = new(widget.Bool)
+			d.selected[img.ID] = sel
+		}
+		label := imageDisplayName(img)
+		if len(img.Tags) > 1 {
+			label += " (+" + intToStr(len(img.Tags)-1) + " tags)"
+		}
+		return layoutCheckBox(gtx, v.theme, sel, label+" • "+docker.FormatSize(img.Size))
+	})
+}
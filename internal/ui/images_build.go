@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// imageBuildDialog holds the state of the "Build Image" dialog.
+// The fields keep their values between builds to make iterating easy.
+type imageBuildDialog struct {
+	open       bool
+	contextDir widget.Editor
+	dockerfile widget.Editor
+	tags       widget.Editor
+	buildArgs  widget.Editor
+	target     widget.Editor
+	noCache    widget.Bool
+	pull       widget.Bool
+	build      widget.Clickable
+	cancel     widget.Clickable
+
+	status string
+}
+
+// openBuildDialog shows the build dialog, defaulting the context to the working directory.
+func (v *ImagesView) openBuildDialog() {
+	d := &v.buildDialog
+	d.open = true
+	d.status = ""
+	if d.contextDir.Text() == "" {
+		if wd, err := os.Getwd(); err == nil {
+			d.contextDir.SetText(wd)
+		}
+	}
+	if d.dockerfile.Text() == "" {
+		d.dockerfile.SetText("Dockerfile")
+	}
+}
+
+// buildOptions reads the build options from the dialog.
+func (d *imageBuildDialog) buildOptions() (docker.BuildOptions, string) {
+	opts := docker.BuildOptions{
+		ContextDir: strings.TrimSpace(d.contextDir.Text()),
+		Dockerfile: strings.TrimSpace(d.dockerfile.Text()),
+		Target:     strings.TrimSpace(d.target.Text()),
+		NoCache:    d.noCache.Value,
+		Pull:       d.pull.Value,
+	}
+	if opts.ContextDir == "" {
+		return opts, "Please enter a context directory"
+	}
+
+	for _, tag := range strings.FieldsFunc(d.tags.Text(), func(r rune) bool { return r == ',' || r == ' ' }) {
+		opts.Tags = append(opts.Tags, tag)
+	}
+
+	for _, line := range strings.Split(d.buildArgs.Text(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			// Like the CLI, a bare name takes its value from the environment
+			env, found := os.LookupEnv(key)
+			if !found {
+				return opts, "Build argument " + key + " has no value"
+			}
+			value = env
+		}
+		if opts.BuildArgs == nil {
+			opts.BuildArgs = make(map[string]string)
+		}
+		opts.BuildArgs[strings.TrimSpace(key)] = value
+	}
+
+	return opts, ""
+}
+
+// layoutBuildDialog renders the build dialog overlay when it is open.
+func (v *ImagesView) layoutBuildDialog(gtx layout.Context) layout.Dimensions {
+	d := &v.buildDialog
+	if !d.open {
+		return layout.Dimensions{}
+	}
+
+	if d.cancel.Clicked(gtx) {
+		d.open = false
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+	if d.build.Clicked(gtx) {
+		if opts, problem := d.buildOptions(); problem != "" {
+			d.status = problem
+		} else {
+			NewBuildWindow(v.theme, v.docker, opts)
+			d.open = false
+			return layout.Dimensions{Size: gtx.Constraints.Max}
+		}
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(560), func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := material.H6(v.theme.Material, "Build Image")
+				title.Color = v.theme.Colors.Text
+				return title.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.contextDir, "Context directory", "/path/to/project")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.dockerfile, "Dockerfile (relative to the context)", "Dockerfile")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.tags, "Tags (comma separated)", "myapp:latest, myapp:dev")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.target, "Target stage", "Last stage")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.Y = gtx.Dp(unit.Dp(72))
+				return layoutTextArea(gtx, v.theme, &d.buildArgs, "Build arguments (one KEY=VALUE per line)", "VERSION=1.2.3")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutCheckBox(gtx, v.theme, &d.noCache, "Do not use the build cache")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutCheckBox(gtx, v.theme, &d.pull, "Always pull newer base images")
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if d.status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, d.status)
+					label.Color = v.theme.Colors.StatusStopped
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, "Cancel", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.build, "Build", false)
+					},
+				)
+			}),
+		)
+	})
+}
@@ -0,0 +1,375 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+	"github.com/tsukinoko-kun/harbor/internal/registry"
+)
+
+// platformDefault is the enum key for pulling the daemon's own platform.
+const platformDefault = "default"
+
+// imagePullDialog holds the state of the "Pull Image" dialog.
+type imagePullDialog struct {
+	open      bool
+	reference widget.Editor
+	platform  widget.Enum
+	detect    widget.Clickable
+	pull      widget.Clickable
+	cancel    widget.Clickable
+	layers    widget.List
+
+	// Updated from background goroutines
+	mu         sync.Mutex
+	platforms  []string
+	detecting  bool
+	pulling    bool
+	cancelPull context.CancelFunc
+	progress   docker.TransferProgress
+	status     string
+	statusErr  bool
+}
+
+// openPullDialog shows the pull dialog, keeping the last reference.
+func (v *ImagesView) openPullDialog() {
+	d := &v.pullDialog
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.open = true
+	if !d.pulling {
+		d.progress = docker.TransferProgress{}
+		d.status = ""
+	}
+	if d.platform.Value == "" {
+		d.platform.Value = platformDefault
+	}
+}
+
+// pullReference returns the trimmed image reference entered in the dialog.
+func (v *ImagesView) pullReference() string {
+	return strings.TrimSpace(v.pullDialog.reference.Text())
+}
+
+// detectPlatforms asks the registry which platforms the image is available for.
+func (v *ImagesView) detectPlatforms(ref string) {
+	d := &v.pullDialog
+	d.mu.Lock()
+	d.detecting = true
+	d.status = "Checking available platforms…"
+	d.statusErr = false
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		platforms, err := v.docker.ImagePlatforms(ctx, ref)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.detecting = false
+		if err != nil {
+			d.status = "Failed to check platforms: " + err.Error()
+			d.statusErr = true
+			return
+		}
+		d.platforms = platforms
+		d.status = intToStr(len(platforms)) + " platforms available"
+	}()
+}
+
+// startPull pulls the image in the background and tracks its progress.
+func (v *ImagesView) startPull(ref, platform string) {
+	d := &v.pullDialog
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d.mu.Lock()
+	d.pulling = true
+	d.cancelPull = cancel
+	d.progress = docker.TransferProgress{}
+	d.status = transferCredentialsStatus("Pulling from", ref)
+	d.statusErr = false
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+		defer cancel()
+
+		err := v.docker.PullImage(ctx, ref, docker.PullOptions{Platform: platform}, func(p docker.TransferProgress) {
+			d.mu.Lock()
+			d.progress = p
+			d.mu.Unlock()
+			v.invalidate()
+		})
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.pulling = false
+		d.cancelPull = nil
+		switch {
+		case errors.Is(err, context.Canceled):
+			d.status = "Pull cancelled"
+			d.statusErr = true
+		case err != nil:
+			d.status = "Pull failed: " + err.Error()
+			d.statusErr = true
+		default:
+			d.status = "Pulled " + ref
+			d.statusErr = false
+		}
+	}()
+}
+
+// transferCredentialsStatus describes which stored credentials a pull or push
+// will use, e.g. "Pulling from docker.io as alice…" for action "Pulling from".
+func transferCredentialsStatus(action, ref string) string {
+	host, err := registry.HostFromReference(ref)
+	if err != nil {
+		return action + " registry…"
+	}
+	creds, err := registry.Lookup(host)
+	if err != nil || creds.Empty() {
+		return action + " " + host + " anonymously…"
+	}
+	if creds.Username != "" {
+		return action + " " + host + " as " + creds.Username + "…"
+	}
+	return action + " " + host + " with stored token…"
+}
+
+// layoutPullDialog renders the pull dialog overlay when it is open.
+func (v *ImagesView) layoutPullDialog(gtx layout.Context) layout.Dimensions {
+	d := &v.pullDialog
+	if !d.open {
+		return layout.Dimensions{}
+	}
+
+	d.mu.Lock()
+	pulling := d.pulling
+	detecting := d.detecting
+	d.mu.Unlock()
+
+	if d.cancel.Clicked(gtx) {
+		if pulling {
+			d.mu.Lock()
+			if d.cancelPull != nil {
+				d.cancelPull()
+			}
+			d.mu.Unlock()
+		} else {
+			d.open = false
+			return layout.Dimensions{Size: gtx.Constraints.Max}
+		}
+	}
+	if d.detect.Clicked(gtx) && !detecting {
+		if ref := v.pullReference(); ref != "" {
+			v.detectPlatforms(ref)
+		}
+	}
+	if d.pull.Clicked(gtx) && !pulling {
+		if ref := v.pullReference(); ref == "" {
+			d.mu.Lock()
+			d.status = "Please enter an image reference"
+			d.statusErr = true
+			d.mu.Unlock()
+		} else {
+			platform := d.platform.Value
+			if platform == platformDefault {
+				platform = ""
+			}
+			v.startPull(ref, platform)
+			pulling = true
+		}
+	}
+
+	d.mu.Lock()
+	platforms := append([]string(nil), d.platforms...)
+	progress := d.progress
+	status := d.status
+	statusErr := d.statusErr
+	d.mu.Unlock()
+
+	// Fall back to the default if the selected platform is no longer offered
+	if d.platform.Value != platformDefault && !slices.Contains(platforms, d.platform.Value) {
+		d.platform.Value = platformDefault
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(600), func(gtx layout.Context) layout.Dimensions {
+		options := []radioOption{{platformDefault, "Default"}}
+		for _, p := range platforms {
+			options = append(options, radioOption{p, p})
+		}
+
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := material.H6(v.theme.Material, "Pull Image")
+				title.Color = v.theme.Colors.Text
+				return title.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+			// Reference
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.reference, "Image", "postgres:16")
+			}),
+			// Platform
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutSectionLabel(gtx, v.theme, "Platform")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Start}.Layout(gtx,
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return layoutRadioGrid(gtx, v.theme, &d.platform, options, 3)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layoutActionButton(gtx, v.theme, &d.detect, "Detect", false, detecting)
+					}),
+				)
+			}),
+			// Layer progress
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if len(progress.Layers) == 0 {
+					return layout.Dimensions{}
+				}
+				return v.layoutTransferProgress(gtx, &d.layers, progress, true)
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, status)
+					label.Color = v.theme.Colors.TextSecondary
+					if statusErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			// Result
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if pulling || progress.Digest == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							label := material.Caption(v.theme.Material, progress.Status)
+							label.Color = v.theme.Colors.TextMuted
+							return label.Layout(gtx)
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							label := material.Caption(v.theme.Material, "Digest: "+progress.Digest)
+							label.Color = v.theme.Colors.TextMuted
+							label.Font.Typeface = "monospace"
+							return label.Layout(gtx)
+						}),
+					)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				cancelLabel := "Close"
+				pullLabel := "Pull"
+				if pulling {
+					cancelLabel = "Cancel"
+					pullLabel = "Pulling…"
+				}
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, cancelLabel, pulling)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.pull, pullLabel, false)
+					},
+				)
+			}),
+		)
+	})
+}
+
+// layoutTransferProgress renders the total size and a progress row per layer.
+// extract adds a second bar for the extraction of pulled layers.
+func (v *ImagesView) layoutTransferProgress(gtx layout.Context, list *widget.List, progress docker.TransferProgress, extract bool) layout.Dimensions {
+	list.Axis = layout.Vertical
+
+	current, total := progress.TotalBytes()
+	summary := intToStr(len(progress.Layers)) + " layers"
+	if total > 0 {
+		summary += " • " + docker.FormatSize(current) + " / " + docker.FormatSize(total)
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutSectionLabel(gtx, v.theme, summary)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Max.Y = min(gtx.Constraints.Max.Y, gtx.Dp(unit.Dp(260)))
+			return material.List(v.theme.Material, list).Layout(gtx, len(progress.Layers), func(gtx layout.Context, index int) layout.Dimensions {
+				return v.layoutLayerProgress(gtx, progress.Layers[index], extract)
+			})
+		}),
+	)
+}
+
+// layoutLayerProgress renders the ID, status and transfer (and extract) bars of a layer.
+func (v *ImagesView) layoutLayerProgress(gtx layout.Context, l docker.LayerProgress, extract bool) layout.Dimensions {
+	status := l.Status
+	if !l.Done && l.TransferTotal > 0 && l.Transferred < l.TransferTotal {
+		status += " " + docker.FormatSize(l.Transferred) + " / " + docker.FormatSize(l.TransferTotal)
+	}
+
+	return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						gtx.Constraints.Min.X = gtx.Dp(unit.Dp(110))
+						label := material.Caption(v.theme.Material, l.ID)
+						label.Color = v.theme.Colors.Text
+						label.Font.Typeface = "monospace"
+						return label.Layout(gtx)
+					}),
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						label := material.Caption(v.theme.Material, status)
+						label.Color = v.theme.Colors.TextMuted
+						label.MaxLines = 1
+						return label.Layout(gtx)
+					}),
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(2)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				bars := []layout.FlexChild{
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return layoutProgressBar(gtx, v.theme, l.TransferFraction())
+					}),
+				}
+				if extract {
+					bars = append(bars,
+						layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+							return layoutProgressBar(gtx, v.theme, l.ExtractFraction())
+						}),
+					)
+				}
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx, bars...)
+			}),
+		)
+	})
+}
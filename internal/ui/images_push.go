@@ -0,0 +1,383 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+	"github.com/tsukinoko-kun/harbor/internal/registry"
+)
+
+// imageTagDialog holds the state of the "Tag Image" dialog.
+type imageTagDialog struct {
+	open      bool
+	image     docker.Image
+	target    widget.Editor
+	pushAfter widget.Bool
+	confirm   widget.Clickable
+	cancel    widget.Clickable
+
+	// Updated from background goroutines
+	mu        sync.Mutex
+	tagging   bool
+	pushNext  string // Tag to open the push dialog for once tagging succeeded
+	status    string
+	statusErr bool
+}
+
+// imagePushDialog holds the state of the "Push Image" dialog.
+type imagePushDialog struct {
+	open   bool
+	image  docker.Image
+	tags   []string
+	tag    widget.Enum
+	push   widget.Clickable
+	cancel widget.Clickable
+	layers widget.List
+
+	// Updated from background goroutines
+	mu         sync.Mutex
+	pushing    bool
+	cancelPush context.CancelFunc
+	progress   docker.TransferProgress
+	status     string
+	statusErr  bool
+}
+
+// openTagDialog shows the tag dialog for an image, prefilled with its name.
+func (v *ImagesView) openTagDialog(img docker.Image) {
+	d := &v.tagDialog
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.open = true
+	d.image = img
+	d.status = ""
+	d.pushNext = ""
+	if img.Dangling {
+		d.target.SetText("")
+	} else {
+		d.target.SetText(img.Tags[0])
+	}
+}
+
+// startTag adds a tag to the dialog's image in the background.
+func (v *ImagesView) startTag(target string, pushAfter bool) {
+	d := &v.tagDialog
+	d.mu.Lock()
+	d.tagging = true
+	d.status = "Tagging…"
+	d.statusErr = false
+	img := d.image
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		err := v.docker.TagImage(ctx, img.ID, target)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.tagging = false
+		if err != nil {
+			d.status = "Failed to tag image: " + err.Error()
+			d.statusErr = true
+			return
+		}
+		d.status = "Tagged " + imageDisplayName(img) + " as " + target
+		if pushAfter {
+			d.pushNext = target
+		}
+	}()
+}
+
+// layoutTagDialog renders the tag dialog overlay when it is open.
+func (v *ImagesView) layoutTagDialog(gtx layout.Context) layout.Dimensions {
+	d := &v.tagDialog
+	if !d.open {
+		return layout.Dimensions{}
+	}
+
+	d.mu.Lock()
+	tagging := d.tagging
+	pushNext := d.pushNext
+	d.pushNext = ""
+	img := d.image
+	status := d.status
+	statusErr := d.statusErr
+	d.mu.Unlock()
+
+	// Continue with the push once the tag exists
+	if pushNext != "" {
+		d.open = false
+		v.openPushDialog(img, pushNext)
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+
+	if d.cancel.Clicked(gtx) {
+		d.open = false
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+	if d.confirm.Clicked(gtx) && !tagging {
+		target := strings.TrimSpace(d.target.Text())
+		if _, err := registry.HostFromReference(target); err != nil {
+			d.mu.Lock()
+			d.status = "Invalid tag: " + err.Error()
+			d.statusErr = true
+			d.mu.Unlock()
+		} else {
+			v.startTag(target, d.pushAfter.Value)
+			tagging = true
+		}
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(520), func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := material.H6(v.theme.Material, "Tag Image")
+				title.Color = v.theme.Colors.Text
+				return title.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogText(gtx, v.theme, "Add a tag to "+imageDisplayName(img)+". To push it to a registry, start the tag with the registry host, e.g. registry.example.com:5000/app:1.0.")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.target, "New tag", "registry.example.com/app:1.0")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutCheckBox(gtx, v.theme, &d.pushAfter, "Push after tagging")
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, status)
+					label.Color = v.theme.Colors.TextSecondary
+					if statusErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				tagLabel := "Tag"
+				if tagging {
+					tagLabel = "Tagging…"
+				}
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, "Close", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.confirm, tagLabel, false)
+					},
+				)
+			}),
+		)
+	})
+}
+
+// openPushDialog shows the push dialog for an image with tag preselected.
+// The tag is offered even if the image list has not picked it up yet.
+func (v *ImagesView) openPushDialog(img docker.Image, tag string) {
+	d := &v.pushDialog
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.open = true
+	if d.pushing {
+		return
+	}
+	d.image = img
+	d.tags = append([]string(nil), img.Tags...)
+	if tag != "" && !slices.Contains(d.tags, tag) {
+		d.tags = append(d.tags, tag)
+	}
+	d.tag.Value = tag
+	if d.tag.Value == "" && len(d.tags) > 0 {
+		d.tag.Value = d.tags[0]
+	}
+	d.progress = docker.TransferProgress{}
+	d.status = ""
+}
+
+// startPush pushes a tag in the background and tracks its progress.
+func (v *ImagesView) startPush(ref string) {
+	d := &v.pushDialog
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d.mu.Lock()
+	d.pushing = true
+	d.cancelPush = cancel
+	d.progress = docker.TransferProgress{}
+	d.status = transferCredentialsStatus("Pushing to", ref)
+	d.statusErr = false
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+		defer cancel()
+
+		err := v.docker.PushImage(ctx, ref, func(p docker.TransferProgress) {
+			d.mu.Lock()
+			d.progress = p
+			d.mu.Unlock()
+			v.invalidate()
+		})
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.pushing = false
+		d.cancelPush = nil
+		switch {
+		case errors.Is(err, context.Canceled):
+			d.status = "Push cancelled"
+			d.statusErr = true
+		case err != nil:
+			d.status = "Push failed: " + err.Error()
+			d.statusErr = true
+		default:
+			d.status = "Pushed " + ref
+			d.statusErr = false
+		}
+	}()
+}
+
+// layoutPushDialog renders the push dialog overlay when it is open.
+func (v *ImagesView) layoutPushDialog(gtx layout.Context) layout.Dimensions {
+	d := &v.pushDialog
+	if !d.open {
+		return layout.Dimensions{}
+	}
+
+	d.mu.Lock()
+	pushing := d.pushing
+	d.mu.Unlock()
+
+	if d.cancel.Clicked(gtx) {
+		if pushing {
+			d.mu.Lock()
+			if d.cancelPush != nil {
+				d.cancelPush()
+			}
+			d.mu.Unlock()
+		} else {
+			d.open = false
+			return layout.Dimensions{Size: gtx.Constraints.Max}
+		}
+	}
+	if d.push.Clicked(gtx) && !pushing {
+		if d.tag.Value == "" {
+			d.mu.Lock()
+			d.status = "The image has no tag to push"
+			d.statusErr = true
+			d.mu.Unlock()
+		} else {
+			v.startPush(d.tag.Value)
+			pushing = true
+		}
+	}
+
+	d.mu.Lock()
+	tags := d.tags
+	progress := d.progress
+	status := d.status
+	statusErr := d.statusErr
+	d.mu.Unlock()
+
+	return layoutModal(gtx, v.theme, unit.Dp(600), func(gtx layout.Context) layout.Dimensions {
+		options := make([]radioOption, len(tags))
+		for i, tag := range tags {
+			options[i] = radioOption{tag, tag}
+		}
+
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := material.H6(v.theme.Material, "Push Image")
+				title.Color = v.theme.Colors.Text
+				return title.Layout(gtx)
+			}),
+			// Tag
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutSectionLabel(gtx, v.theme, "Tag")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if len(options) == 0 {
+					return layoutDialogText(gtx, v.theme, "Tag the image with a registry host first.")
+				}
+				return layoutRadioGrid(gtx, v.theme, &d.tag, options, 1)
+			}),
+			// Layer progress
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if len(progress.Layers) == 0 {
+					return layout.Dimensions{}
+				}
+				return v.layoutTransferProgress(gtx, &d.layers, progress, false)
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, status)
+					label.Color = v.theme.Colors.TextSecondary
+					if statusErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			// Result
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if pushing || progress.Digest == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Caption(v.theme.Material, "Digest: "+progress.Digest)
+					label.Color = v.theme.Colors.TextMuted
+					label.Font.Typeface = "monospace"
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				cancelLabel := "Close"
+				pushLabel := "Push"
+				if pushing {
+					cancelLabel = "Cancel"
+					pushLabel = "Pushing…"
+				}
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, cancelLabel, pushing)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.push, pushLabel, false)
+					},
+				)
+			}),
+		)
+	})
+}
@@ -0,0 +1,334 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// Image removal actions of the confirmation dialog
+const (
+	imageActionRemove = "remove"
+	imageActionUntag  = "untag"
+	imageActionPrune  = "prune"
+)
+
+// Prune modes, keyed for widget.Enum
+const (
+	pruneDangling = "dangling"
+	pruneUnused   = "unused"
+)
+
+var pruneModeOptions = []radioOption{
+	{pruneDangling, "Dangling images"},
+	{pruneUnused, "All unused images"},
+}
+
+// imageRemoveDialog holds the state of the remove, untag and prune confirmation dialog.
+type imageRemoveDialog struct {
+	action    string // Empty when the dialog is closed
+	image     docker.Image
+	tag       widget.Enum
+	force     widget.Bool
+	noPrune   widget.Bool
+	pruneMode widget.Enum
+	confirm   widget.Clickable
+	cancel    widget.Clickable
+	list      widget.List
+
+	// Prune candidates, loaded in the background
+	mu         sync.Mutex
+	candidates []docker.Image
+	loadedMode string
+	loading    bool
+	loadErr    string
+}
+
+// openRemoveDialog asks for confirmation before removing or untagging an image.
+func (v *ImagesView) openRemoveDialog(action string, img docker.Image) {
+	d := &v.removeDialog
+	d.action = action
+	d.image = img
+	d.force.Value = false
+	d.noPrune.Value = false
+	d.tag.Value = img.Tags[0]
+}
+
+// openPruneDialog asks for confirmation before pruning images.
+func (v *ImagesView) openPruneDialog() {
+	d := &v.removeDialog
+	d.action = imageActionPrune
+	if d.pruneMode.Value == "" {
+		d.pruneMode.Value = pruneDangling
+	}
+	v.loadPruneCandidates(d.pruneMode.Value)
+}
+
+// loadPruneCandidates lists the images a prune in the given mode would remove.
+func (v *ImagesView) loadPruneCandidates(mode string) {
+	d := &v.removeDialog
+	d.mu.Lock()
+	d.loading = true
+	d.loadedMode = mode
+	d.candidates = nil
+	d.loadErr = ""
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		candidates, err := v.docker.PruneCandidates(ctx, mode == pruneUnused)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.loadedMode != mode {
+			// The mode changed while loading
+			return
+		}
+		d.loading = false
+		if err != nil {
+			d.loadErr = err.Error()
+			return
+		}
+		d.candidates = candidates
+	}()
+}
+
+// runRemoveAction performs the confirmed action in the background.
+func (v *ImagesView) runRemoveAction() {
+	d := &v.removeDialog
+	action := d.action
+	img := d.image
+	tag := d.tag.Value
+	opts := docker.RemoveImageOptions{Force: d.force.Value, NoPrune: d.noPrune.Value}
+	pruneAll := d.pruneMode.Value == pruneUnused
+
+	var btns *imageRowButtons
+	if action != imageActionPrune {
+		btns = v.getImageButtons(img.ID)
+		btns.processing = true
+	} else {
+		v.pruning = true
+	}
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		switch action {
+		case imageActionRemove:
+			if _, err := v.docker.RemoveImage(ctx, img.ID, opts); err != nil {
+				v.setImageError("Failed to remove "+imageDisplayName(img), err)
+			} else {
+				v.setNotice("Removed " + imageDisplayName(img))
+			}
+			btns.processing = false
+		case imageActionUntag:
+			if err := v.docker.UntagImage(ctx, tag); err != nil {
+				v.setImageError("Failed to untag "+tag, err)
+			} else {
+				v.setNotice("Untagged " + tag)
+			}
+			btns.processing = false
+		case imageActionPrune:
+			report, err := v.docker.PruneImages(ctx, pruneAll)
+			if err != nil {
+				v.setImageError("Failed to prune images", err)
+			} else {
+				v.setNotice("Removed " + intToStr(report.Deleted) + " images, reclaimed " + docker.FormatSize(int64(report.SpaceReclaimed)))
+			}
+			v.pruning = false
+		}
+	}()
+}
+
+// setImageError shows an error. If containers use the image, they are listed
+// and can be shown in the containers view.
+func (v *ImagesView) setImageError(prefix string, err error) {
+	var containers []docker.Container
+	var inUse *docker.ImageInUseError
+	if errors.As(err, &inUse) {
+		containers = inUse.Containers
+	}
+	v.banner.set(prefix+": "+err.Error(), true, containers)
+}
+
+// setNotice shows a success message.
+func (v *ImagesView) setNotice(msg string) {
+	v.banner.set(msg, false, nil)
+}
+
+// imageDisplayName returns the first tag of an image, or its ID if it has none.
+func imageDisplayName(img docker.Image) string {
+	if img.Dangling {
+		return img.ID
+	}
+	return img.Tags[0]
+}
+
+// layoutRemoveDialog renders the confirmation dialog overlay when it is open.
+func (v *ImagesView) layoutRemoveDialog(gtx layout.Context) layout.Dimensions {
+	d := &v.removeDialog
+	if d.action == "" {
+		return layout.Dimensions{}
+	}
+
+	if d.cancel.Clicked(gtx) {
+		d.action = ""
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+	if d.action == imageActionPrune && d.pruneMode.Update(gtx) {
+		v.loadPruneCandidates(d.pruneMode.Value)
+	}
+
+	d.mu.Lock()
+	loading := d.loading
+	candidates := d.candidates
+	loadErr := d.loadErr
+	d.mu.Unlock()
+
+	if d.confirm.Clicked(gtx) && !(d.action == imageActionPrune && (loading || len(candidates) == 0)) {
+		v.runRemoveAction()
+		d.action = ""
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+
+	var title, confirmLabel string
+	var body []layout.FlexChild
+	switch d.action {
+	case imageActionRemove:
+		title = "Remove Image"
+		confirmLabel = "Remove"
+		body = v.removeDialogBody(d)
+	case imageActionUntag:
+		title = "Untag Image"
+		confirmLabel = "Untag"
+		body = v.untagDialogBody(d)
+	case imageActionPrune:
+		title = "Prune Images"
+		confirmLabel = "Prune"
+		body = v.pruneDialogBody(d, loading, candidates, loadErr)
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(520), func(gtx layout.Context) layout.Dimensions {
+		children := []layout.FlexChild{
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.H6(v.theme.Material, title)
+				label.Color = v.theme.Colors.Text
+				return label.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+		}
+		children = append(children, body...)
+		children = append(children,
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, "Cancel", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.confirm, confirmLabel, true)
+					},
+				)
+			}),
+		)
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *ImagesView) removeDialogBody(d *imageRemoveDialog) []layout.FlexChild {
+	img := d.image
+	return []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutDialogText(gtx, v.theme, "The following will be removed:")
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			lines := []string{"Image " + img.ID}
+			if !img.Dangling {
+				for _, tag := range img.Tags {
+					lines = append(lines, "Tag "+tag)
+				}
+			}
+			return layoutItemList(gtx, v.theme, &d.list, lines)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutDialogText(gtx, v.theme, "Up to "+docker.FormatSize(img.Size)+" will be reclaimed. Layers shared with other images are kept.")
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutCheckBox(gtx, v.theme, &d.force, "Force (remove all tags and images used by stopped containers)")
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutCheckBox(gtx, v.theme, &d.noPrune, "Keep untagged parent images")
+		}),
+	}
+}
+
+func (v *ImagesView) untagDialogBody(d *imageRemoveDialog) []layout.FlexChild {
+	img := d.image
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutDialogText(gtx, v.theme, "Select the tag to remove from image "+img.ID+":")
+		}),
+	}
+	for _, tag := range img.Tags {
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutRadioButton(gtx, v.theme, &d.tag, tag, tag)
+		}))
+	}
+	if len(img.Tags) == 1 {
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogText(gtx, v.theme, "This is the only tag, so the image ("+docker.FormatSize(img.Size)+") will be removed as well.")
+			})
+		}))
+	}
+	return children
+}
+
+func (v *ImagesView) pruneDialogBody(d *imageRemoveDialog, loading bool, candidates []docker.Image, loadErr string) []layout.FlexChild {
+	return []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutRadioRow(gtx, v.theme, &d.pruneMode, pruneModeOptions)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			switch {
+			case loading:
+				return layoutDialogText(gtx, v.theme, "Looking for images to remove…")
+			case loadErr != "":
+				return layoutDialogText(gtx, v.theme, "Failed to list images: "+loadErr)
+			case len(candidates) == 0:
+				return layoutDialogText(gtx, v.theme, "Nothing to prune.")
+			}
+
+			var total int64
+			lines := make([]string, len(candidates))
+			for i, img := range candidates {
+				total += img.Size
+				lines[i] = imageDisplayName(img) + "  " + docker.FormatSize(img.Size)
+			}
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutDialogText(gtx, v.theme, intToStr(len(candidates))+" images will be removed, reclaiming up to "+docker.FormatSize(total)+":")
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutItemList(gtx, v.theme, &d.list, lines)
+				}),
+			)
+		}),
+	}
+}
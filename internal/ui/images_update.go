@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// outdatedTags queues update checks for the tags of an image and returns the
+// tags whose registry digest differs from the local one.
+// Images without registry digests were built or loaded locally and are skipped.
+func (v *ImagesView) outdatedTags(img docker.Image) []string {
+	if img.Dangling || len(img.Digests) == 0 {
+		return nil
+	}
+
+	var outdated []string
+	for _, tag := range img.Tags {
+		local := localDigests(img, tag)
+		if len(local) == 0 {
+			continue
+		}
+		v.updates.Check(tag)
+		result, _ := v.updates.Result(tag)
+		if result.Digest == "" {
+			continue
+		}
+		upToDate := false
+		for _, digest := range local {
+			if digest == result.Digest {
+				upToDate = true
+				break
+			}
+		}
+		if !upToDate {
+			outdated = append(outdated, tag)
+		}
+	}
+	return outdated
+}
+
+// localDigests returns the digests the image was pulled with from the repository of tag.
+func localDigests(img docker.Image, tag string) []string {
+	named, err := reference.ParseNormalizedNamed(tag)
+	if err != nil {
+		return nil
+	}
+	var digests []string
+	for _, repoDigest := range img.Digests {
+		ref, err := reference.ParseNormalizedNamed(repoDigest)
+		if err != nil || ref.Name() != named.Name() {
+			continue
+		}
+		if canonical, ok := ref.(reference.Canonical); ok {
+			digests = append(digests, canonical.Digest().String())
+		}
+	}
+	return digests
+}
+
+// sameTag reports whether two image references name the same tag, e.g. "redis" and "docker.io/library/redis:latest".
+func sameTag(a, b string) bool {
+	na, err := reference.ParseNormalizedNamed(a)
+	if err != nil {
+		return false
+	}
+	nb, err := reference.ParseNormalizedNamed(b)
+	if err != nil {
+		return false
+	}
+	return reference.TagNameOnly(na).String() == reference.TagNameOnly(nb).String()
+}
+
+// startUpdate pulls the outdated tags of an image and recreates the containers
+// created from them, so they run the new image.
+func (v *ImagesView) startUpdate(img docker.Image, tags []string) {
+	btns := v.getImageButtons(img.ID)
+	btns.processing = true
+	v.setNotice("Updating " + strings.Join(tags, ", ") + "…")
+
+	go func() {
+		defer v.invalidate()
+		defer func() { btns.processing = false }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+
+		// Once a tag points to the new image, the daemon lists containers of the
+		// old one by image ID, so find the containers to recreate before pulling
+		containers, err := v.docker.ListContainers(ctx)
+		if err != nil {
+			v.setImageError("Failed to list containers", err)
+			return
+		}
+		var affected []docker.Container
+		for _, ctr := range containers {
+			if ctr.ImageID == img.ID && containerUsesTag(ctr, tags) {
+				affected = append(affected, ctr)
+			}
+		}
+
+		for _, tag := range tags {
+			err := v.docker.PullImage(ctx, tag, docker.PullOptions{}, func(docker.TransferProgress) {})
+			v.updates.Recheck(tag)
+			if err != nil {
+				v.setImageError("Failed to pull "+tag, err)
+				return
+			}
+		}
+
+		var recreated []docker.Container
+		var errs []error
+		for _, ctr := range affected {
+			id, err := v.docker.RecreateContainer(ctx, ctr.ID)
+			if err != nil {
+				errs = append(errs, errors.New(ctr.Name+": "+err.Error()))
+			}
+			if id != "" {
+				ctr.ID = id
+				recreated = append(recreated, ctr)
+			}
+		}
+
+		if len(errs) > 0 {
+			v.setImageError("Failed to recreate containers", errors.Join(errs...))
+			return
+		}
+
+		msg := "Updated " + strings.Join(tags, ", ")
+		switch len(recreated) {
+		case 0:
+		case 1:
+			msg += " and recreated " + recreated[0].Name
+		default:
+			msg += " and recreated " + intToStr(len(recreated)) + " containers"
+		}
+		v.banner.set(msg, false, recreated)
+	}()
+}
+
+// containerUsesTag reports whether a container was created from one of tags
+// rather than from the image ID or another tag of the image.
+func containerUsesTag(ctr docker.Container, tags []string) bool {
+	for _, tag := range tags {
+		if sameTag(ctr.Image, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,15 +1,14 @@
 package ui
 
 import (
-	"bufio"
 	"context"
-	"encoding/binary"
 	"image"
-	"io"
 	"strings"
 	"sync"
+	"time"
 
 	"gioui.org/app"
+	"gioui.org/font"
 	"gioui.org/layout"
 	"gioui.org/op"
 	"gioui.org/op/clip"
@@ -18,42 +17,84 @@ import (
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 
+	"github.com/tsukinoko-kun/harbor/internal/config"
 	"github.com/tsukinoko-kun/harbor/internal/docker"
 )
 
+// logsReattachInterval is how often a stopped or missing container is polled
+// while waiting for it to come back.
+const logsReattachInterval = time.Second
+
 // LogsWindow represents a window for displaying container logs.
+// It stays attached to the container name (or compose service) rather than
+// the container ID, so logs continue after restarts and recreation.
 type LogsWindow struct {
-	window        *app.Window
-	theme         *Theme
-	docker        *docker.Client
-	containerID   string
-	containerName string
+	window      *app.Window
+	theme       *Theme
+	docker      *docker.Client
+	container   docker.Container
+	containerID string // ID of the container instance currently attached to
+	tty         bool   // Whether the attached instance uses a TTY
 
 	// Log content
-	mu         sync.RWMutex
-	logContent strings.Builder
-	editor     widget.Editor
-	list       widget.List
+	mu       sync.RWMutex
+	entries  []docker.LogEntry
+	grouper  *docker.MultilineGrouper
+	rowState []*logRowState
+	list     widget.List
+
+	// Filter
+	filter  widget.Editor
+	visible logVisibility
+
+	// Save dialog
+	saveButton widget.Clickable
+	saveDialog logSaveDialog
+
+	// Deduplication of lines re-sent after reattaching
+	lastTimestamp time.Time
+	seenAtLast    map[string]struct{}
 
 	// Control
 	cancel context.CancelFunc
 	closed bool
 }
 
+// logRowState holds the UI state of a single log entry.
+type logRowState struct {
+	expanded bool
+	toggle   widget.Clickable
+	more     widget.Clickable
+	text     widget.Selectable
+}
+
+// logVisibility caches which entries match the filter.
+type logVisibility struct {
+	filter    string
+	entries   int // Number of entries when computed
+	lastLines int // Lines of the last entry when computed
+	indices   []int
+}
+
 // NewLogsWindow creates and runs a new logs window for a container.
-func NewLogsWindow(theme *Theme, dockerClient *docker.Client, containerID, containerName string) {
+func NewLogsWindow(theme *Theme, dockerClient *docker.Client, settings *config.Settings, ctr docker.Container) {
+	// An invalid custom pattern only disables the custom rule
+	grouper, err := docker.NewMultilineGrouper(!settings.Logs.DisableMultiline, settings.Logs.MultilinePattern)
+	if err != nil {
+		grouper, _ = docker.NewMultilineGrouper(!settings.Logs.DisableMultiline, "")
+	}
+
 	lw := &LogsWindow{
-		theme:         theme,
-		docker:        dockerClient,
-		containerID:   containerID,
-		containerName: containerName,
-		editor: widget.Editor{
-			ReadOnly:   true,
-			SingleLine: false,
-		},
+		theme:       theme,
+		docker:      dockerClient,
+		container:   ctr,
+		containerID: ctr.ID,
+		grouper:     grouper,
+		seenAtLast:  make(map[string]struct{}),
 		list: widget.List{
 			List: layout.List{
-				Axis: layout.Vertical,
+				Axis:        layout.Vertical,
+				ScrollToEnd: true,
 			},
 		},
 	}
@@ -64,7 +105,7 @@ func NewLogsWindow(theme *Theme, dockerClient *docker.Client, containerID, conta
 func (lw *LogsWindow) run() {
 	lw.window = new(app.Window)
 	lw.window.Option(
-		app.Title("Logs: "+lw.containerName),
+		app.Title("Logs: "+lw.container.Name),
 		app.Size(unit.Dp(800), unit.Dp(600)),
 		app.MinSize(unit.Dp(400), unit.Dp(300)),
 	)
@@ -92,126 +133,281 @@ func (lw *LogsWindow) run() {
 	}
 }
 
+// streamLogs follows the container's logs until the window is closed.
+// When the stream ends it waits for the container to be restarted or
+// recreated and then reattaches, marking the restart with a separator.
 func (lw *LogsWindow) streamLogs(ctx context.Context) {
-	reader, err := lw.docker.ContainerLogs(ctx, lw.containerID, true)
-	if err != nil {
-		lw.appendLog("Error fetching logs: " + err.Error() + "\n")
-		return
-	}
-	defer reader.Close()
-
-	// Docker logs have an 8-byte header for multiplexed streams
-	// Format: [1 byte stream type][3 bytes padding][4 bytes size (big-endian)]
-	header := make([]byte, 8)
-	bufReader := bufio.NewReader(reader)
+	var attached docker.ContainerInfo
+	exitCodeKnown := false
+	reportedErr := false
 
 	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
-
-		// Read the 8-byte header
-		_, err := io.ReadFull(bufReader, header)
+		info, err := lw.resolveContainer(ctx)
 		if err != nil {
-			if err == io.EOF || ctx.Err() != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if attached.ID == "" && !reportedErr {
+				lw.appendNote("Error fetching logs: " + err.Error())
+				reportedErr = true
+			}
+			if !sleepContext(ctx, logsReattachInterval) {
 				return
 			}
-			// Try reading as raw text (some containers don't have multiplexed output)
-			lw.readRawLogs(bufReader, ctx)
-			return
-		}
-
-		// Parse the size from header bytes 4-7
-		size := binary.BigEndian.Uint32(header[4:8])
-		if size == 0 {
 			continue
 		}
 
-		// Read the log message
-		msg := make([]byte, size)
-		_, err = io.ReadFull(bufReader, msg)
-		if err != nil {
-			if err == io.EOF || ctx.Err() != nil {
-				return
+		if attached.ID != "" {
+			restarted := info.ID != attached.ID || info.StartedAt.After(attached.StartedAt)
+			if !restarted && !info.Running {
+				// Still stopped, wait for it to come back
+				if !sleepContext(ctx, logsReattachInterval) {
+					return
+				}
+				continue
+			}
+			if restarted {
+				lw.appendRestartSeparator(attached.ExitCode, exitCodeKnown)
 			}
-			continue
 		}
 
-		lw.appendLog(string(msg))
+		attached = info
+		exitCodeKnown = false
+		lw.containerID = info.ID
+		lw.tty = info.Tty
+
+		err = lw.docker.StreamLogs(ctx, info.ID, docker.LogOptions{
+			Follow: true,
+			Since:  lw.resumeTimestamp(),
+			Tty:    info.Tty,
+		}, lw.appendLine)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && !reportedErr {
+			lw.appendNote("Error fetching logs: " + err.Error())
+			reportedErr = true
+		}
+
+		// The stream ended; remember how this instance exited
+		if final, err := lw.docker.InspectContainer(ctx, info.ID); err == nil && final.StartedAt.Equal(info.StartedAt) {
+			attached.Running = final.Running
+			attached.ExitCode = final.ExitCode
+			exitCodeKnown = !final.Running
+		}
+
+		if !sleepContext(ctx, logsReattachInterval) {
+			return
+		}
 	}
 }
 
-func (lw *LogsWindow) readRawLogs(reader *bufio.Reader, ctx context.Context) {
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
+// resolveContainer finds the container instance to attach to. The current
+// instance is preferred; if it is gone, the compose service labels or the
+// container name are used to find its replacement.
+func (lw *LogsWindow) resolveContainer(ctx context.Context) (docker.ContainerInfo, error) {
+	info, err := lw.docker.InspectContainer(ctx, lw.containerID)
+	if err == nil {
+		return info, nil
+	}
+
+	if lw.container.Project != "" && lw.container.Service != "" {
+		if id, findErr := lw.docker.FindServiceContainer(ctx, lw.container.Project, lw.container.Service); findErr == nil {
+			return lw.docker.InspectContainer(ctx, id)
 		}
+	}
 
-		line, err := reader.ReadString('\n')
-		if line != "" {
-			lw.appendLog(line)
+	if lw.container.Name != "" {
+		if info, nameErr := lw.docker.InspectContainer(ctx, lw.container.Name); nameErr == nil {
+			return info, nil
 		}
-		if err != nil {
+	}
+
+	return docker.ContainerInfo{}, err
+}
+
+// resumeTimestamp returns the timestamp to resume streaming from.
+func (lw *LogsWindow) resumeTimestamp() time.Time {
+	lw.mu.RLock()
+	defer lw.mu.RUnlock()
+	return lw.lastTimestamp
+}
+
+// appendLine appends a log line unless it was already shown before reattaching.
+// Docker's "since" filter is inclusive, so lines sharing the last timestamp
+// are compared by message.
+func (lw *LogsWindow) appendLine(line docker.LogLine) {
+	lw.mu.Lock()
+	if !line.Timestamp.IsZero() {
+		if line.Timestamp.Before(lw.lastTimestamp) {
+			lw.mu.Unlock()
 			return
 		}
+		if line.Timestamp.Equal(lw.lastTimestamp) {
+			if _, seen := lw.seenAtLast[line.Message]; seen {
+				lw.mu.Unlock()
+				return
+			}
+		} else {
+			lw.lastTimestamp = line.Timestamp
+			clear(lw.seenAtLast)
+		}
+		lw.seenAtLast[line.Message] = struct{}{}
+	}
+	lw.entries = lw.grouper.Append(lw.entries, line)
+	lw.mu.Unlock()
+
+	lw.invalidate()
+}
+
+// appendRestartSeparator marks the point where the container was restarted or recreated.
+func (lw *LogsWindow) appendRestartSeparator(exitCode int, exitCodeKnown bool) {
+	if exitCodeKnown {
+		lw.appendNote("— container restarted (exit code " + intToStr(exitCode) + ") —")
+	} else {
+		lw.appendNote("— container restarted —")
 	}
 }
 
-func (lw *LogsWindow) appendLog(msg string) {
+// appendNote appends a line that is not part of the container output,
+// such as an error or a restart separator.
+func (lw *LogsWindow) appendNote(msg string) {
 	lw.mu.Lock()
-	lw.logContent.WriteString(msg)
+	lw.entries = append(lw.entries, docker.LogEntry{LogLine: docker.LogLine{Timestamp: time.Now(), Message: msg}})
 	lw.mu.Unlock()
 
+	lw.invalidate()
+}
+
+func (lw *LogsWindow) invalidate() {
 	if lw.window != nil && !lw.closed {
 		lw.window.Invalidate()
 	}
 }
 
+// sleepContext waits for d or until ctx is cancelled.
+// It returns false if ctx was cancelled.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
 func (lw *LogsWindow) layout(gtx layout.Context) layout.Dimensions {
+	if lw.saveButton.Clicked(gtx) {
+		lw.openSaveDialog()
+	}
+
 	// Fill background
 	paint.FillShape(gtx.Ops, lw.theme.Colors.Background, clip.Rect{Max: gtx.Constraints.Max}.Op())
 
-	return layout.Inset{
-		Top:    unit.Dp(8),
-		Bottom: unit.Dp(8),
-		Left:   unit.Dp(12),
-		Right:  unit.Dp(12),
-	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-		return lw.layoutLogs(gtx)
-	})
+	return layout.Stack{}.Layout(gtx,
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{
+				Top:    unit.Dp(8),
+				Bottom: unit.Dp(8),
+				Left:   unit.Dp(12),
+				Right:  unit.Dp(12),
+			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					// Toolbar
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layout.Inset{Bottom: unit.Dp(8)}.Layout(gtx, lw.layoutToolbar)
+					}),
+					// Logs
+					layout.Flexed(1, lw.layoutLogs),
+				)
+			})
+		}),
+		// Save dialog overlay
+		layout.Expanded(lw.layoutSaveDialog),
+	)
 }
 
-func (lw *LogsWindow) layoutLogs(gtx layout.Context) layout.Dimensions {
-	lw.mu.RLock()
-	content := lw.logContent.String()
-	lw.mu.RUnlock()
+func (lw *LogsWindow) layoutToolbar(gtx layout.Context) layout.Dimensions {
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			label := material.Body2(lw.theme.Material, lw.container.Name)
+			label.Color = lw.theme.Colors.TextSecondary
+			return label.Layout(gtx)
+		}),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(16)}.Layout),
+		// Filter
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return layoutTextField(gtx, lw.theme, &lw.filter, "", "Filter")
+		}),
+		layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutActionButton(gtx, lw.theme, &lw.saveButton, "Save…", false, false)
+		}),
+	)
+}
 
-	if content == "" {
-		content = "Waiting for logs..."
+// filterText returns the normalized filter text.
+func (lw *LogsWindow) filterText() string {
+	return strings.ToLower(strings.TrimSpace(lw.filter.Text()))
+}
+
+// visibleEntries returns the indices of the entries matching the filter.
+// Grouped entries match if any of their lines match, and are shown whole.
+// The caller must hold lw.mu.
+func (lw *LogsWindow) visibleEntries(filter string) []int {
+	v := &lw.visible
+	lastLines := 0
+	if n := len(lw.entries); n > 0 {
+		lastLines = len(lw.entries[n-1].Continuation)
+	}
+	if v.indices != nil && v.filter == filter && v.entries == len(lw.entries) && v.lastLines == lastLines {
+		return v.indices
 	}
 
-	// Update editor content if it changed
-	currentText := lw.editor.Text()
-	if currentText != content {
-		lw.editor.SetText(content)
-		// Move cursor to end for auto-scroll effect
-		lw.editor.SetCaret(len(content), len(content))
+	v.filter = filter
+	v.entries = len(lw.entries)
+	v.lastLines = lastLines
+	v.indices = filterLogEntries(lw.entries, filter)
+	return v.indices
+}
+
+// filterLogEntries returns the indices of entries containing the (lowercase) filter text.
+func filterLogEntries(entries []docker.LogEntry, filter string) []int {
+	indices := make([]int, 0, len(entries))
+	for i, entry := range entries {
+		if filter == "" || strings.Contains(strings.ToLower(entry.Text()), filter) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// rowStateAt returns the UI state for an entry, creating it if needed.
+func (lw *LogsWindow) rowStateAt(index int) *logRowState {
+	for len(lw.rowState) <= index {
+		lw.rowState = append(lw.rowState, nil)
+	}
+	if lw.rowState[index] == nil {
+		lw.rowState[index] = &logRowState{}
 	}
+	return lw.rowState[index]
+}
+
+func (lw *LogsWindow) layoutLogs(gtx layout.Context) layout.Dimensions {
+	filter := lw.filterText()
+
+	lw.mu.RLock()
+	visible := lw.visibleEntries(filter)
+	total := len(lw.entries)
+	lw.mu.RUnlock()
 
 	return layout.Stack{}.Layout(gtx,
 		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
 			// Background for the log area
-			rr := gtx.Dp(unit.Dp(4))
-			rect := clip.RRect{
-				Rect: image.Rectangle{Max: gtx.Constraints.Max},
-				NE:   rr, NW: rr, SE: rr, SW: rr,
-			}
-			paint.FillShape(gtx.Ops, lw.theme.Colors.Surface, rect.Op(gtx.Ops))
-			return layout.Dimensions{Size: gtx.Constraints.Max}
+			gtx.Constraints.Min = gtx.Constraints.Max
+			return fillRounded(gtx, lw.theme.Colors.Surface, 4)
 		}),
 		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
 			return layout.Inset{
@@ -220,12 +416,90 @@ func (lw *LogsWindow) layoutLogs(gtx layout.Context) layout.Dimensions {
 				Left:   unit.Dp(8),
 				Right:  unit.Dp(8),
 			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				// Use material.Editor for styled text editing
-				editor := material.Editor(lw.theme.Material, &lw.editor, "")
-				editor.Color = lw.theme.Colors.Text
-				editor.SelectionColor = lw.theme.Colors.SelectedBg
-				return editor.Layout(gtx)
+				if len(visible) == 0 {
+					msg := "Waiting for logs..."
+					if total > 0 {
+						msg = "No log entries match the filter"
+					}
+					label := material.Body2(lw.theme.Material, msg)
+					label.Color = lw.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				}
+
+				return material.List(lw.theme.Material, &lw.list).Layout(gtx, len(visible), func(gtx layout.Context, i int) layout.Dimensions {
+					index := visible[i]
+					lw.mu.RLock()
+					entry := lw.entries[index]
+					lw.mu.RUnlock()
+					return lw.layoutEntry(gtx, entry, lw.rowStateAt(index))
+				})
+			})
+		}),
+	)
+}
+
+// layoutEntry renders a single log entry. Grouped entries can be collapsed
+// to their first line.
+func (lw *LogsWindow) layoutEntry(gtx layout.Context, entry docker.LogEntry, state *logRowState) layout.Dimensions {
+	grouped := len(entry.Continuation) > 0
+	if grouped && (state.toggle.Clicked(gtx) || state.more.Clicked(gtx)) {
+		state.expanded = !state.expanded
+	}
+
+	text := entry.Message
+	if grouped && state.expanded {
+		text = entry.Text()
+	}
+
+	textColor := lw.theme.Colors.Text
+	switch entry.Stream {
+	case docker.StreamStderr:
+		textColor = lw.theme.Colors.ErrorText
+	case "":
+		// Notes such as restart separators
+		textColor = lw.theme.Colors.TextMuted
+	}
+
+	return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+		// Expand/collapse toggle
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			width := gtx.Dp(unit.Dp(16))
+			if !grouped {
+				return layout.Dimensions{Size: image.Point{X: width}}
+			}
+			return state.toggle.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.X = width
+				icon := "▸"
+				if state.expanded {
+					icon = "▾"
+				}
+				label := material.Caption(lw.theme.Material, icon)
+				label.Color = lw.theme.Colors.TextMuted
+				return label.Layout(gtx)
 			})
 		}),
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					label := material.Caption(lw.theme.Material, text)
+					label.Font = font.Font{Typeface: "monospace"}
+					label.Color = textColor
+					label.SelectionColor = lw.theme.Colors.SelectedBg
+					label.State = &state.text
+					return label.Layout(gtx)
+				}),
+				// Collapsed line count
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					if !grouped || state.expanded {
+						return layout.Dimensions{}
+					}
+					return state.more.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						label := material.Caption(lw.theme.Material, "+"+intToStr(len(entry.Continuation))+" more lines")
+						label.Color = lw.theme.Colors.TextMuted
+						return label.Layout(gtx)
+					})
+				}),
+			)
+		}),
 	)
 }
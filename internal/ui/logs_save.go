@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// Log save sources
+const (
+	logSourceBuffer  = "buffer"
+	logSourceHistory = "history"
+)
+
+// Log save formats, keyed for widget.Enum
+var logSaveFormats = []struct {
+	key    string
+	label  string
+	format docker.LogFormat
+}{
+	{"text", "Plain text", docker.LogFormatText},
+	{"timestamps", "Text with timestamps", docker.LogFormatTimestamps},
+	{"ndjson", "NDJSON (stream, timestamp, message)", docker.LogFormatNDJSON},
+}
+
+// logSaveDialog holds the state of the "Save…" dialog of a logs window.
+type logSaveDialog struct {
+	open   bool
+	path   widget.Editor
+	source widget.Enum
+	format widget.Enum
+	gzip   widget.Bool
+	filter widget.Bool
+	save   widget.Clickable
+	cancel widget.Clickable
+
+	saving    bool
+	status    string
+	statusErr bool
+}
+
+// openSaveDialog shows the save dialog with a default file path.
+func (lw *LogsWindow) openSaveDialog() {
+	d := &lw.saveDialog
+	d.open = true
+	d.status = ""
+	if d.source.Value == "" {
+		d.source.Value = logSourceBuffer
+	}
+	if d.format.Value == "" {
+		d.format.Value = logSaveFormats[0].key
+	}
+	d.path.SetText(defaultLogPath(lw.container.Name, lw.selectedLogFormat(), d.gzip.Value))
+}
+
+// selectedLogFormat returns the format currently selected in the save dialog.
+func (lw *LogsWindow) selectedLogFormat() docker.LogFormat {
+	for _, f := range logSaveFormats {
+		if f.key == lw.saveDialog.format.Value {
+			return f.format
+		}
+	}
+	return docker.LogFormatText
+}
+
+// defaultLogPath returns a file path in the user's downloads (or home) directory.
+func defaultLogPath(containerName string, format docker.LogFormat, compress bool) string {
+	name := containerName + "-" + time.Now().Format("20060102-150405")
+	return filepath.Join(downloadsDir(), name+logFileExtension(format, compress))
+}
+
+// downloadsDir returns the user's downloads directory, falling back to the home directory.
+func downloadsDir() string {
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	if downloads := filepath.Join(dir, "Downloads"); isDir(downloads) {
+		return downloads
+	}
+	return dir
+}
+
+// logFileExtension returns the file extension for a log format.
+func logFileExtension(format docker.LogFormat, compress bool) string {
+	ext := format.Extension()
+	if compress {
+		ext += ".gz"
+	}
+	return ext
+}
+
+// replaceLogExtension swaps a known log file extension for a new one.
+func replaceLogExtension(path, ext string) string {
+	for _, known := range []string{".log.gz", ".ndjson.gz", ".log", ".ndjson"} {
+		if strings.HasSuffix(path, known) {
+			return strings.TrimSuffix(path, known) + ext
+		}
+	}
+	return path
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// saveLogs writes the buffer or the full history to the chosen file.
+// If filter is not empty only entries matching it are written.
+func (lw *LogsWindow) saveLogs(path string, source string, format docker.LogFormat, compress bool, filter string) {
+	d := &lw.saveDialog
+	d.saving = true
+	d.status = "Saving…"
+	d.statusErr = false
+
+	go func() {
+		defer lw.invalidate()
+
+		var entries []docker.LogEntry
+		if source == logSourceHistory {
+			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			defer cancel()
+			lines, err := lw.docker.FetchLogs(ctx, lw.containerID, lw.tty)
+			if err != nil {
+				lw.finishSave("Failed to fetch logs: "+err.Error(), true)
+				return
+			}
+			entries = lw.grouper.Group(lines)
+		} else {
+			lw.mu.RLock()
+			entries = append([]docker.LogEntry(nil), lw.entries...)
+			lw.mu.RUnlock()
+		}
+
+		if filter != "" {
+			indices := filterLogEntries(entries, filter)
+			filtered := make([]docker.LogEntry, 0, len(indices))
+			for _, i := range indices {
+				filtered = append(filtered, entries[i])
+			}
+			entries = filtered
+		}
+
+		if err := writeLogFile(path, entries, format, compress); err != nil {
+			lw.finishSave("Failed to save logs: "+err.Error(), true)
+			return
+		}
+		lw.finishSave("Saved "+intToStr(len(entries))+" entries to "+path, false)
+	}()
+}
+
+func (lw *LogsWindow) finishSave(status string, isErr bool) {
+	d := &lw.saveDialog
+	d.saving = false
+	d.status = status
+	d.statusErr = isErr
+}
+
+// writeLogFile creates the file at path and writes the log entries into it.
+func writeLogFile(path string, entries []docker.LogEntry, format docker.LogFormat, compress bool) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := docker.WriteLogs(f, entries, format, compress); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// layoutSaveDialog renders the save dialog overlay when it is open.
+func (lw *LogsWindow) layoutSaveDialog(gtx layout.Context) layout.Dimensions {
+	d := &lw.saveDialog
+	if !d.open {
+		return layout.Dimensions{}
+	}
+
+	// Keep the file extension in sync with the selected format
+	formatChanged := d.format.Update(gtx)
+	gzipChanged := d.gzip.Update(gtx)
+	if formatChanged || gzipChanged {
+		ext := logFileExtension(lw.selectedLogFormat(), d.gzip.Value)
+		d.path.SetText(replaceLogExtension(d.path.Text(), ext))
+	}
+
+	if d.cancel.Clicked(gtx) {
+		d.open = false
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+	if d.save.Clicked(gtx) && !d.saving {
+		path := strings.TrimSpace(d.path.Text())
+		if path == "" {
+			d.status = "Please enter a file path"
+			d.statusErr = true
+		} else {
+			filter := ""
+			if d.filter.Value {
+				filter = lw.filterText()
+			}
+			lw.saveLogs(path, d.source.Value, lw.selectedLogFormat(), d.gzip.Value, filter)
+		}
+	}
+
+	return layoutModal(gtx, lw.theme, unit.Dp(520), func(gtx layout.Context) layout.Dimensions {
+		children := []layout.FlexChild{
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := material.H6(lw.theme.Material, "Save Logs")
+				title.Color = lw.theme.Colors.Text
+				return title.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+			// File path
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, lw.theme, &d.path, "File", "/path/to/file.log")
+			}),
+			// Source
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutSectionLabel(gtx, lw.theme, "Content")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutRadioButton(gtx, lw.theme, &d.source, logSourceBuffer, "Current buffer")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutRadioButton(gtx, lw.theme, &d.source, logSourceHistory, "Full history from Docker")
+			}),
+			// Format
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutSectionLabel(gtx, lw.theme, "Format")
+			}),
+		}
+		for _, f := range logSaveFormats {
+			f := f
+			children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutRadioButton(gtx, lw.theme, &d.format, f.key, f.label)
+			}))
+		}
+		children = append(children,
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutCheckBox(gtx, lw.theme, &d.gzip, "Gzip compressed")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if lw.filterText() == "" {
+					return layout.Dimensions{}
+				}
+				return layoutCheckBox(gtx, lw.theme, &d.filter, "Only entries matching the filter")
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if d.status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(lw.theme.Material, d.status)
+					label.Color = lw.theme.Colors.TextSecondary
+					if d.statusErr {
+						label.Color = lw.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				saveLabel := "Save"
+				if d.saving {
+					saveLabel = "Saving…"
+				}
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, lw.theme, &d.cancel, "Close", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, lw.theme, &d.save, saveLabel, false)
+					},
+				)
+			}),
+		)
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
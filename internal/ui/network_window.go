@@ -0,0 +1,310 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/app"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// Network window tabs, keyed for widget.Enum
+const (
+	networkTabContainers = "containers"
+	networkTabConfig     = "config"
+)
+
+var networkTabs = []radioOption{
+	{networkTabContainers, "Containers"},
+	{networkTabConfig, "Configuration"},
+}
+
+// NetworkWindow represents a window showing the containers on a network with
+// their addresses and the configuration of the network.
+type NetworkWindow struct {
+	window  *app.Window
+	theme   *Theme
+	docker  *docker.Client
+	network docker.Network
+
+	// Loaded in the background
+	mu      sync.RWMutex
+	detail  docker.NetworkDetail
+	loaded  bool
+	loading bool
+	loadErr string
+
+	tab          widget.Enum
+	reload       widget.Clickable
+	endpointList widget.List
+	configList   widget.List
+
+	closed bool
+}
+
+// NewNetworkWindow creates and runs a new detail window for a network.
+func NewNetworkWindow(theme *Theme, dockerClient *docker.Client, net docker.Network) {
+	nw := &NetworkWindow{
+		theme:        theme,
+		docker:       dockerClient,
+		network:      net,
+		tab:          widget.Enum{Value: networkTabContainers},
+		endpointList: widget.List{List: layout.List{Axis: layout.Vertical}},
+		configList:   widget.List{List: layout.List{Axis: layout.Vertical}},
+	}
+
+	go nw.run()
+}
+
+func (nw *NetworkWindow) run() {
+	nw.window = new(app.Window)
+	nw.window.Option(
+		app.Title("Network: "+nw.network.Name),
+		app.Size(unit.Dp(800), unit.Dp(600)),
+		app.MinSize(unit.Dp(500), unit.Dp(400)),
+	)
+
+	go nw.load()
+
+	// Run the event loop
+	var ops op.Ops
+	for {
+		switch e := nw.window.Event().(type) {
+		case app.DestroyEvent:
+			nw.closed = true
+			return
+		case app.FrameEvent:
+			gtx := app.NewContext(&ops, e)
+			nw.layout(gtx)
+			e.Frame(gtx.Ops)
+		}
+	}
+}
+
+// load inspects the network and its endpoints.
+func (nw *NetworkWindow) load() {
+	nw.mu.Lock()
+	nw.loading = true
+	nw.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	detail, err := nw.docker.InspectNetwork(ctx, nw.network.ID)
+
+	nw.mu.Lock()
+	nw.loaded = true
+	nw.loading = false
+	if err != nil {
+		nw.loadErr = err.Error()
+	} else {
+		nw.loadErr = ""
+		nw.detail = detail
+	}
+	nw.mu.Unlock()
+	nw.invalidate()
+}
+
+func (nw *NetworkWindow) invalidate() {
+	if nw.window != nil && !nw.closed {
+		nw.window.Invalidate()
+	}
+}
+
+func (nw *NetworkWindow) layout(gtx layout.Context) layout.Dimensions {
+	// Fill background
+	paint.FillShape(gtx.Ops, nw.theme.Colors.Background, clip.Rect{Max: gtx.Constraints.Max}.Op())
+
+	nw.mu.RLock()
+	loaded := nw.loaded
+	loading := nw.loading
+	loadErr := nw.loadErr
+	detail := nw.detail
+	nw.mu.RUnlock()
+
+	if nw.reload.Clicked(gtx) && !loading {
+		go nw.load()
+	}
+
+	return layout.Inset{
+		Top:    unit.Dp(12),
+		Bottom: unit.Dp(8),
+		Left:   unit.Dp(16),
+		Right:  unit.Dp(16),
+	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		switch {
+		case !loaded:
+			return nw.layoutMessage(gtx, "Loading network…")
+		case loadErr != "":
+			return nw.layoutMessage(gtx, "Failed to inspect network: "+loadErr)
+		}
+
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Summary
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return nw.layoutSummary(gtx, detail, loading)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			// Tabs
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTabBar(gtx, nw.theme, &nw.tab, networkTabs)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			// Tab content
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				if nw.tab.Value == networkTabConfig {
+					return layoutConfigRows(gtx, nw.theme, &nw.configList, networkConfigRows(detail))
+				}
+				return nw.layoutEndpoints(gtx, detail)
+			}),
+		)
+	})
+}
+
+func (nw *NetworkWindow) layoutMessage(gtx layout.Context, msg string) layout.Dimensions {
+	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		label := material.Body1(nw.theme.Material, msg)
+		label.Color = nw.theme.Colors.TextMuted
+		return label.Layout(gtx)
+	})
+}
+
+func (nw *NetworkWindow) layoutSummary(gtx layout.Context, detail docker.NetworkDetail, loading bool) layout.Dimensions {
+	info := detail.ID + " • " + detail.Driver + " • " + pluralize(len(detail.Endpoints), "container")
+	if !detail.Created.IsZero() {
+		info += " • created " + formatAge(detail.Created)
+	}
+
+	return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					label := material.H6(nw.theme.Material, detail.Name)
+					label.Color = nw.theme.Colors.Text
+					return label.Layout(gtx)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					label := material.Caption(nw.theme.Material, info)
+					label.Color = nw.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				}),
+			)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutActionButton(gtx, nw.theme, &nw.reload, "Reload", false, loading)
+		}),
+	)
+}
+
+// networkConfigRows returns the configuration of a network as labelled rows.
+// Empty values are omitted.
+func networkConfigRows(detail docker.NetworkDetail) []configRow {
+	var flags []string
+	if detail.Internal {
+		flags = append(flags, "internal")
+	}
+	if detail.Attachable {
+		flags = append(flags, "attachable")
+	}
+	if detail.EnableIPv6 {
+		flags = append(flags, "IPv6")
+	}
+
+	subnets := make([]string, len(detail.Subnets))
+	for i, s := range detail.Subnets {
+		subnets[i] = s.Subnet
+		if s.Gateway != "" {
+			subnets[i] += "  gateway " + s.Gateway
+		}
+		if s.IPRange != "" {
+			subnets[i] += "  range " + s.IPRange
+		}
+	}
+
+	rows := []configRow{
+		{"ID", detail.ID},
+		{"Driver", detail.Driver},
+		{"Scope", detail.Scope},
+		{"Flags", strings.Join(flags, ", ")},
+		{"IPAM driver", detail.IPAMDriver},
+		{"Subnets", strings.Join(subnets, "\n")},
+		{"IPAM options", formatKeyValueLines(detail.IPAMOptions)},
+		{"Options", formatKeyValueLines(detail.Options)},
+		{"Labels", formatKeyValueLines(detail.Labels)},
+	}
+	return nonEmptyRows(rows)
+}
+
+func (nw *NetworkWindow) layoutEndpoints(gtx layout.Context, detail docker.NetworkDetail) layout.Dimensions {
+	if len(detail.Endpoints) == 0 {
+		return nw.layoutMessage(gtx, "No containers are connected to this network")
+	}
+	return material.List(nw.theme.Material, &nw.endpointList).Layout(gtx, len(detail.Endpoints), func(gtx layout.Context, index int) layout.Dimensions {
+		return nw.layoutEndpoint(gtx, detail.Endpoints[index])
+	})
+}
+
+// endpointAddresses describes the addresses of an endpoint, or its state
+// when it has none.
+func endpointAddresses(ep docker.NetworkEndpoint) string {
+	var parts []string
+	for _, addr := range []string{ep.IPv4, ep.IPv6} {
+		if addr != "" {
+			parts = append(parts, addr)
+		}
+	}
+	if ep.MAC != "" {
+		parts = append(parts, "MAC "+ep.MAC)
+	}
+	if len(parts) == 0 {
+		return "no address while " + ep.State
+	}
+	return strings.Join(parts, " • ")
+}
+
+func (nw *NetworkWindow) layoutEndpoint(gtx layout.Context, ep docker.NetworkEndpoint) layout.Dimensions {
+	var names []string
+	if len(ep.Aliases) > 0 {
+		names = append(names, "aliases "+strings.Join(ep.Aliases, ", "))
+	}
+	if len(ep.DNSNames) > 0 {
+		names = append(names, "resolvable as "+strings.Join(ep.DNSNames, ", "))
+	}
+
+	return layout.Inset{Bottom: unit.Dp(10), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.Body1(nw.theme.Material, ep.Name)
+				label.Color = nw.theme.Colors.Text
+				if ep.State != "running" {
+					label.Color = nw.theme.Colors.TextMuted
+				}
+				return label.Layout(gtx)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.Caption(nw.theme.Material, endpointAddresses(ep))
+				label.Color = nw.theme.Colors.TextSecondary
+				label.Font.Typeface = "monospace"
+				return label.Layout(gtx)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if len(names) == 0 {
+					return layout.Dimensions{}
+				}
+				label := material.Caption(nw.theme.Material, strings.Join(names, " • "))
+				label.Color = nw.theme.Colors.TextMuted
+				return label.Layout(gtx)
+			}),
+		)
+	})
+}
@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"errors"
+	"strings"
+
 	"gioui.org/layout"
 	"gioui.org/unit"
 	"gioui.org/widget"
@@ -9,43 +12,126 @@ import (
 	"github.com/tsukinoko-kun/harbor/internal/docker"
 )
 
+// networkRowButtons holds the button states for a network row.
+type networkRowButtons struct {
+	usedBy     widget.Clickable
+	details    widget.Clickable
+	containers widget.Clickable
+	remove     widget.Clickable
+	processing bool // true when an action is in progress
+}
+
 // NetworksView displays the list of Docker networks.
 type NetworksView struct {
-	theme *Theme
-	list  widget.List
+	theme          *Theme
+	docker         *docker.Client
+	invalidate     func()             // Requests a redraw from background goroutines
+	showContainers func(ids []string) // Switches to the containers view
+	list           widget.List
+	networkButtons map[string]*networkRowButtons
+
+	createButton  widget.Clickable
+	createDialog  networkCreateDialog
+	pruneButton   widget.Clickable
+	pruning       bool
+	removeDialog  networkRemoveDialog
+	connectDialog networkConnectDialog
+
+	// Result of the last action, with the containers blocking a removal
+	banner actionBanner
 }
 
 // NewNetworksView creates a new networks view.
-func NewNetworksView(theme *Theme) *NetworksView {
+func NewNetworksView(theme *Theme, dockerClient *docker.Client, invalidate func(), showContainers func(ids []string)) *NetworksView {
 	return &NetworksView{
-		theme: theme,
+		theme:          theme,
+		docker:         dockerClient,
+		invalidate:     invalidate,
+		showContainers: showContainers,
 		list: widget.List{
 			List: layout.List{Axis: layout.Vertical},
 		},
+		networkButtons: make(map[string]*networkRowButtons),
+	}
+}
+
+// getNetworkButtons returns or creates button state for a network.
+func (v *NetworksView) getNetworkButtons(id string) *networkRowButtons {
+	if btns, ok := v.networkButtons[id]; ok {
+		return btns
+	}
+	btns := &networkRowButtons{}
+	v.networkButtons[id] = btns
+	return btns
+}
+
+// setNetworkError shows an error. If containers are connected to the network,
+// they are listed and can be shown in the containers view.
+func (v *NetworksView) setNetworkError(prefix string, err error) {
+	var containers []docker.Container
+	var inUse *docker.NetworkInUseError
+	if errors.As(err, &inUse) {
+		containers = inUse.Containers
 	}
+	v.banner.set(prefix+": "+err.Error(), true, containers)
+}
+
+// setNotice shows a success message.
+func (v *NetworksView) setNotice(msg string) {
+	v.banner.set(msg, false, nil)
 }
 
 // Layout renders the networks view.
 func (v *NetworksView) Layout(gtx layout.Context, networks []docker.Network) layout.Dimensions {
-	if len(networks) == 0 {
-		return v.layoutEmpty(gtx)
+	if v.createButton.Clicked(gtx) {
+		v.openCreateDialog()
+	}
+	if v.pruneButton.Clicked(gtx) && !v.pruning {
+		v.openPruneDialog()
+	}
+	if ids := v.banner.update(gtx); ids != nil {
+		v.showContainers(ids)
 	}
 
-	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
-		// Header
-		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-			return v.layoutHeader(gtx, len(networks))
+	return layout.Stack{}.Layout(gtx,
+		// Main content
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				// Result of the last action (if any)
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return v.banner.layout(gtx, v.theme)
+				}),
+				// Header
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return v.layoutHeader(gtx, len(networks))
+				}),
+				// Network list
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					if len(networks) == 0 {
+						return v.layoutEmpty(gtx)
+					}
+					return layout.Inset{
+						Left:  unit.Dp(16),
+						Right: unit.Dp(16),
+					}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return v.list.Layout(gtx, len(networks), func(gtx layout.Context, index int) layout.Dimensions {
+							return v.layoutNetwork(gtx, networks[index])
+						})
+					})
+				}),
+			)
 		}),
-		// Network list
-		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
-			return layout.Inset{
-				Left:  unit.Dp(16),
-				Right: unit.Dp(16),
-			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				return v.list.Layout(gtx, len(networks), func(gtx layout.Context, index int) layout.Dimensions {
-					return v.layoutNetwork(gtx, networks[index])
-				})
-			})
+		// Create dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutCreateDialog(gtx, networks)
+		}),
+		// Connect and disconnect dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutConnectDialog(gtx, networks)
+		}),
+		// Confirmation dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutRemoveDialog(gtx, networks)
 		}),
 	)
 }
@@ -69,44 +155,116 @@ func (v *NetworksView) layoutHeader(gtx layout.Context, count int) layout.Dimens
 				label.Color = v.theme.Colors.TextMuted
 				return label.Layout(gtx)
 			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Dimensions{}
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.pruneButton, "Prune", false, v.pruning)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.createButton, "Create Network", false, false)
+			}),
 		)
 	})
 }
 
+// networkDetails describes the driver, scope, subnets and options of a network.
+func networkDetails(net docker.Network) string {
+	parts := []string{net.ID, net.Driver, net.Scope}
+	for _, s := range net.Subnets {
+		subnet := s.Subnet
+		if s.Gateway != "" {
+			subnet += " via " + s.Gateway
+		}
+		parts = append(parts, subnet)
+	}
+	if net.Internal {
+		parts = append(parts, "internal")
+	}
+	if net.Attachable {
+		parts = append(parts, "attachable")
+	}
+	if net.EnableIPv6 {
+		parts = append(parts, "IPv6")
+	}
+	if project := net.Project(); project != "" {
+		parts = append(parts, "compose project "+project)
+	}
+	return strings.Join(parts, " • ")
+}
+
 func (v *NetworksView) layoutNetwork(gtx layout.Context, net docker.Network) layout.Dimensions {
+	btns := v.getNetworkButtons(net.ID)
+
+	if btns.usedBy.Clicked(gtx) && net.InUse() {
+		ids := make([]string, len(net.Containers))
+		for i, ctr := range net.Containers {
+			ids[i] = ctr.ID
+		}
+		v.showContainers(ids)
+	}
+	if btns.details.Clicked(gtx) {
+		NewNetworkWindow(v.theme, v.docker, net)
+	}
+	if btns.containers.Clicked(gtx) {
+		v.openConnectDialog(net)
+	}
+	if btns.remove.Clicked(gtx) && !btns.processing {
+		if net.InUse() {
+			// Refuse right away instead of asking for a confirmation that cannot succeed
+			v.setNetworkError("Cannot remove "+net.Name, &docker.NetworkInUseError{Err: errors.New("network is in use"), Containers: net.Containers})
+		} else {
+			v.openRemoveDialog(net)
+		}
+	}
+
 	return layout.Inset{
 		Top:    unit.Dp(8),
 		Bottom: unit.Dp(8),
 	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
-			// Name
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				label := material.Body1(v.theme.Material, net.Name)
-				label.Color = v.theme.Colors.Text
-				return label.Layout(gtx)
-			}),
-			// Driver and scope
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			// Network info
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					// Name
 					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						label := material.Caption(v.theme.Material, net.ID)
-						label.Color = v.theme.Colors.TextMuted
+						label := material.Body1(v.theme.Material, net.Name)
+						label.Color = v.theme.Colors.Text
 						return label.Layout(gtx)
 					}),
-					layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+					// ID, driver, scope, subnets and options
 					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						label := material.Caption(v.theme.Material, net.Driver)
-						label.Color = v.theme.Colors.TextMuted
-						return label.Layout(gtx)
-					}),
-					layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
-					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						label := material.Caption(v.theme.Material, net.Scope)
+						label := material.Caption(v.theme.Material, networkDetails(net))
 						label.Color = v.theme.Colors.TextMuted
 						return label.Layout(gtx)
 					}),
 				)
 			}),
+			// Containers connected to the network
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutUsedBy(gtx, v.theme, &btns.usedBy, net.Containers)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, v.theme, &btns.details, "Details", false, false)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if net.Name == "host" || net.Name == "none" {
+					// Containers cannot be connected to these at runtime
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Right: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, v.theme, &btns.containers, "Containers…", false, false)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if net.Predefined() {
+					return layout.Dimensions{}
+				}
+				return layoutActionButton(gtx, v.theme, &btns.remove, "Delete", true, btns.processing)
+			}),
 		)
 	})
 }
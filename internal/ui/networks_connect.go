@@ -0,0 +1,368 @@
+package ui
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// networkConnectDialog holds the state of the dialog that connects containers
+// to a network and disconnects them from it.
+type networkConnectDialog struct {
+	open       bool
+	networkID  string
+	disconnect map[string]*widget.Clickable // By container ID
+	connected  widget.List
+	candidate  widget.Enum // ID of the container to connect
+	candidates widget.List
+	aliases    widget.Editor
+	ipv4       widget.Editor
+	ipv6       widget.Editor
+	connect    widget.Clickable
+	close      widget.Clickable
+
+	// Updated from background goroutines
+	mu        sync.Mutex
+	running   []docker.Container // Running containers, loaded in the background
+	loading   bool
+	busy      bool
+	status    string
+	statusErr bool
+}
+
+// openConnectDialog shows the containers connected to a network.
+func (v *NetworksView) openConnectDialog(net docker.Network) {
+	d := &v.connectDialog
+	d.mu.Lock()
+	d.open = true
+	d.networkID = net.ID
+	d.disconnect = make(map[string]*widget.Clickable)
+	d.candidate.Value = ""
+	d.aliases.SetText("")
+	d.ipv4.SetText("")
+	d.ipv6.SetText("")
+	if !d.busy {
+		d.status = ""
+	}
+	d.mu.Unlock()
+
+	v.loadRunningContainers()
+}
+
+// loadRunningContainers lists the containers that can be connected.
+func (v *NetworksView) loadRunningContainers() {
+	d := &v.connectDialog
+	d.mu.Lock()
+	d.loading = true
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		containers, err := v.docker.ListContainers(ctx)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.loading = false
+		if err != nil {
+			d.status = "Failed to list containers: " + err.Error()
+			d.statusErr = true
+			return
+		}
+		d.running = nil
+		for _, ctr := range containers {
+			if ctr.State == "running" {
+				d.running = append(d.running, ctr)
+			}
+		}
+	}()
+}
+
+// connectOptions reads the endpoint options from the dialog.
+func (d *networkConnectDialog) connectOptions() (docker.ConnectOptions, string) {
+	opts := docker.ConnectOptions{
+		Aliases: strings.FieldsFunc(d.aliases.Text(), func(r rune) bool { return r == ',' || r == ' ' }),
+		IPv4:    strings.TrimSpace(d.ipv4.Text()),
+		IPv6:    strings.TrimSpace(d.ipv6.Text()),
+	}
+	if opts.IPv4 != "" {
+		if addr, err := netip.ParseAddr(opts.IPv4); err != nil || !addr.Is4() {
+			return opts, "\"" + opts.IPv4 + "\" is not an IPv4 address"
+		}
+	}
+	if opts.IPv6 != "" {
+		if addr, err := netip.ParseAddr(opts.IPv6); err != nil || !addr.Is6() {
+			return opts, "\"" + opts.IPv6 + "\" is not an IPv6 address"
+		}
+	}
+	return opts, ""
+}
+
+// runEndpointAction connects or disconnects a container in the background.
+// opts is nil to disconnect.
+func (v *NetworksView) runEndpointAction(net docker.Network, ctr docker.Container, opts *docker.ConnectOptions) {
+	d := &v.connectDialog
+	d.mu.Lock()
+	d.busy = true
+	d.statusErr = false
+	if opts != nil {
+		d.status = "Connecting " + ctr.Name + "…"
+	} else {
+		d.status = "Disconnecting " + ctr.Name + "…"
+	}
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var err error
+		if opts != nil {
+			err = v.docker.ConnectContainer(ctx, net.ID, ctr.ID, *opts)
+		} else {
+			err = v.docker.DisconnectContainer(ctx, net.ID, ctr.ID)
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.busy = false
+		switch {
+		case err != nil && opts != nil:
+			d.status = "Failed to connect " + ctr.Name + ": " + err.Error()
+			d.statusErr = true
+		case err != nil:
+			d.status = "Failed to disconnect " + ctr.Name + ": " + err.Error()
+			d.statusErr = true
+		case opts != nil:
+			d.status = "Connected " + ctr.Name + " to " + net.Name
+		default:
+			d.status = "Disconnected " + ctr.Name + " from " + net.Name
+		}
+	}()
+}
+
+// layoutConnectDialog renders the connect dialog overlay when it is open.
+func (v *NetworksView) layoutConnectDialog(gtx layout.Context, networks []docker.Network) layout.Dimensions {
+	d := &v.connectDialog
+	if !d.open {
+		return layout.Dimensions{}
+	}
+
+	// Look the network up on every frame so the list follows refreshes
+	var net docker.Network
+	found := false
+	for _, n := range networks {
+		if n.ID == d.networkID {
+			net, found = n, true
+			break
+		}
+	}
+	if !found || d.close.Clicked(gtx) {
+		d.open = false
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+
+	d.mu.Lock()
+	running := d.running
+	loading := d.loading
+	busy := d.busy
+	status := d.status
+	statusErr := d.statusErr
+	d.mu.Unlock()
+
+	connected := make(map[string]bool, len(net.Containers))
+	for _, ctr := range net.Containers {
+		connected[ctr.ID] = true
+	}
+	var candidates []docker.Container
+	for _, ctr := range running {
+		if !connected[ctr.ID] {
+			candidates = append(candidates, ctr)
+		}
+	}
+
+	for _, ctr := range net.Containers {
+		btn, ok := d.disconnect[ctr.ID]
+		if !ok {
+			btn = new(widget.Clickable)
+			d.disconnect[ctr.ID] = btn
+		}
+		if btn.Clicked(gtx) && !busy {
+			v.runEndpointAction(net, ctr, nil)
+			busy = true
+		}
+	}
+	if d.connect.Clicked(gtx) && !busy {
+		var selected *docker.Container
+		for i := range candidates {
+			if candidates[i].ID == d.candidate.Value {
+				selected = &candidates[i]
+			}
+		}
+		opts, problem := d.connectOptions()
+		switch {
+		case selected == nil:
+			problem = "Please select a container"
+		case problem == "":
+			v.runEndpointAction(net, *selected, &opts)
+			busy = true
+		}
+		if problem != "" {
+			status = problem
+			statusErr = true
+			d.mu.Lock()
+			d.status = problem
+			d.statusErr = true
+			d.mu.Unlock()
+		}
+	}
+
+	connectLabel := "Connect"
+	if busy {
+		connectLabel = "Working…"
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(600), func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := material.H6(v.theme.Material, "Containers on "+net.Name)
+				title.Color = v.theme.Colors.Text
+				return title.Layout(gtx)
+			}),
+			// Connected containers
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutSectionLabel(gtx, v.theme, "Connected")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if len(net.Containers) == 0 {
+					return layoutDialogText(gtx, v.theme, "No containers are connected.")
+				}
+				return v.layoutConnectedContainers(gtx, net, busy)
+			}),
+			// Attach a running container
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutSectionLabel(gtx, v.theme, "Connect a running container")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				switch {
+				case loading && len(running) == 0:
+					return layoutDialogText(gtx, v.theme, "Loading containers…")
+				case len(candidates) == 0:
+					return layoutDialogText(gtx, v.theme, "All running containers are connected.")
+				}
+				gtx.Constraints.Max.Y = min(gtx.Constraints.Max.Y, gtx.Dp(unit.Dp(160)))
+				d.candidates.Axis = layout.Vertical
+				return material.List(v.theme.Material, &d.candidates).Layout(gtx, len(candidates), func(gtx layout.Context, index int) layout.Dimensions {
+					ctr := candidates[index]
+					label := ctr.Name
+					if ctr.Project != "" {
+						label += "  (" + ctr.Project + ")"
+					}
+					return layoutRadioButton(gtx, v.theme, &d.candidate, ctr.ID, label)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.aliases, "Aliases (separated by commas)", "db, postgres")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return v.layoutFieldRow(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutTextField(gtx, v.theme, &d.ipv4, "IPv4 address", "Assigned if empty")
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutTextField(gtx, v.theme, &d.ipv6, "IPv6 address", "Assigned if empty")
+					},
+				)
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, status)
+					label.Color = v.theme.Colors.TextSecondary
+					if statusErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.close, "Close", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.connect, connectLabel, false)
+					},
+				)
+			}),
+		)
+	})
+}
+
+// layoutConnectedContainers lists the containers on a network with their
+// addresses and a button to disconnect each.
+func (v *NetworksView) layoutConnectedContainers(gtx layout.Context, net docker.Network, busy bool) layout.Dimensions {
+	d := &v.connectDialog
+	gtx.Constraints.Max.Y = min(gtx.Constraints.Max.Y, gtx.Dp(unit.Dp(160)))
+	d.connected.Axis = layout.Vertical
+	return material.List(v.theme.Material, &d.connected).Layout(gtx, len(net.Containers), func(gtx layout.Context, index int) layout.Dimensions {
+		ctr := net.Containers[index]
+		var addrs []string
+		for _, n := range ctr.Networks {
+			if n.NetworkID != net.ID {
+				continue
+			}
+			for _, addr := range []string{n.IPv4, n.IPv6} {
+				if addr != "" {
+					addrs = append(addrs, addr)
+				}
+			}
+		}
+		if len(addrs) == 0 {
+			addrs = append(addrs, ctr.State)
+		}
+
+		return layout.Inset{Top: unit.Dp(4), Bottom: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							label := material.Body2(v.theme.Material, ctr.Name)
+							label.Color = v.theme.Colors.Text
+							return label.Layout(gtx)
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							label := material.Caption(v.theme.Material, strings.Join(addrs, " • "))
+							label.Color = v.theme.Colors.TextMuted
+							return label.Layout(gtx)
+						}),
+					)
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutActionButton(gtx, v.theme, d.disconnect[ctr.ID], "Disconnect", true, busy)
+				}),
+			)
+		})
+	})
+}
@@ -0,0 +1,365 @@
+package ui
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// networkCreateDialog holds the state of the "Create Network" dialog.
+type networkCreateDialog struct {
+	open        bool
+	name        widget.Editor
+	driver      widget.Editor
+	subnet      widget.Editor
+	gateway     widget.Editor
+	ipRange     widget.Editor
+	enableIPv6  widget.Bool
+	ipv6Subnet  widget.Editor
+	ipv6Gateway widget.Editor
+	internal    widget.Bool
+	attachable  widget.Bool
+	labels      widget.Editor
+	create      widget.Clickable
+	cancel      widget.Clickable
+
+	// Updated from background goroutines
+	mu        sync.Mutex
+	creating  bool
+	status    string
+	statusErr bool
+}
+
+// openCreateDialog shows the create dialog with empty fields.
+func (v *NetworksView) openCreateDialog() {
+	d := &v.createDialog
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.open = true
+	d.status = ""
+	for _, e := range []*widget.Editor{&d.name, &d.driver, &d.subnet, &d.gateway, &d.ipRange, &d.ipv6Subnet, &d.ipv6Gateway, &d.labels} {
+		e.SetText("")
+	}
+	d.enableIPv6.Value = false
+	d.internal.Value = false
+	d.attachable.Value = false
+}
+
+// subnetTexts returns the subnets entered in the dialog.
+func (d *networkCreateDialog) subnetTexts() []string {
+	var subnets []string
+	if s := strings.TrimSpace(d.subnet.Text()); s != "" {
+		subnets = append(subnets, s)
+	}
+	if d.enableIPv6.Value {
+		if s := strings.TrimSpace(d.ipv6Subnet.Text()); s != "" {
+			subnets = append(subnets, s)
+		}
+	}
+	return subnets
+}
+
+// createOptions reads the network options from the dialog.
+func (d *networkCreateDialog) createOptions() (docker.CreateNetworkOptions, string) {
+	opts := docker.CreateNetworkOptions{
+		Name:       strings.TrimSpace(d.name.Text()),
+		Driver:     strings.TrimSpace(d.driver.Text()),
+		Internal:   d.internal.Value,
+		Attachable: d.attachable.Value,
+		EnableIPv6: d.enableIPv6.Value,
+	}
+	if opts.Name == "" {
+		return opts, "Please enter a name"
+	}
+
+	subnet, problem := parseSubnet("Subnet", d.subnet.Text(), d.gateway.Text(), d.ipRange.Text(), false)
+	if problem != "" {
+		return opts, problem
+	}
+	if subnet.Subnet != "" {
+		opts.Subnets = append(opts.Subnets, subnet)
+	}
+	if opts.EnableIPv6 {
+		subnet, problem := parseSubnet("IPv6 subnet", d.ipv6Subnet.Text(), d.ipv6Gateway.Text(), "", true)
+		if problem != "" {
+			return opts, problem
+		}
+		if subnet.Subnet != "" {
+			opts.Subnets = append(opts.Subnets, subnet)
+		}
+	}
+
+	opts.Labels, problem = parseKeyValueLines(d.labels.Text(), "Label")
+	return opts, problem
+}
+
+// parseSubnet validates a subnet with an optional gateway and IP range inside of it.
+func parseSubnet(what, subnet, gateway, ipRange string, ipv6 bool) (docker.NetworkSubnet, string) {
+	s := docker.NetworkSubnet{
+		Subnet:  strings.TrimSpace(subnet),
+		Gateway: strings.TrimSpace(gateway),
+		IPRange: strings.TrimSpace(ipRange),
+	}
+	if s.Subnet == "" {
+		if s.Gateway != "" || s.IPRange != "" {
+			return s, what + " is required for a gateway or IP range"
+		}
+		return s, ""
+	}
+
+	prefix, err := netip.ParsePrefix(s.Subnet)
+	if err != nil {
+		return s, what + " \"" + s.Subnet + "\" is not in CIDR notation"
+	}
+	if prefix.Addr().Is6() != ipv6 {
+		if ipv6 {
+			return s, what + " \"" + s.Subnet + "\" is not an IPv6 subnet"
+		}
+		return s, what + " \"" + s.Subnet + "\" is not an IPv4 subnet; enable IPv6 for IPv6 subnets"
+	}
+	if prefix.Masked() != prefix {
+		return s, what + " \"" + s.Subnet + "\" has host bits set, did you mean " + prefix.Masked().String() + "?"
+	}
+	if s.Gateway != "" {
+		addr, err := netip.ParseAddr(s.Gateway)
+		if err != nil {
+			return s, "Gateway \"" + s.Gateway + "\" is not an IP address"
+		}
+		if !prefix.Contains(addr) {
+			return s, "Gateway " + s.Gateway + " is outside of " + s.Subnet
+		}
+	}
+	if s.IPRange != "" {
+		r, err := netip.ParsePrefix(s.IPRange)
+		if err != nil {
+			return s, "IP range \"" + s.IPRange + "\" is not in CIDR notation"
+		}
+		if r.Bits() < prefix.Bits() || !prefix.Contains(r.Addr()) {
+			return s, "IP range " + s.IPRange + " is outside of " + s.Subnet
+		}
+	}
+	return s, ""
+}
+
+// overlapWarning describes the existing networks that overlap the entered
+// subnets, or returns "" if there are none.
+func (d *networkCreateDialog) overlapWarning(networks []docker.Network) string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, subnet := range d.subnetTexts() {
+		overlapping, err := docker.OverlappingNetworks(networks, subnet)
+		if err != nil {
+			continue
+		}
+		for _, n := range overlapping {
+			if !seen[n.ID] {
+				seen[n.ID] = true
+				names = append(names, n.Name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return "The subnet overlaps with " + strings.Join(names, ", ") + ". Containers may not reach addresses in the other network."
+}
+
+// startCreate creates the network in the background and closes the dialog on success.
+func (v *NetworksView) startCreate(opts docker.CreateNetworkOptions) {
+	d := &v.createDialog
+	d.mu.Lock()
+	d.creating = true
+	d.status = "Creating…"
+	d.statusErr = false
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		_, err := v.docker.CreateNetwork(ctx, opts)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.creating = false
+		if err != nil {
+			d.status = "Failed to create network: " + err.Error()
+			d.statusErr = true
+			return
+		}
+		d.open = false
+		v.setNotice("Created network " + opts.Name)
+	}()
+}
+
+// layoutCreateDialog renders the create dialog overlay when it is open.
+func (v *NetworksView) layoutCreateDialog(gtx layout.Context, networks []docker.Network) layout.Dimensions {
+	d := &v.createDialog
+
+	d.mu.Lock()
+	open := d.open
+	creating := d.creating
+	status := d.status
+	statusErr := d.statusErr
+	d.mu.Unlock()
+
+	if !open {
+		return layout.Dimensions{}
+	}
+
+	if d.cancel.Clicked(gtx) && !creating {
+		d.mu.Lock()
+		d.open = false
+		d.mu.Unlock()
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+	if d.create.Clicked(gtx) && !creating {
+		if opts, problem := d.createOptions(); problem != "" {
+			status = problem
+			statusErr = true
+			d.mu.Lock()
+			d.status = problem
+			d.statusErr = true
+			d.mu.Unlock()
+		} else {
+			v.startCreate(opts)
+			creating = true
+		}
+	}
+
+	// Checked every frame so the warning follows the typed subnet
+	warning := d.overlapWarning(networks)
+
+	createLabel := "Create"
+	switch {
+	case creating:
+		createLabel = "Creating…"
+	case warning != "":
+		createLabel = "Create Anyway"
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(560), func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := material.H6(v.theme.Material, "Create Network")
+				title.Color = v.theme.Colors.Text
+				return title.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.name, "Name", "backend")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.driver, "Driver", "bridge")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutSectionLabel(gtx, v.theme, "IPv4 addressing (assigned by Docker if empty)")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return v.layoutFieldRow(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutTextField(gtx, v.theme, &d.subnet, "Subnet", "172.28.0.0/16")
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutTextField(gtx, v.theme, &d.gateway, "Gateway", "172.28.0.1")
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutTextField(gtx, v.theme, &d.ipRange, "IP range", "172.28.5.0/24")
+					},
+				)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutCheckBox(gtx, v.theme, &d.enableIPv6, "Enable IPv6")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if !d.enableIPv6.Value {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return v.layoutFieldRow(gtx,
+						func(gtx layout.Context) layout.Dimensions {
+							return layoutTextField(gtx, v.theme, &d.ipv6Subnet, "IPv6 subnet", "fd00:28::/64")
+						},
+						func(gtx layout.Context) layout.Dimensions {
+							return layoutTextField(gtx, v.theme, &d.ipv6Gateway, "IPv6 gateway", "fd00:28::1")
+						},
+					)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutCheckBox(gtx, v.theme, &d.internal, "Internal (no access to the outside world)")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutCheckBox(gtx, v.theme, &d.attachable, "Attachable (for swarm overlay networks)")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.Y = gtx.Dp(unit.Dp(72))
+				return layoutTextArea(gtx, v.theme, &d.labels, "Labels (one KEY=VALUE per line)", "com.example.team=backend")
+			}),
+			// Subnet overlap warning
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if warning == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, warning)
+					label.Color = v.theme.Colors.StatusPaused
+					return label.Layout(gtx)
+				})
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, status)
+					label.Color = v.theme.Colors.TextSecondary
+					if statusErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, "Cancel", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.create, createLabel, warning != "")
+					},
+				)
+			}),
+		)
+	})
+}
+
+// layoutFieldRow lays out text fields side by side with equal widths.
+func (v *NetworksView) layoutFieldRow(gtx layout.Context, fields ...layout.Widget) layout.Dimensions {
+	children := make([]layout.FlexChild, 0, 2*len(fields))
+	for i, field := range fields {
+		if i > 0 {
+			children = append(children, layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout))
+		}
+		children = append(children, layout.Flexed(1, field))
+	}
+	return layout.Flex{Axis: layout.Horizontal}.Layout(gtx, children...)
+}
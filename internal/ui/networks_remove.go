@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// Network removal actions of the confirmation dialog
+const (
+	networkActionRemove = "remove"
+	networkActionPrune  = "prune"
+)
+
+// networkRemoveDialog holds the state of the remove and prune confirmation dialog.
+type networkRemoveDialog struct {
+	action  string // Empty when the dialog is closed
+	network docker.Network
+	confirm widget.Clickable
+	cancel  widget.Clickable
+	list    widget.List
+}
+
+// openRemoveDialog asks for confirmation before removing a network.
+func (v *NetworksView) openRemoveDialog(net docker.Network) {
+	d := &v.removeDialog
+	d.action = networkActionRemove
+	d.network = net
+}
+
+// openPruneDialog asks for confirmation before pruning networks.
+func (v *NetworksView) openPruneDialog() {
+	v.removeDialog.action = networkActionPrune
+}
+
+// pruneCandidates returns the networks a prune would remove: all custom
+// networks no container is connected to.
+func pruneCandidates(networks []docker.Network) []docker.Network {
+	var candidates []docker.Network
+	for _, net := range networks {
+		if !net.Predefined() && !net.InUse() {
+			candidates = append(candidates, net)
+		}
+	}
+	return candidates
+}
+
+// runRemoveAction performs the confirmed action in the background.
+func (v *NetworksView) runRemoveAction() {
+	d := &v.removeDialog
+	action := d.action
+	net := d.network
+
+	var btns *networkRowButtons
+	if action == networkActionRemove {
+		btns = v.getNetworkButtons(net.ID)
+		btns.processing = true
+	} else {
+		v.pruning = true
+	}
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		switch action {
+		case networkActionRemove:
+			if err := v.docker.RemoveNetwork(ctx, net.ID); err != nil {
+				v.setNetworkError("Failed to remove "+net.Name, err)
+			} else {
+				v.setNotice("Removed " + net.Name)
+			}
+			btns.processing = false
+		case networkActionPrune:
+			deleted, err := v.docker.PruneNetworks(ctx)
+			switch {
+			case err != nil:
+				v.setNetworkError("Failed to prune networks", err)
+			case len(deleted) == 0:
+				v.setNotice("No networks removed")
+			default:
+				v.setNotice("Removed " + pluralize(len(deleted), "network") + ": " + strings.Join(deleted, ", "))
+			}
+			v.pruning = false
+		}
+	}()
+}
+
+// layoutRemoveDialog renders the confirmation dialog overlay when it is open.
+func (v *NetworksView) layoutRemoveDialog(gtx layout.Context, networks []docker.Network) layout.Dimensions {
+	d := &v.removeDialog
+	if d.action == "" {
+		return layout.Dimensions{}
+	}
+
+	if d.cancel.Clicked(gtx) {
+		d.action = ""
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+
+	candidates := pruneCandidates(networks)
+	if d.confirm.Clicked(gtx) && !(d.action == networkActionPrune && len(candidates) == 0) {
+		v.runRemoveAction()
+		d.action = ""
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+
+	var title, confirmLabel string
+	var body layout.Widget
+	switch d.action {
+	case networkActionRemove:
+		title = "Remove Network"
+		confirmLabel = "Remove"
+		body = func(gtx layout.Context) layout.Dimensions {
+			return layoutDialogText(gtx, v.theme, "Network "+d.network.Name+" will be removed.")
+		}
+	case networkActionPrune:
+		title = "Prune Networks"
+		confirmLabel = "Prune"
+		body = func(gtx layout.Context) layout.Dimensions {
+			if len(candidates) == 0 {
+				return layoutDialogText(gtx, v.theme, "Nothing to prune.")
+			}
+			lines := make([]string, len(candidates))
+			for i, net := range candidates {
+				lines[i] = net.Name
+				if project := net.Project(); project != "" {
+					lines[i] += "  (" + project + ")"
+				}
+			}
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutDialogText(gtx, v.theme, intToStr(len(candidates))+" networks no container is connected to will be removed:")
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutItemList(gtx, v.theme, &d.list, lines)
+				}),
+			)
+		}
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(520), func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.H6(v.theme.Material, title)
+				label.Color = v.theme.Colors.Text
+				return label.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			layout.Rigid(body),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, "Cancel", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.confirm, confirmLabel, true)
+					},
+				)
+			}),
+		)
+	})
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+)
+
+const (
+	// notificationDuration is how long an in-app notification stays visible.
+	notificationDuration = 10 * time.Second
+
+	// maxNotifications limits how many in-app notifications are stacked at once.
+	maxNotifications = 4
+)
+
+// inAppNotification is a single notification card.
+type inAppNotification struct {
+	title   string
+	body    string
+	expiry  time.Time
+	dismiss widget.Clickable
+}
+
+// NotificationCenter shows notifications stacked in the corner of the main window.
+// It is used when desktop notifications are unavailable or not wanted.
+type NotificationCenter struct {
+	theme *Theme
+
+	mu    sync.Mutex
+	items []*inAppNotification
+}
+
+// NewNotificationCenter creates a new notification center.
+func NewNotificationCenter(theme *Theme) *NotificationCenter {
+	return &NotificationCenter{theme: theme}
+}
+
+// Push adds a notification. It is safe to call from any goroutine.
+func (n *NotificationCenter) Push(title, body string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.items = append(n.items, &inAppNotification{
+		title:  title,
+		body:   body,
+		expiry: time.Now().Add(notificationDuration),
+	})
+	if len(n.items) > maxNotifications {
+		n.items = n.items[len(n.items)-maxNotifications:]
+	}
+}
+
+// Layout renders the visible notifications in the bottom right corner.
+func (n *NotificationCenter) Layout(gtx layout.Context) layout.Dimensions {
+	n.mu.Lock()
+	now := time.Now()
+	visible := n.items[:0]
+	for _, item := range n.items {
+		if item.dismiss.Clicked(gtx) || now.After(item.expiry) {
+			continue
+		}
+		visible = append(visible, item)
+	}
+	n.items = visible
+	items := append([]*inAppNotification(nil), visible...)
+	n.mu.Unlock()
+
+	if len(items) == 0 {
+		return layout.Dimensions{}
+	}
+
+	children := make([]layout.FlexChild, 0, len(items))
+	for _, item := range items {
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return n.layoutItem(gtx, item)
+			})
+		}))
+	}
+
+	return layout.SE.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Inset{Right: unit.Dp(16), Bottom: unit.Dp(16)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			gtx.Constraints.Max.X = min(gtx.Constraints.Max.X, gtx.Dp(unit.Dp(360)))
+			gtx.Constraints.Min.X = gtx.Constraints.Max.X
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+		})
+	})
+}
+
+func (n *NotificationCenter) layoutItem(gtx layout.Context, item *inAppNotification) layout.Dimensions {
+	return layout.Stack{}.Layout(gtx,
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return fillRounded(gtx, n.theme.Colors.CardBg, 6)
+		}),
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return layout.Inset{
+				Top:    unit.Dp(10),
+				Bottom: unit.Dp(10),
+				Left:   unit.Dp(12),
+				Right:  unit.Dp(12),
+			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								label := material.Body2(n.theme.Material, item.title)
+								label.Color = n.theme.Colors.Text
+								return label.Layout(gtx)
+							}),
+							layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+								label := material.Caption(n.theme.Material, item.body)
+								label.Color = n.theme.Colors.TextSecondary
+								label.MaxLines = 3
+								return label.Layout(gtx)
+							}),
+						)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return item.dismiss.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+							label := material.Body2(n.theme.Material, "✕")
+							label.Color = n.theme.Colors.TextMuted
+							return label.Layout(gtx)
+						})
+					}),
+				)
+			})
+		}),
+	)
+}
@@ -2,6 +2,8 @@ package ui
 
 import (
 	"image"
+	"regexp"
+	"strings"
 
 	"gioui.org/layout"
 	"gioui.org/op/clip"
@@ -12,26 +14,77 @@ import (
 
 	"github.com/tsukinoko-kun/harbor/internal/config"
 	"github.com/tsukinoko-kun/harbor/internal/version"
+	"github.com/tsukinoko-kun/harbor/internal/watch"
 )
 
+// watchScopeOptions are the scopes offered when adding a watch rule.
+var watchScopeOptions = []radioOption{
+	{"all", "All containers"},
+	{config.WatchScopeContainer, "Container"},
+	{config.WatchScopeProject, "Project"},
+	{config.WatchScopeImage, "Image"},
+}
+
+// watchActionOptions are the actions offered when adding a watch rule.
+var watchActionOptions = []radioOption{
+	{config.WatchActionNotify, "Notify"},
+	{config.WatchActionHighlight, "Highlight"},
+	{config.WatchActionCount, "Count"},
+}
+
 // SettingsView displays the application settings.
 type SettingsView struct {
 	theme           *Theme
 	settings        *config.Settings
+	watcher         *watch.Watcher
+	invalidate      func() // Requests a redraw from background goroutines
 	list            widget.List
 	terminalButtons []widget.Clickable
+
+	// Watch rules
+	ruleRemoveButtons []widget.Clickable
+	rulePattern       widget.Editor
+	ruleScope         widget.Enum
+	ruleTarget        widget.Editor
+	ruleAction        widget.Enum
+	ruleAdd           widget.Clickable
+	ruleError         string
+
+	// Log display
+	multiline        widget.Bool
+	multilinePattern widget.Editor
+	multilineApply   widget.Clickable
+	multilineStatus  string
+	multilineErr     bool
+
+	// Volume browser
+	helperImage       widget.Editor
+	helperImageApply  widget.Clickable
+	helperImageStatus string
+
+	// Registry credentials
+	registry registrySettings
 }
 
 // NewSettingsView creates a new settings view.
-func NewSettingsView(theme *Theme, settings *config.Settings) *SettingsView {
-	return &SettingsView{
-		theme:    theme,
-		settings: settings,
+func NewSettingsView(theme *Theme, settings *config.Settings, watcher *watch.Watcher, invalidate func()) *SettingsView {
+	v := &SettingsView{
+		theme:      theme,
+		settings:   settings,
+		watcher:    watcher,
+		invalidate: invalidate,
 		list: widget.List{
 			List: layout.List{Axis: layout.Vertical},
 		},
 		terminalButtons: make([]widget.Clickable, len(settings.Terminals)),
+		ruleScope:       widget.Enum{Value: "all"},
+		ruleAction:      widget.Enum{Value: config.WatchActionNotify},
+		multiline:       widget.Bool{Value: !settings.Logs.DisableMultiline},
 	}
+	v.multilinePattern.SetText(settings.Logs.MultilinePattern)
+	v.helperImage.SetText(settings.Volumes.HelperImage)
+	v.loadRegistries()
+	return v
 }
 
 // Layout renders the settings view.
@@ -83,11 +136,19 @@ func (v *SettingsView) layoutHeader(gtx layout.Context) layout.Dimensions {
 }
 
 func (v *SettingsView) layoutContent(gtx layout.Context) layout.Dimensions {
-	return v.list.Layout(gtx, 2, func(gtx layout.Context, index int) layout.Dimensions {
+	return v.list.Layout(gtx, 6, func(gtx layout.Context, index int) layout.Dimensions {
 		switch index {
 		case 0:
 			return v.layoutTerminalSection(gtx)
 		case 1:
+			return v.layoutLogsSection(gtx)
+		case 2:
+			return v.layoutWatchSection(gtx)
+		case 3:
+			return v.layoutVolumesSection(gtx)
+		case 4:
+			return v.layoutRegistrySection(gtx)
+		case 5:
 			return v.layoutVersionSection(gtx)
 		default:
 			return layout.Dimensions{}
@@ -240,6 +301,312 @@ func (v *SettingsView) layoutRadio(gtx layout.Context, isSelected bool) layout.D
 	return layout.Dimensions{Size: image.Point{X: size, Y: size}}
 }
 
+// applyMultilinePattern validates and stores the custom multiline pattern.
+// Open logs windows pick up the change when they are opened again.
+func (v *SettingsView) applyMultilinePattern() {
+	pattern := strings.TrimSpace(v.multilinePattern.Text())
+	if pattern != "" {
+		if _, err := regexp.Compile(pattern); err != nil {
+			v.multilineStatus = "Invalid regular expression: " + err.Error()
+			v.multilineErr = true
+			return
+		}
+	}
+
+	v.settings.Logs.MultilinePattern = pattern
+	v.multilineStatus = "Saved. Applies to newly opened logs windows."
+	v.multilineErr = false
+	go func() {
+		_ = v.settings.Save()
+	}()
+}
+
+func (v *SettingsView) layoutLogsSection(gtx layout.Context) layout.Dimensions {
+	if v.multiline.Update(gtx) {
+		v.settings.Logs.DisableMultiline = !v.multiline.Value
+		go func() {
+			_ = v.settings.Save()
+		}()
+	}
+	if v.multilineApply.Clicked(gtx) {
+		v.applyMultilinePattern()
+	}
+
+	return layout.Inset{Top: unit.Dp(24)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Section header
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.H6(v.theme.Material, "Logs")
+					label.Color = v.theme.Colors.Text
+					return label.Layout(gtx)
+				})
+			}),
+			// Description
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(16)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, "Group multiline entries such as stack traces. Lines starting with whitespace, \"Caused by\" or \"Traceback\" join the previous entry, as do lines matching the pattern below.")
+					label.Color = v.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutCheckBox(gtx, v.theme, &v.multiline, "Group multiline log entries")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &v.multilinePattern, "Continuation pattern", `^\.\.\. \d+ more$`)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if v.multilineStatus == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, v.multilineStatus)
+					label.Color = v.theme.Colors.TextMuted
+					if v.multilineErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.multilineApply, "Save Pattern", false, false)
+			}),
+		)
+	})
+}
+
+func (v *SettingsView) layoutVolumesSection(gtx layout.Context) layout.Dimensions {
+	if v.helperImageApply.Clicked(gtx) {
+		v.settings.Volumes.HelperImage = strings.TrimSpace(v.helperImage.Text())
+		v.helperImageStatus = "Saved. Applies to newly opened volume browsers and connectivity checks."
+		go func() {
+			_ = v.settings.Save()
+		}()
+	}
+
+	return layout.Inset{Top: unit.Dp(24)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Section header
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.H6(v.theme.Material, "Volumes")
+					label.Color = v.theme.Colors.Text
+					return label.Layout(gtx)
+				})
+			}),
+			// Description
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(16)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, "Volumes whose mountpoint Harbor cannot read directly are browsed through a container that mounts them read-only. Connectivity checks from containers without a shell or network tools run in the same image. Leave the image empty to use a local busybox or alpine image.")
+					label.Color = v.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &v.helperImage, "Helper image", "busybox:latest")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if v.helperImageStatus == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, v.helperImageStatus)
+					label.Color = v.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.helperImageApply, "Save Image", false, false)
+			}),
+		)
+	})
+}
+
+// saveWatchRules applies the watch rules to the watcher and persists them.
+func (v *SettingsView) saveWatchRules() {
+	rules := append([]config.WatchRule(nil), v.settings.WatchRules...)
+	v.watcher.SetRules(rules)
+	go func() {
+		_ = v.settings.Save()
+	}()
+}
+
+// addWatchRule validates the form and adds a new watch rule.
+func (v *SettingsView) addWatchRule() {
+	pattern := v.rulePattern.Text()
+	if pattern == "" {
+		v.ruleError = "Enter a regular expression"
+		return
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		v.ruleError = "Invalid regular expression: " + err.Error()
+		return
+	}
+
+	rule := config.WatchRule{
+		Pattern: pattern,
+		Action:  v.ruleAction.Value,
+	}
+	if v.ruleScope.Value != "all" {
+		rule.Scope = v.ruleScope.Value
+		rule.Target = strings.TrimSpace(v.ruleTarget.Text())
+		if rule.Target == "" {
+			v.ruleError = "Enter the " + rule.Scope + " the rule applies to"
+			return
+		}
+	}
+
+	v.settings.WatchRules = append(v.settings.WatchRules, rule)
+	v.saveWatchRules()
+	v.rulePattern.SetText("")
+	v.ruleTarget.SetText("")
+	v.ruleError = ""
+}
+
+func (v *SettingsView) layoutWatchSection(gtx layout.Context) layout.Dimensions {
+	// Handle rule removal and creation
+	if len(v.ruleRemoveButtons) != len(v.settings.WatchRules) {
+		v.ruleRemoveButtons = make([]widget.Clickable, len(v.settings.WatchRules))
+	}
+	for i := range v.ruleRemoveButtons {
+		if v.ruleRemoveButtons[i].Clicked(gtx) {
+			v.settings.WatchRules = append(v.settings.WatchRules[:i:i], v.settings.WatchRules[i+1:]...)
+			v.saveWatchRules()
+			v.ruleRemoveButtons = make([]widget.Clickable, len(v.settings.WatchRules))
+			break
+		}
+	}
+	if v.ruleAdd.Clicked(gtx) {
+		v.addWatchRule()
+	}
+
+	return layout.Inset{Top: unit.Dp(24)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		children := []layout.FlexChild{
+			// Section header
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.H6(v.theme.Material, "Log Alerts")
+					label.Color = v.theme.Colors.Text
+					return label.Layout(gtx)
+				})
+			}),
+			// Description
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(16)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, "Watch the logs of all running containers for a regular expression, even without an open logs window.")
+					label.Color = v.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				})
+			}),
+		}
+
+		for i, rule := range v.settings.WatchRules {
+			idx := i
+			r := rule
+			children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return v.layoutWatchRule(gtx, &v.ruleRemoveButtons[idx], r)
+			}))
+		}
+
+		children = append(children, layout.Rigid(v.layoutWatchRuleForm))
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *SettingsView) layoutWatchRule(gtx layout.Context, remove *widget.Clickable, rule config.WatchRule) layout.Dimensions {
+	scope := "All containers"
+	if rule.Scope != config.WatchScopeAll {
+		scope = rule.Scope + ": " + rule.Target
+	}
+	details := scope + " • " + rule.Action + " • " + intToStr(v.watcher.RuleCount(rule)) + " matches"
+
+	return layout.Inset{Bottom: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Stack{}.Layout(gtx,
+			layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+				return fillRounded(gtx, v.theme.Colors.CardBg, 6)
+			}),
+			layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{
+					Top:    unit.Dp(12),
+					Bottom: unit.Dp(12),
+					Left:   unit.Dp(16),
+					Right:  unit.Dp(16),
+				}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+							return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+									label := material.Body1(v.theme.Material, rule.Pattern)
+									label.Color = v.theme.Colors.Text
+									return label.Layout(gtx)
+								}),
+								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+									label := material.Caption(v.theme.Material, details)
+									label.Color = v.theme.Colors.TextMuted
+									return label.Layout(gtx)
+								}),
+							)
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							return layoutActionButton(gtx, v.theme, remove, "Remove", true, false)
+						}),
+					)
+				})
+			}),
+		)
+	})
+}
+
+func (v *SettingsView) layoutWatchRuleForm(gtx layout.Context) layout.Dimensions {
+	children := []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutTextField(gtx, v.theme, &v.rulePattern, "Pattern", "panic:|OutOfMemoryError")
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutSectionLabel(gtx, v.theme, "Scope")
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutRadioRow(gtx, v.theme, &v.ruleScope, watchScopeOptions)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if v.ruleScope.Value == "all" {
+				return layout.Dimensions{}
+			}
+			return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &v.ruleTarget, "", "Name of the "+v.ruleScope.Value)
+			})
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutSectionLabel(gtx, v.theme, "Action")
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutRadioRow(gtx, v.theme, &v.ruleAction, watchActionOptions)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if v.ruleError == "" {
+				return layout.Dimensions{}
+			}
+			return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				label := material.Body2(v.theme.Material, v.ruleError)
+				label.Color = v.theme.Colors.StatusStopped
+				return label.Layout(gtx)
+			})
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutActionButton(gtx, v.theme, &v.ruleAdd, "Add Rule", false, false)
+		}),
+	}
+
+	return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
 func (v *SettingsView) layoutVersionSection(gtx layout.Context) layout.Dimensions {
 	return layout.Inset{Top: unit.Dp(24)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
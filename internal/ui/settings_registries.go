@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/registry"
+)
+
+// registrySettings holds the state of the registry credentials section.
+type registrySettings struct {
+	logoutButtons []widget.Clickable
+	host          widget.Editor
+	username      widget.Editor
+	password      widget.Editor
+	login         widget.Clickable
+
+	// Updated from background goroutines
+	mu         sync.Mutex
+	registries []registry.Registry
+	loaded     bool
+	busy       bool
+	status     string
+	statusErr  bool
+}
+
+// loadRegistries reads the known registries in the background.
+func (v *SettingsView) loadRegistries() {
+	go func() {
+		defer v.invalidate()
+
+		registries, err := registry.Registries()
+
+		r := &v.registry
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.loaded = true
+		if err != nil {
+			r.status = "Failed to read the Docker config: " + err.Error()
+			r.statusErr = true
+			return
+		}
+		r.registries = registries
+	}()
+}
+
+// loginRegistry validates and stores credentials in the background.
+func (v *SettingsView) loginRegistry(host, username, password string) {
+	r := &v.registry
+	r.mu.Lock()
+	r.busy = true
+	r.status = "Logging in to " + host + "…"
+	r.statusErr = false
+	r.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		creds, err := registry.Login(ctx, host, username, password)
+		if err == nil {
+			err = registry.Store(creds)
+		}
+
+		r.mu.Lock()
+		r.busy = false
+		switch {
+		case errors.Is(err, registry.ErrNoCredentialHelper):
+			r.status = "Cannot store credentials: " + err.Error() + ". Install a credential helper such as docker-credential-pass or docker-credential-secretservice."
+			r.statusErr = true
+		case err != nil:
+			r.status = "Login failed: " + err.Error()
+			r.statusErr = true
+		default:
+			r.status = "Logged in to " + registry.NormalizeHost(host) + " as " + username
+		}
+		r.mu.Unlock()
+
+		if err == nil {
+			v.loadRegistries()
+		}
+	}()
+}
+
+// logoutRegistry erases the credentials of a registry in the background.
+func (v *SettingsView) logoutRegistry(host string) {
+	r := &v.registry
+	r.mu.Lock()
+	r.busy = true
+	r.status = "Logging out of " + host + "…"
+	r.statusErr = false
+	r.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		err := registry.Logout(host)
+
+		r.mu.Lock()
+		r.busy = false
+		if err != nil {
+			r.status = "Logout failed: " + err.Error()
+			r.statusErr = true
+		} else {
+			r.status = "Logged out of " + host
+		}
+		r.mu.Unlock()
+
+		v.loadRegistries()
+	}()
+}
+
+func (v *SettingsView) layoutRegistrySection(gtx layout.Context) layout.Dimensions {
+	r := &v.registry
+
+	r.mu.Lock()
+	registries := r.registries
+	loaded := r.loaded
+	busy := r.busy
+	status := r.status
+	statusErr := r.statusErr
+	r.mu.Unlock()
+
+	if len(r.logoutButtons) != len(registries) {
+		r.logoutButtons = make([]widget.Clickable, len(registries))
+	}
+	for i := range r.logoutButtons {
+		if r.logoutButtons[i].Clicked(gtx) && !busy {
+			v.logoutRegistry(registries[i].Host)
+			busy = true
+		}
+	}
+	if r.login.Clicked(gtx) && !busy {
+		host := strings.TrimSpace(r.host.Text())
+		username := strings.TrimSpace(r.username.Text())
+		password := r.password.Text()
+		if host == "" {
+			host = registry.DockerHubHost
+		}
+		if username == "" || password == "" {
+			r.mu.Lock()
+			r.status = "Please enter a username and password"
+			r.statusErr = true
+			r.mu.Unlock()
+		} else {
+			r.password.SetText("")
+			v.loginRegistry(host, username, password)
+			busy = true
+		}
+	}
+
+	return layout.Inset{Top: unit.Dp(24)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		children := []layout.FlexChild{
+			// Section header
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.H6(v.theme.Material, "Registries")
+					label.Color = v.theme.Colors.Text
+					return label.Layout(gtx)
+				})
+			}),
+			// Description
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(16)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, "Credentials are shared with the Docker CLI and kept in its credential helper. Pull, push and build use them automatically.")
+					label.Color = v.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				})
+			}),
+		}
+
+		if loaded && len(registries) == 0 {
+			children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{Bottom: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, "No registries configured")
+					label.Color = v.theme.Colors.TextMuted
+					return label.Layout(gtx)
+				})
+			}))
+		}
+		for i, reg := range registries {
+			idx := i
+			reg := reg
+			children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return v.layoutRegistry(gtx, &r.logoutButtons[idx], reg, busy)
+			}))
+		}
+
+		children = append(children, layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutLoginForm(gtx, busy, status, statusErr)
+		}))
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *SettingsView) layoutRegistry(gtx layout.Context, logout *widget.Clickable, reg registry.Registry, busy bool) layout.Dimensions {
+	details := "Not logged in"
+	if reg.LoggedIn {
+		details = "Logged in"
+		if reg.Username != "" {
+			details += " as " + reg.Username
+		}
+	}
+	if reg.Helper != "" {
+		details += " • docker-credential-" + reg.Helper
+	} else if reg.LoggedIn {
+		details += " • stored in plain text"
+	}
+
+	return layout.Inset{Bottom: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Stack{}.Layout(gtx,
+			layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+				return fillRounded(gtx, v.theme.Colors.CardBg, 6)
+			}),
+			layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+				return layout.Inset{
+					Top:    unit.Dp(12),
+					Bottom: unit.Dp(12),
+					Left:   unit.Dp(16),
+					Right:  unit.Dp(16),
+				}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+							return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+									label := material.Body1(v.theme.Material, reg.Host)
+									label.Color = v.theme.Colors.Text
+									return label.Layout(gtx)
+								}),
+								layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+									label := material.Caption(v.theme.Material, details)
+									label.Color = v.theme.Colors.TextMuted
+									return label.Layout(gtx)
+								}),
+							)
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							if !reg.LoggedIn {
+								return layout.Dimensions{}
+							}
+							return layoutActionButton(gtx, v.theme, logout, "Log Out", true, busy)
+						}),
+					)
+				})
+			}),
+		)
+	})
+}
+
+func (v *SettingsView) layoutLoginForm(gtx layout.Context, busy bool, status string, statusErr bool) layout.Dimensions {
+	r := &v.registry
+	r.password.Mask = '•'
+
+	loginLabel := "Log In"
+	if busy {
+		loginLabel = "Working…"
+	}
+
+	return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &r.host, "Registry", registry.DockerHubHost)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return layoutTextField(gtx, v.theme, &r.username, "Username", "")
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						return layoutTextField(gtx, v.theme, &r.password, "Password or access token", "")
+					}),
+				)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(8)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, status)
+					label.Color = v.theme.Colors.TextSecondary
+					if statusErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &r.login, loginLabel, false, busy)
+			}),
+		)
+	})
+}
@@ -0,0 +1,468 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"gioui.org/f32"
+	"gioui.org/io/event"
+	"gioui.org/io/pointer"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/text"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// Zoom limits of the topology graph
+const (
+	topoMinZoom  = 0.2
+	topoMaxZoom  = 3
+	topoZoomStep = 1.25
+)
+
+// topoClickSlop is how far, in pixels, the pointer may move between press and
+// release for the gesture to count as a click instead of a pan.
+const topoClickSlop = 4
+
+// TopologyView draws containers and networks as a graph.
+type TopologyView struct {
+	theme          *Theme
+	docker         *docker.Client
+	showContainers func(ids []string) // Switches to the containers view
+
+	zoomIn  widget.Clickable
+	zoomOut widget.Clickable
+	fit     widget.Clickable
+
+	// View transform: screen = offset + graph * zoom (graph in pixels at zoom 1)
+	zoom   float32
+	offset f32.Point
+	fitted bool // Whether the graph was fitted to the canvas since it was last reset
+	size   image.Point
+
+	// Pointer state
+	pressed  bool
+	moved    bool
+	pressPos f32.Point
+	lastPos  f32.Point
+	hoverCtr int
+	hoverNet int
+}
+
+// NewTopologyView creates a new topology view.
+func NewTopologyView(theme *Theme, dockerClient *docker.Client, showContainers func(ids []string)) *TopologyView {
+	return &TopologyView{
+		theme:          theme,
+		docker:         dockerClient,
+		showContainers: showContainers,
+		zoom:           1,
+		hoverCtr:       -1,
+		hoverNet:       -1,
+	}
+}
+
+// Layout renders the topology view.
+// aliases are the network aliases of the containers, see docker.Client.NetworkAliases.
+func (v *TopologyView) Layout(gtx layout.Context, groups []docker.ContainerGroup, networks []docker.Network, aliases map[string]map[string][]string) layout.Dimensions {
+	graph := layoutTopology(groups, networks, aliases)
+
+	if v.zoomIn.Clicked(gtx) {
+		v.zoomAt(f32.Pt(float32(v.size.X)/2, float32(v.size.Y)/2), topoZoomStep)
+	}
+	if v.zoomOut.Clicked(gtx) {
+		v.zoomAt(f32.Pt(float32(v.size.X)/2, float32(v.size.Y)/2), 1/topoZoomStep)
+	}
+	if v.fit.Clicked(gtx) {
+		v.fitted = false
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		// Header
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutHeader(gtx, graph)
+		}),
+		// Graph
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			if len(graph.containers) == 0 && len(graph.networks) == 0 {
+				return v.layoutEmpty(gtx)
+			}
+			return v.layoutCanvas(gtx, &graph)
+		}),
+	)
+}
+
+func (v *TopologyView) layoutHeader(gtx layout.Context, graph topologyLayout) layout.Dimensions {
+	return layout.Inset{
+		Top:    unit.Dp(20),
+		Bottom: unit.Dp(16),
+		Left:   unit.Dp(16),
+		Right:  unit.Dp(16),
+	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Baseline}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := material.H5(v.theme.Material, "Topology")
+				title.Color = v.theme.Colors.Text
+				return title.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				info := pluralize(len(graph.containers), "container") + " • " + pluralize(len(graph.networks), "network") +
+					" • drag to pan, scroll to zoom, click a node to open it"
+				label := material.Body2(v.theme.Material, info)
+				label.Color = v.theme.Colors.TextMuted
+				return label.Layout(gtx)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Dimensions{}
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.zoomOut, "−", false, v.zoom <= topoMinZoom)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(4)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.zoomIn, "+", false, v.zoom >= topoMaxZoom)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.fit, "Fit", false, false)
+			}),
+		)
+	})
+}
+
+func (v *TopologyView) layoutEmpty(gtx layout.Context) layout.Dimensions {
+	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		label := material.Body1(v.theme.Material, "No containers or networks found")
+		label.Color = v.theme.Colors.TextMuted
+		return label.Layout(gtx)
+	})
+}
+
+// zoomAt changes the zoom by factor, keeping the graph point under p in place.
+func (v *TopologyView) zoomAt(p f32.Point, factor float32) {
+	zoom := min(max(v.zoom*factor, topoMinZoom), topoMaxZoom)
+	v.offset = p.Sub(p.Sub(v.offset).Mul(zoom / v.zoom))
+	v.zoom = zoom
+}
+
+// fitGraph scales and centers the graph in the canvas. It never zooms in past 100%.
+func (v *TopologyView) fitGraph(gtx layout.Context, graph *topologyLayout) {
+	ppdp := gtx.Metric.PxPerDp
+	margin := float32(gtx.Dp(unit.Dp(24)))
+	w := (graph.bounds.Max.X - graph.bounds.Min.X) * ppdp
+	h := (graph.bounds.Max.Y - graph.bounds.Min.Y) * ppdp
+	zoom := float32(1)
+	if w > 0 && h > 0 {
+		zoom = min((float32(v.size.X)-2*margin)/w, (float32(v.size.Y)-2*margin)/h, 1)
+	}
+	v.zoom = min(max(zoom, topoMinZoom), topoMaxZoom)
+	v.offset = f32.Pt(
+		(float32(v.size.X)-w*v.zoom)/2-graph.bounds.Min.X*ppdp*v.zoom,
+		(float32(v.size.Y)-h*v.zoom)/2-graph.bounds.Min.Y*ppdp*v.zoom,
+	)
+	v.fitted = true
+}
+
+// toGraph converts a canvas position to graph coordinates (dp).
+func (v *TopologyView) toGraph(gtx layout.Context, p f32.Point) f32.Point {
+	return p.Sub(v.offset).Div(v.zoom * gtx.Metric.PxPerDp)
+}
+
+// handlePointer pans, zooms and opens clicked nodes.
+func (v *TopologyView) handlePointer(gtx layout.Context, graph *topologyLayout) {
+	for {
+		ev, ok := gtx.Event(pointer.Filter{
+			Target:  v,
+			Kinds:   pointer.Press | pointer.Drag | pointer.Release | pointer.Cancel | pointer.Move | pointer.Leave | pointer.Scroll,
+			ScrollY: pointer.ScrollRange{Min: -1 << 16, Max: 1 << 16},
+		})
+		if !ok {
+			break
+		}
+		e, ok := ev.(pointer.Event)
+		if !ok {
+			continue
+		}
+
+		switch e.Kind {
+		case pointer.Press:
+			if e.Source == pointer.Mouse && e.Buttons != pointer.ButtonPrimary {
+				continue
+			}
+			v.pressed = true
+			v.moved = false
+			v.pressPos = e.Position
+			v.lastPos = e.Position
+		case pointer.Drag:
+			if !v.pressed {
+				continue
+			}
+			v.offset = v.offset.Add(e.Position.Sub(v.lastPos))
+			v.lastPos = e.Position
+			if d := e.Position.Sub(v.pressPos); d.X*d.X+d.Y*d.Y > topoClickSlop*topoClickSlop {
+				v.moved = true
+			}
+		case pointer.Release:
+			if v.pressed && !v.moved {
+				v.openNode(graph, v.toGraph(gtx, e.Position))
+			}
+			v.pressed = false
+		case pointer.Cancel:
+			v.pressed = false
+		case pointer.Move:
+			p := v.toGraph(gtx, e.Position)
+			v.hoverCtr = graph.hitContainer(p)
+			v.hoverNet = graph.hitNetwork(p)
+		case pointer.Leave:
+			v.hoverCtr = -1
+			v.hoverNet = -1
+		case pointer.Scroll:
+			// Wheels scroll in steps of several pixels; one step zooms by about 10%
+			v.zoomAt(e.Position, float32(math.Pow(1.1, float64(-e.Scroll.Y)/20)))
+		}
+	}
+
+	// The graph may have changed since the last pointer event
+	if v.hoverCtr >= len(graph.containers) {
+		v.hoverCtr = -1
+	}
+	if v.hoverNet >= len(graph.networks) {
+		v.hoverNet = -1
+	}
+}
+
+// openNode shows the container in the containers view or opens the network's detail window.
+func (v *TopologyView) openNode(graph *topologyLayout, p f32.Point) {
+	if i := graph.hitContainer(p); i >= 0 {
+		v.showContainers([]string{graph.containers[i].container.ID})
+		return
+	}
+	if i := graph.hitNetwork(p); i >= 0 {
+		NewNetworkWindow(v.theme, v.docker, graph.networks[i].network)
+	}
+}
+
+func (v *TopologyView) layoutCanvas(gtx layout.Context, graph *topologyLayout) layout.Dimensions {
+	v.size = gtx.Constraints.Max
+	if !v.fitted {
+		v.fitGraph(gtx, graph)
+	}
+	v.handlePointer(gtx, graph)
+
+	defer clip.Rect{Max: v.size}.Push(gtx.Ops).Pop()
+	event.Op(gtx.Ops, v)
+	switch {
+	case v.pressed && v.moved:
+		pointer.CursorGrabbing.Add(gtx.Ops)
+	case v.hoverCtr >= 0 || v.hoverNet >= 0:
+		pointer.CursorPointer.Add(gtx.Ops)
+	default:
+		pointer.CursorGrab.Add(gtx.Ops)
+	}
+
+	transform := f32.Affine2D{}.Scale(f32.Point{}, f32.Pt(v.zoom, v.zoom)).Offset(v.offset)
+	defer op.Affine(transform).Push(gtx.Ops).Pop()
+
+	for _, g := range graph.groups {
+		v.drawGroup(gtx, g)
+	}
+	for _, e := range graph.edges {
+		highlight := e.container == v.hoverCtr || e.network == v.hoverNet
+		v.drawEdge(gtx, graph, e, highlight)
+	}
+	for _, e := range graph.edges {
+		if len(e.aliases) > 0 {
+			v.drawEdgeLabel(gtx, graph, e)
+		}
+	}
+	for i, n := range graph.networks {
+		v.drawNetwork(gtx, n, i == v.hoverNet)
+	}
+	for i, c := range graph.containers {
+		v.drawContainer(gtx, c, i == v.hoverCtr)
+	}
+
+	return layout.Dimensions{Size: v.size}
+}
+
+// pxRect converts a graph rectangle to pixels at zoom 1.
+func pxRect(gtx layout.Context, r topoRect) image.Rectangle {
+	ppdp := gtx.Metric.PxPerDp
+	return image.Rect(
+		int(r.Min.X*ppdp), int(r.Min.Y*ppdp),
+		int(r.Max.X*ppdp), int(r.Max.Y*ppdp),
+	)
+}
+
+// drawBox fills a rounded rectangle and strokes its border.
+func drawBox(gtx layout.Context, r image.Rectangle, radius int, fill, border color.NRGBA, borderWidth unit.Dp) {
+	paint.FillShape(gtx.Ops, fill, clip.UniformRRect(r, radius).Op(gtx.Ops))
+	paint.FillShape(gtx.Ops, border, clip.Stroke{
+		Path:  clip.UniformRRect(r, radius).Path(gtx.Ops),
+		Width: float32(gtx.Dp(borderWidth)),
+	}.Op())
+}
+
+// drawLabel draws a single line of text into r.
+func drawLabel(gtx layout.Context, label material.LabelStyle, r image.Rectangle) {
+	label.MaxLines = 1
+	defer op.Offset(r.Min).Push(gtx.Ops).Pop()
+	gtx.Constraints = layout.Constraints{Min: image.Pt(r.Dx(), 0), Max: r.Size()}
+	label.Layout(gtx)
+}
+
+func (v *TopologyView) drawGroup(gtx layout.Context, g topoGroup) {
+	r := pxRect(gtx, g.rect)
+	drawBox(gtx, r, gtx.Dp(unit.Dp(8)), v.theme.Colors.Surface, v.theme.Colors.Border, unit.Dp(1))
+
+	name := g.name
+	if name == "" {
+		name = "Standalone"
+	}
+	pad := gtx.Dp(unit.Dp(topoGroupPadding))
+	label := material.Caption(v.theme.Material, name)
+	label.Color = v.theme.Colors.TextSecondary
+	drawLabel(gtx, label, image.Rect(r.Min.X+pad, r.Min.Y+gtx.Dp(unit.Dp(4)), r.Max.X-pad, r.Min.Y+gtx.Dp(unit.Dp(topoGroupLabel))))
+}
+
+// edgeEnds returns the attachment points of an edge in pixels at zoom 1.
+func edgeEnds(gtx layout.Context, graph *topologyLayout, e topoEdge) (from, to f32.Point) {
+	ppdp := gtx.Metric.PxPerDp
+	n := graph.networks[e.network].rect
+	c := graph.containers[e.container].rect
+	from = f32.Pt((n.Min.X+n.Max.X)/2, n.Max.Y).Mul(ppdp)
+	to = f32.Pt((c.Min.X+c.Max.X)/2, c.Min.Y).Mul(ppdp)
+	return from, to
+}
+
+// edgePoint returns the point at t along the curve of an edge.
+func edgePoint(from, to f32.Point, t float32) f32.Point {
+	midY := (from.Y + to.Y) / 2
+	c1 := f32.Pt(from.X, midY)
+	c2 := f32.Pt(to.X, midY)
+	u := 1 - t
+	return from.Mul(u * u * u).Add(c1.Mul(3 * u * u * t)).Add(c2.Mul(3 * u * t * t)).Add(to.Mul(t * t * t))
+}
+
+func (v *TopologyView) drawEdge(gtx layout.Context, graph *topologyLayout, e topoEdge, highlight bool) {
+	from, to := edgeEnds(gtx, graph, e)
+	midY := (from.Y + to.Y) / 2
+
+	var p clip.Path
+	p.Begin(gtx.Ops)
+	p.MoveTo(from)
+	p.CubeTo(f32.Pt(from.X, midY), f32.Pt(to.X, midY), to)
+
+	c := v.theme.Colors.Border
+	width := unit.Dp(1.5)
+	if highlight {
+		c = v.theme.Colors.Primary
+		width = unit.Dp(2.5)
+	}
+	paint.FillShape(gtx.Ops, c, clip.Stroke{Path: p.End(), Width: float32(gtx.Dp(width))}.Op())
+}
+
+// drawEdgeLabel draws the aliases of a container near its end of the edge.
+func (v *TopologyView) drawEdgeLabel(gtx layout.Context, graph *topologyLayout, e topoEdge) {
+	from, to := edgeEnds(gtx, graph, e)
+	at := edgePoint(from, to, 0.8)
+
+	label := material.Caption(v.theme.Material, strings.Join(e.aliases, ", "))
+	label.Color = v.theme.Colors.TextSecondary
+	label.MaxLines = 1
+
+	// Measure the text to center it on a background that hides the edge
+	macro := op.Record(gtx.Ops)
+	lgtx := gtx
+	lgtx.Constraints = layout.Constraints{Max: image.Pt(gtx.Dp(unit.Dp(topoNodeWidth)), gtx.Dp(unit.Dp(20)))}
+	dims := label.Layout(lgtx)
+	call := macro.Stop()
+
+	pad := gtx.Dp(unit.Dp(3))
+	origin := image.Pt(int(at.X)-dims.Size.X/2, int(at.Y)-dims.Size.Y/2)
+	bg := image.Rectangle{Min: origin, Max: origin.Add(dims.Size)}.Inset(-pad)
+	paint.FillShape(gtx.Ops, v.theme.Colors.Background, clip.UniformRRect(bg, pad).Op(gtx.Ops))
+
+	defer op.Offset(origin).Push(gtx.Ops).Pop()
+	call.Add(gtx.Ops)
+}
+
+func (v *TopologyView) drawNetwork(gtx layout.Context, n topoNetwork, hovered bool) {
+	r := pxRect(gtx, n.rect)
+	border := v.theme.Colors.Primary
+	width := unit.Dp(1)
+	if hovered {
+		width = unit.Dp(2)
+	}
+	drawBox(gtx, r, r.Dy()/2, v.theme.Colors.SelectedBg, border, width)
+
+	pad := r.Dy() / 2
+	name := material.Body2(v.theme.Material, n.network.Name)
+	name.Color = v.theme.Colors.Text
+	name.Alignment = text.Middle
+	drawLabel(gtx, name, image.Rect(r.Min.X+pad, r.Min.Y+gtx.Dp(unit.Dp(2)), r.Max.X-pad, r.Max.Y))
+
+	info := n.network.Driver
+	if len(n.network.Subnets) > 0 {
+		info = n.network.Subnets[0].Subnet
+	}
+	if n.network.Internal {
+		info += " • internal"
+	}
+	caption := material.Caption(v.theme.Material, info)
+	caption.Color = v.theme.Colors.TextSecondary
+	caption.Alignment = text.Middle
+	drawLabel(gtx, caption, image.Rect(r.Min.X+pad, r.Min.Y+r.Dy()/2, r.Max.X-pad, r.Max.Y))
+}
+
+// containerStateColor returns the color that represents a container state.
+func containerStateColor(theme *Theme, state string) color.NRGBA {
+	switch state {
+	case "running":
+		return theme.Colors.StatusRunning
+	case "paused", "restarting":
+		return theme.Colors.StatusPaused
+	case "exited", "dead":
+		return theme.Colors.StatusStopped
+	default:
+		return theme.Colors.StatusCreated
+	}
+}
+
+func (v *TopologyView) drawContainer(gtx layout.Context, c topoContainer, hovered bool) {
+	r := pxRect(gtx, c.rect)
+	state := containerStateColor(v.theme, c.container.State)
+	width := unit.Dp(1.5)
+	if hovered {
+		width = unit.Dp(2.5)
+	}
+	drawBox(gtx, r, gtx.Dp(unit.Dp(6)), v.theme.Colors.CardBg, state, width)
+
+	pad := gtx.Dp(unit.Dp(10))
+	name := material.Body2(v.theme.Material, c.container.Name)
+	name.Color = v.theme.Colors.Text
+	drawLabel(gtx, name, image.Rect(r.Min.X+pad, r.Min.Y+gtx.Dp(unit.Dp(4)), r.Max.X-pad, r.Max.Y))
+
+	// Published ports, or the status for containers without any
+	var ports []string
+	for _, p := range c.container.Ports {
+		if p.Published() {
+			ports = append(ports, p.String())
+		}
+	}
+	info := strings.Join(ports, ", ")
+	if info == "" {
+		info = c.container.Status
+	}
+	caption := material.Caption(v.theme.Material, info)
+	caption.Color = v.theme.Colors.TextSecondary
+	drawLabel(gtx, caption, image.Rect(r.Min.X+pad, r.Min.Y+r.Dy()/2+gtx.Dp(unit.Dp(1)), r.Max.X-pad, r.Max.Y))
+}
@@ -0,0 +1,264 @@
+package ui
+
+import (
+	"math"
+	"sort"
+
+	"gioui.org/f32"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// Sizes of the topology graph, in dp
+const (
+	topoNodeWidth       = 180
+	topoContainerHeight = 46
+	topoNetworkHeight   = 38
+	topoNodeGap         = 16
+	topoGroupGap        = 40
+	topoGroupPadding    = 12
+	topoGroupLabel      = 22
+	topoLayerGap        = 170 // Between the network row and the container row
+	topoOrderPasses     = 4   // Crossing reduction passes
+)
+
+// topoRect is an axis-aligned rectangle in graph coordinates (dp).
+type topoRect struct {
+	Min, Max f32.Point
+}
+
+func (r topoRect) contains(p f32.Point) bool {
+	return p.X >= r.Min.X && p.X < r.Max.X && p.Y >= r.Min.Y && p.Y < r.Max.Y
+}
+
+func (r topoRect) union(o topoRect) topoRect {
+	return topoRect{
+		Min: f32.Pt(min(r.Min.X, o.Min.X), min(r.Min.Y, o.Min.Y)),
+		Max: f32.Pt(max(r.Max.X, o.Max.X), max(r.Max.Y, o.Max.Y)),
+	}
+}
+
+// topoContainer is a container node.
+type topoContainer struct {
+	container docker.Container
+	rect      topoRect
+}
+
+// topoNetwork is a network node.
+type topoNetwork struct {
+	network docker.Network
+	rect    topoRect
+}
+
+// topoGroup is the box around the containers of a compose project.
+type topoGroup struct {
+	name string // Empty for standalone containers
+	rect topoRect
+}
+
+// topoEdge connects a container to a network it is on.
+type topoEdge struct {
+	network   int // Index into topologyLayout.networks
+	container int // Index into topologyLayout.containers
+	aliases   []string
+}
+
+// topologyLayout is a layered drawing of the networks (top row) and the
+// containers grouped by compose project (bottom row).
+type topologyLayout struct {
+	containers []topoContainer
+	networks   []topoNetwork
+	groups     []topoGroup
+	edges      []topoEdge
+	bounds     topoRect
+}
+
+// layoutTopology positions the nodes of the graph. Containers and networks
+// are ordered by the barycenter of their neighbours to keep edges short and
+// reduce crossings; containers stay within their project group.
+func layoutTopology(groups []docker.ContainerGroup, networks []docker.Network, aliases map[string]map[string][]string) topologyLayout {
+	var l topologyLayout
+
+	// Networks nothing can be connected to only add noise
+	netIndex := make(map[string]int)
+	for _, net := range networks {
+		if net.Predefined() && !net.InUse() {
+			continue
+		}
+		netIndex[net.ID] = len(l.networks)
+		l.networks = append(l.networks, topoNetwork{network: net})
+	}
+
+	// Container indices per group, in display order
+	members := make([][]int, 0, len(groups))
+	for _, g := range groups {
+		if len(g.Containers) == 0 {
+			continue
+		}
+		var idx []int
+		for _, ctr := range g.Containers {
+			i := len(l.containers)
+			l.containers = append(l.containers, topoContainer{container: ctr})
+			idx = append(idx, i)
+			for _, n := range ctr.Networks {
+				if ni, ok := netIndex[n.NetworkID]; ok {
+					l.edges = append(l.edges, topoEdge{network: ni, container: i, aliases: aliases[ctr.ID][n.NetworkID]})
+				}
+			}
+		}
+		l.groups = append(l.groups, topoGroup{name: g.Name})
+		members = append(members, idx)
+	}
+
+	ctrNets := make([][]int, len(l.containers))
+	netCtrs := make([][]int, len(l.networks))
+	for _, e := range l.edges {
+		ctrNets[e.container] = append(ctrNets[e.container], e.network)
+		netCtrs[e.network] = append(netCtrs[e.network], e.container)
+	}
+
+	// Start with networks in name order, as listed
+	netOrder := make([]int, len(l.networks))
+	for i := range netOrder {
+		netOrder[i] = i
+	}
+	netX := make([]float32, len(l.networks))
+	ctrX := make([]float32, len(l.containers))
+	groupOrder := make([]int, len(l.groups))
+	for i := range groupOrder {
+		groupOrder[i] = i
+	}
+
+	for pass := 0; pass < topoOrderPasses; pass++ {
+		for rank, ni := range netOrder {
+			netX[ni] = float32(rank)
+		}
+
+		// Order containers within their group, then the groups themselves
+		groupBary := make([]float32, len(l.groups))
+		for gi, idx := range members {
+			sort.SliceStable(idx, func(a, b int) bool {
+				return barycenter(ctrNets[idx[a]], netX) < barycenter(ctrNets[idx[b]], netX)
+			})
+			var sum float32
+			var n int
+			for _, ci := range idx {
+				if b := barycenter(ctrNets[ci], netX); !math.IsInf(float64(b), 1) {
+					sum += b
+					n++
+				}
+			}
+			groupBary[gi] = float32(math.Inf(1))
+			if n > 0 {
+				groupBary[gi] = sum / float32(n)
+			}
+		}
+		sort.SliceStable(groupOrder, func(a, b int) bool {
+			ga, gb := groupOrder[a], groupOrder[b]
+			// Standalone containers stay at the end
+			if l.groups[ga].name == "" || l.groups[gb].name == "" {
+				return l.groups[gb].name == "" && l.groups[ga].name != ""
+			}
+			return groupBary[ga] < groupBary[gb]
+		})
+
+		// Place the containers to get the barycenters of the networks
+		x := float32(0)
+		for _, gi := range groupOrder {
+			x += topoGroupPadding
+			for _, ci := range members[gi] {
+				ctrX[ci] = x + topoNodeWidth/2
+				x += topoNodeWidth + topoNodeGap
+			}
+			x += topoGroupPadding - topoNodeGap + topoGroupGap
+		}
+		sort.SliceStable(netOrder, func(a, b int) bool {
+			return barycenter(netCtrs[netOrder[a]], ctrX) < barycenter(netCtrs[netOrder[b]], ctrX)
+		})
+	}
+
+	// Container row with the group boxes around it
+	x := float32(0)
+	top := float32(topoNetworkHeight + topoLayerGap)
+	for _, gi := range groupOrder {
+		start := x
+		x += topoGroupPadding
+		for _, ci := range members[gi] {
+			l.containers[ci].rect = topoRect{
+				Min: f32.Pt(x, top+topoGroupLabel),
+				Max: f32.Pt(x+topoNodeWidth, top+topoGroupLabel+topoContainerHeight),
+			}
+			x += topoNodeWidth + topoNodeGap
+		}
+		x += topoGroupPadding - topoNodeGap
+		l.groups[gi].rect = topoRect{
+			Min: f32.Pt(start, top),
+			Max: f32.Pt(x, top+topoGroupLabel+topoContainerHeight+topoGroupPadding),
+		}
+		x += topoGroupGap
+	}
+
+	// Network row: above the middle of their containers, pushed apart where they would overlap
+	next := float32(math.Inf(-1))
+	var last float32
+	for _, ni := range netOrder {
+		want := barycenter(netCtrs[ni], ctrX)
+		if math.IsInf(float64(want), 1) {
+			// Unused networks go after the others
+			want = last + topoNodeWidth
+		}
+		left := max(want-topoNodeWidth/2, next)
+		l.networks[ni].rect = topoRect{
+			Min: f32.Pt(left, 0),
+			Max: f32.Pt(left+topoNodeWidth, topoNetworkHeight),
+		}
+		next = left + topoNodeWidth + topoNodeGap
+		last = left + topoNodeWidth/2
+	}
+
+	for i, g := range l.groups {
+		if i == 0 {
+			l.bounds = g.rect
+		}
+		l.bounds = l.bounds.union(g.rect)
+	}
+	for i, n := range l.networks {
+		if i == 0 && len(l.groups) == 0 {
+			l.bounds = n.rect
+		}
+		l.bounds = l.bounds.union(n.rect)
+	}
+	return l
+}
+
+// barycenter returns the mean position of the neighbours, or +Inf without neighbours.
+func barycenter(neighbours []int, pos []float32) float32 {
+	if len(neighbours) == 0 {
+		return float32(math.Inf(1))
+	}
+	var sum float32
+	for _, n := range neighbours {
+		sum += pos[n]
+	}
+	return sum / float32(len(neighbours))
+}
+
+// hitContainer returns the index of the container node at p, or -1.
+func (l *topologyLayout) hitContainer(p f32.Point) int {
+	for i, c := range l.containers {
+		if c.rect.contains(p) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hitNetwork returns the index of the network node at p, or -1.
+func (l *topologyLayout) hitNetwork(p f32.Point) int {
+	for i, n := range l.networks {
+		if n.rect.contains(p) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,593 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"gioui.org/app"
+	"gioui.org/layout"
+	"gioui.org/op"
+	"gioui.org/op/clip"
+	"gioui.org/op/paint"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// maxPreviewBytes limits how much of a file the volume browser previews.
+const maxPreviewBytes = 256 << 10
+
+// VolumeWindow represents a window browsing the files of a volume.
+type VolumeWindow struct {
+	window      *app.Window
+	theme       *Theme
+	docker      *docker.Client
+	volume      docker.Volume
+	helperImage string
+
+	// Updated from background goroutines
+	mu          sync.RWMutex
+	browser     *docker.VolumeBrowser
+	opened      bool
+	openErr     string
+	dir         string // Directory shown, e.g. "/" or "/data"
+	entries     []docker.VolumeEntry
+	listing     bool
+	listErr     string
+	selected    string // Path of the previewed file, empty if none
+	preview     []string
+	truncated   bool
+	binary      bool
+	loadingFile bool
+	previewErr  string
+	downloading bool
+	downloadMsg string
+	downloadErr bool
+
+	up           widget.Clickable
+	reload       widget.Clickable
+	download     widget.Clickable
+	entryButtons map[string]*widget.Clickable
+	entryList    widget.List
+	previewList  widget.List
+	shownDir     string // Directory the entry list is scrolled in
+
+	closed bool
+}
+
+// NewVolumeWindow creates and runs a new window browsing a volume.
+// helperImage is the image of the helper container, empty for the default.
+func NewVolumeWindow(theme *Theme, dockerClient *docker.Client, vol docker.Volume, helperImage string) {
+	vw := &VolumeWindow{
+		theme:        theme,
+		docker:       dockerClient,
+		volume:       vol,
+		helperImage:  helperImage,
+		dir:          "/",
+		entryButtons: make(map[string]*widget.Clickable),
+		entryList:    widget.List{List: layout.List{Axis: layout.Vertical}},
+		previewList:  widget.List{List: layout.List{Axis: layout.Vertical}},
+	}
+
+	go vw.run()
+}
+
+func (vw *VolumeWindow) run() {
+	vw.window = new(app.Window)
+	vw.window.Option(
+		app.Title("Volume: "+vw.volume.Name),
+		app.Size(unit.Dp(900), unit.Dp(650)),
+		app.MinSize(unit.Dp(500), unit.Dp(400)),
+	)
+
+	go vw.open()
+
+	// Run the event loop
+	var ops op.Ops
+	for {
+		switch e := vw.window.Event().(type) {
+		case app.DestroyEvent:
+			vw.closed = true
+			vw.closeBrowser()
+			return
+		case app.FrameEvent:
+			gtx := app.NewContext(&ops, e)
+			vw.layout(gtx)
+			e.Frame(gtx.Ops)
+		}
+	}
+}
+
+// open prepares the browser and lists the root directory.
+func (vw *VolumeWindow) open() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	browser, err := vw.docker.BrowseVolume(ctx, vw.volume, vw.helperImage)
+
+	vw.mu.Lock()
+	vw.opened = true
+	if err != nil {
+		vw.openErr = err.Error()
+	} else if vw.closed {
+		// The window was closed while the helper was created
+		vw.mu.Unlock()
+		_ = browser.Close(context.Background())
+		return
+	} else {
+		vw.browser = browser
+	}
+	vw.mu.Unlock()
+	vw.invalidate()
+
+	if err == nil {
+		vw.listDir("/")
+	}
+}
+
+// closeBrowser removes the helper container in the background.
+func (vw *VolumeWindow) closeBrowser() {
+	vw.mu.Lock()
+	browser := vw.browser
+	vw.browser = nil
+	vw.mu.Unlock()
+	if browser == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = browser.Close(ctx)
+	}()
+}
+
+func (vw *VolumeWindow) invalidate() {
+	if vw.window != nil && !vw.closed {
+		vw.window.Invalidate()
+	}
+}
+
+// listDir lists a directory in the background and shows it once loaded.
+func (vw *VolumeWindow) listDir(dir string) {
+	vw.mu.Lock()
+	browser := vw.browser
+	vw.listing = true
+	vw.listErr = ""
+	vw.mu.Unlock()
+	if browser == nil {
+		return
+	}
+
+	go func() {
+		defer vw.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		entries, err := browser.List(ctx, dir)
+
+		vw.mu.Lock()
+		defer vw.mu.Unlock()
+		vw.listing = false
+		if err != nil {
+			vw.listErr = err.Error()
+			return
+		}
+		if vw.dir != dir {
+			vw.selected = ""
+			vw.preview = nil
+			vw.previewErr = ""
+		}
+		vw.dir = dir
+		vw.entries = entries
+	}()
+}
+
+// previewFile loads the beginning of a file in the background.
+func (vw *VolumeWindow) previewFile(entry docker.VolumeEntry) {
+	vw.mu.Lock()
+	browser := vw.browser
+	vw.selected = entry.Path
+	vw.preview = nil
+	vw.previewErr = ""
+	vw.loadingFile = true
+	vw.mu.Unlock()
+	vw.previewList.Position = layout.Position{}
+
+	go func() {
+		defer vw.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		data, truncated, err := browser.ReadFile(ctx, entry.Path, maxPreviewBytes)
+
+		vw.mu.Lock()
+		defer vw.mu.Unlock()
+		if vw.selected != entry.Path {
+			return
+		}
+		vw.loadingFile = false
+		if err != nil {
+			vw.previewErr = err.Error()
+			return
+		}
+		vw.truncated = truncated
+		vw.binary = isBinary(data, truncated)
+		if !vw.binary {
+			vw.preview = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+		}
+	}()
+}
+
+// isBinary reports whether data looks like binary rather than text.
+// A character cut off at the end of a truncated preview is ignored.
+func isBinary(data []byte, truncated bool) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+	if truncated {
+		for i := 0; i < utf8.UTFMax-1 && len(data) > 0 && !utf8.Valid(data); i++ {
+			data = data[:len(data)-1]
+		}
+	}
+	return !utf8.Valid(data)
+}
+
+// startDownload saves a file, or a tar archive of a directory, to the downloads directory.
+func (vw *VolumeWindow) startDownload(entry docker.VolumeEntry) {
+	name := entry.Name
+	if entry.Path == "/" {
+		name = vw.volume.Name
+	}
+	if entry.IsDir() {
+		name += ".tar"
+	}
+	dest := uniqueDownloadPath(name)
+
+	vw.mu.Lock()
+	browser := vw.browser
+	vw.downloading = true
+	vw.downloadMsg = "Downloading " + entry.Path + "…"
+	vw.downloadErr = false
+	vw.mu.Unlock()
+
+	go func() {
+		defer vw.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+		defer cancel()
+		err := downloadEntry(ctx, browser, entry, dest)
+
+		vw.mu.Lock()
+		defer vw.mu.Unlock()
+		vw.downloading = false
+		if err != nil {
+			vw.downloadMsg = "Failed to download " + entry.Path + ": " + err.Error()
+			vw.downloadErr = true
+			return
+		}
+		vw.downloadMsg = "Saved to " + dest
+	}()
+}
+
+// downloadEntry writes an entry to dest, removing the partial file on failure.
+func downloadEntry(ctx context.Context, browser *docker.VolumeBrowser, entry docker.VolumeEntry, dest string) error {
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if err := browser.Download(ctx, entry, f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(dest)
+		return err
+	}
+	return f.Close()
+}
+
+// uniqueDownloadPath returns a path for name in the downloads directory,
+// adding a timestamp if a file of that name exists already.
+func uniqueDownloadPath(name string) string {
+	p := filepath.Join(downloadsDir(), name)
+	if _, err := os.Stat(p); err != nil {
+		return p
+	}
+	ext := filepath.Ext(name)
+	return filepath.Join(downloadsDir(), strings.TrimSuffix(name, ext)+"-"+time.Now().Format("20060102-150405")+ext)
+}
+
+// entryButton returns the clickable of a directory entry.
+func (vw *VolumeWindow) entryButton(p string) *widget.Clickable {
+	if c, ok := vw.entryButtons[p]; ok {
+		return c
+	}
+	c := new(widget.Clickable)
+	vw.entryButtons[p] = c
+	return c
+}
+
+func (vw *VolumeWindow) layout(gtx layout.Context) layout.Dimensions {
+	// Fill background
+	paint.FillShape(gtx.Ops, vw.theme.Colors.Background, clip.Rect{Max: gtx.Constraints.Max}.Op())
+
+	vw.mu.RLock()
+	opened := vw.opened
+	openErr := vw.openErr
+	browser := vw.browser
+	dir := vw.dir
+	entries := vw.entries
+	listing := vw.listing
+	listErr := vw.listErr
+	selected := vw.selected
+	downloading := vw.downloading
+	downloadMsg := vw.downloadMsg
+	downloadErr := vw.downloadErr
+	vw.mu.RUnlock()
+
+	if dir != vw.shownDir {
+		vw.shownDir = dir
+		vw.entryList.Position = layout.Position{}
+	}
+
+	if browser != nil {
+		if vw.up.Clicked(gtx) && dir != "/" && !listing {
+			vw.listDir(path.Dir(dir))
+			listing = true
+		}
+		if vw.reload.Clicked(gtx) && !listing {
+			vw.listDir(dir)
+			listing = true
+		}
+		for _, entry := range entries {
+			if !vw.entryButton(entry.Path).Clicked(gtx) {
+				continue
+			}
+			switch {
+			case entry.IsDir() && !listing:
+				vw.listDir(entry.Path)
+				listing = true
+			case entry.Mode.IsRegular():
+				vw.previewFile(entry)
+				selected = entry.Path
+			}
+		}
+		if vw.download.Clicked(gtx) && !downloading {
+			vw.startDownload(vw.downloadTarget(dir, entries, selected))
+			downloading = true
+		}
+	}
+
+	return layout.Inset{
+		Top:    unit.Dp(12),
+		Bottom: unit.Dp(8),
+		Left:   unit.Dp(16),
+		Right:  unit.Dp(16),
+	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		switch {
+		case !opened:
+			return vw.layoutMessage(gtx, "Preparing to read the volume…")
+		case openErr != "":
+			return vw.layoutMessage(gtx, "Failed to open volume: "+openErr)
+		case browser == nil:
+			return layout.Dimensions{}
+		}
+
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Summary
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return vw.layoutSummary(gtx, browser)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			// Path and actions
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				downloadLabel := "Download Folder"
+				if selected != "" {
+					downloadLabel = "Download File"
+				}
+				return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layoutActionButton(gtx, vw.theme, &vw.up, "Up", false, false)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+					layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+						label := material.Body2(vw.theme.Material, dir)
+						label.Color = vw.theme.Colors.Text
+						label.Font.Typeface = "monospace"
+						label.MaxLines = 1
+						return label.Layout(gtx)
+					}),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layoutActionButton(gtx, vw.theme, &vw.reload, "Reload", false, listing)
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						return layoutActionButton(gtx, vw.theme, &vw.download, downloadLabel, false, downloading)
+					}),
+				)
+			}),
+			// Download result
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if downloadMsg == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Caption(vw.theme.Material, downloadMsg)
+					label.Color = vw.theme.Colors.TextSecondary
+					if downloadErr {
+						label.Color = vw.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			// Entries and preview
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+					layout.Flexed(0.45, func(gtx layout.Context) layout.Dimensions {
+						switch {
+						case listErr != "":
+							return vw.layoutMessage(gtx, "Failed to list "+dir+": "+listErr)
+						case len(entries) == 0 && listing:
+							return vw.layoutMessage(gtx, "Listing files…")
+						case len(entries) == 0:
+							return vw.layoutMessage(gtx, "This folder is empty")
+						}
+						return material.List(vw.theme.Material, &vw.entryList).Layout(gtx, len(entries), func(gtx layout.Context, index int) layout.Dimensions {
+							return vw.layoutEntry(gtx, entries[index], entries[index].Path == selected)
+						})
+					}),
+					layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+					layout.Flexed(0.55, vw.layoutPreview),
+				)
+			}),
+		)
+	})
+}
+
+// downloadTarget returns the selected file, or the shown directory if no file is selected.
+func (vw *VolumeWindow) downloadTarget(dir string, entries []docker.VolumeEntry, selected string) docker.VolumeEntry {
+	for _, entry := range entries {
+		if entry.Path == selected {
+			return entry
+		}
+	}
+	return docker.VolumeEntry{Name: path.Base(dir), Path: dir, Mode: os.ModeDir}
+}
+
+func (vw *VolumeWindow) layoutMessage(gtx layout.Context, msg string) layout.Dimensions {
+	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		label := material.Body1(vw.theme.Material, msg)
+		label.Color = vw.theme.Colors.TextMuted
+		return label.Layout(gtx)
+	})
+}
+
+func (vw *VolumeWindow) layoutSummary(gtx layout.Context, browser *docker.VolumeBrowser) layout.Dimensions {
+	info := "Driver: " + vw.volume.Driver + " • read-only via " + browser.Source()
+	if vw.volume.Size >= 0 {
+		info = docker.FormatSize(vw.volume.Size) + " • " + info
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			label := material.H6(vw.theme.Material, vw.volume.Name)
+			label.Color = vw.theme.Colors.Text
+			return label.Layout(gtx)
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			label := material.Caption(vw.theme.Material, info)
+			label.Color = vw.theme.Colors.TextMuted
+			return label.Layout(gtx)
+		}),
+	)
+}
+
+func (vw *VolumeWindow) layoutEntry(gtx layout.Context, entry docker.VolumeEntry, selected bool) layout.Dimensions {
+	clickable := vw.entryButton(entry.Path)
+
+	name := entry.Name
+	nameColor := vw.theme.Colors.Text
+	switch {
+	case entry.IsDir():
+		name += "/"
+	case entry.IsLink():
+		name += " → " + entry.LinkTarget
+		nameColor = vw.theme.Colors.TextSecondary
+	case !entry.Mode.IsRegular():
+		nameColor = vw.theme.Colors.TextMuted
+	}
+	var size string
+	if entry.Size >= 0 && !entry.IsLink() {
+		size = docker.FormatSize(entry.Size)
+	}
+
+	return clickable.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Stack{}.Layout(gtx,
+			layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+				switch {
+				case selected:
+					return fillRounded(gtx, vw.theme.Colors.SelectedBg, 4)
+				case clickable.Hovered():
+					return fillRounded(gtx, vw.theme.Colors.ButtonHover, 4)
+				}
+				return layout.Dimensions{Size: gtx.Constraints.Min}
+			}),
+			layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.X = gtx.Constraints.Max.X
+				return layout.Inset{Top: unit.Dp(3), Bottom: unit.Dp(3), Left: unit.Dp(6), Right: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+							label := material.Caption(vw.theme.Material, name)
+							label.Color = nameColor
+							label.Font.Typeface = "monospace"
+							label.MaxLines = 1
+							return label.Layout(gtx)
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							label := material.Caption(vw.theme.Material, size)
+							label.Color = vw.theme.Colors.TextMuted
+							return label.Layout(gtx)
+						}),
+						layout.Rigid(layout.Spacer{Width: unit.Dp(12)}.Layout),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							gtx.Constraints.Min.X = gtx.Dp(unit.Dp(72))
+							label := material.Caption(vw.theme.Material, formatAge(entry.ModTime))
+							label.Color = vw.theme.Colors.TextMuted
+							return label.Layout(gtx)
+						}),
+					)
+				})
+			}),
+		)
+	})
+}
+
+func (vw *VolumeWindow) layoutPreview(gtx layout.Context) layout.Dimensions {
+	vw.mu.RLock()
+	selected := vw.selected
+	preview := vw.preview
+	truncated := vw.truncated
+	binary := vw.binary
+	loadingFile := vw.loadingFile
+	previewErr := vw.previewErr
+	vw.mu.RUnlock()
+
+	switch {
+	case selected == "":
+		return vw.layoutMessage(gtx, "Select a file to preview it")
+	case loadingFile:
+		return vw.layoutMessage(gtx, "Reading "+path.Base(selected)+"…")
+	case previewErr != "":
+		return vw.layoutMessage(gtx, "Failed to read file: "+previewErr)
+	case binary:
+		return vw.layoutMessage(gtx, "Binary file, download it to open it")
+	}
+
+	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+			return material.List(vw.theme.Material, &vw.previewList).Layout(gtx, len(preview), func(gtx layout.Context, index int) layout.Dimensions {
+				label := material.Caption(vw.theme.Material, preview[index])
+				label.Color = vw.theme.Colors.Text
+				label.Font.Typeface = "monospace"
+				return label.Layout(gtx)
+			})
+		}),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			if !truncated {
+				return layout.Dimensions{}
+			}
+			return layout.Inset{Top: unit.Dp(6)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				label := material.Caption(vw.theme.Material, "Showing the first "+docker.FormatSize(maxPreviewBytes)+", download the file to see all of it")
+				label.Color = vw.theme.Colors.TextMuted
+				return label.Layout(gtx)
+			})
+		}),
+	)
+}
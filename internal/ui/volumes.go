@@ -1,51 +1,173 @@
 package ui
 
 import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
 	"gioui.org/layout"
 	"gioui.org/unit"
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 
+	"github.com/tsukinoko-kun/harbor/internal/config"
 	"github.com/tsukinoko-kun/harbor/internal/docker"
 )
 
+// volumeRowButtons holds the button states for a volume row.
+type volumeRowButtons struct {
+	usedBy     widget.Clickable
+	browse     widget.Clickable
+	backup     widget.Clickable
+	restore    widget.Clickable
+	clone      widget.Clickable
+	rename     widget.Clickable
+	remove     widget.Clickable
+	processing bool // true when an action is in progress
+}
+
 // VolumesView displays the list of Docker volumes.
 type VolumesView struct {
-	theme *Theme
-	list  widget.List
+	theme          *Theme
+	docker         *docker.Client
+	settings       *config.Settings
+	invalidate     func()             // Requests a redraw from background goroutines
+	showContainers func(ids []string) // Switches to the containers view
+	list           widget.List
+	volumeButtons  map[string]*volumeRowButtons
+	filter         widget.Enum
+	sort           widget.Enum
+
+	// Disk usage from /system/df, loaded in the background
+	usageMu      sync.Mutex
+	usage        map[string]docker.VolumeUsage
+	usageAt      time.Time
+	usageLoading bool
+
+	createButton  widget.Clickable
+	createDialog  volumeCreateDialog
+	pruneButton   widget.Clickable
+	pruning       bool
+	removeDialog  volumeRemoveDialog
+	backupDialog  volumeBackupDialog
+	cloneDialog   volumeCloneDialog
+	restoreButton widget.Clickable
+
+	// Result of the last action, with the containers blocking a removal
+	banner actionBanner
 }
 
 // NewVolumesView creates a new volumes view.
-func NewVolumesView(theme *Theme) *VolumesView {
+func NewVolumesView(theme *Theme, dockerClient *docker.Client, settings *config.Settings, invalidate func(), showContainers func(ids []string)) *VolumesView {
 	return &VolumesView{
-		theme: theme,
+		theme:          theme,
+		docker:         dockerClient,
+		settings:       settings,
+		invalidate:     invalidate,
+		showContainers: showContainers,
 		list: widget.List{
 			List: layout.List{Axis: layout.Vertical},
 		},
+		volumeButtons: make(map[string]*volumeRowButtons),
+		filter:        widget.Enum{Value: usageFilterAll},
+		sort:          widget.Enum{Value: volumeSortName},
+	}
+}
+
+// getVolumeButtons returns or creates button state for a volume.
+func (v *VolumesView) getVolumeButtons(name string) *volumeRowButtons {
+	if btns, ok := v.volumeButtons[name]; ok {
+		return btns
 	}
+	btns := &volumeRowButtons{}
+	v.volumeButtons[name] = btns
+	return btns
+}
+
+// setVolumeError shows an error. If containers use the volume, they are listed
+// and can be shown in the containers view.
+func (v *VolumesView) setVolumeError(prefix string, err error) {
+	var containers []docker.Container
+	var inUse *docker.VolumeInUseError
+	if errors.As(err, &inUse) {
+		containers = inUse.Containers
+	}
+	v.banner.set(prefix+": "+err.Error(), true, containers)
+}
+
+// setNotice shows a success message. Sizes are reloaded since the action
+// changed what is stored.
+func (v *VolumesView) setNotice(msg string) {
+	v.banner.set(msg, false, nil)
+	v.invalidateUsage()
 }
 
 // Layout renders the volumes view.
 func (v *VolumesView) Layout(gtx layout.Context, volumes []docker.Volume) layout.Dimensions {
-	if len(volumes) == 0 {
-		return v.layoutEmpty(gtx)
+	if v.createButton.Clicked(gtx) {
+		v.openCreateDialog()
+	}
+	if v.pruneButton.Clicked(gtx) && !v.pruning {
+		v.openPruneDialog()
+	}
+	if v.restoreButton.Clicked(gtx) {
+		v.openRestoreDialog("")
+	}
+	if ids := v.banner.update(gtx); ids != nil {
+		v.showContainers(ids)
 	}
 
-	return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
-		// Header
-		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-			return v.layoutHeader(gtx, len(volumes))
+	v.refreshUsage()
+	shown := v.shownVolumes(volumes)
+
+	return layout.Stack{}.Layout(gtx,
+		// Main content
+		layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				// Result of the last action (if any)
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return v.banner.layout(gtx, v.theme)
+				}),
+				// Header
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return v.layoutHeader(gtx, len(volumes))
+				}),
+				// Usage filter and sort order
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return v.layoutFilter(gtx, volumes)
+				}),
+				// Volume list
+				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+					if len(shown) == 0 {
+						return v.layoutEmpty(gtx, len(volumes) > 0)
+					}
+					return layout.Inset{
+						Left:  unit.Dp(16),
+						Right: unit.Dp(16),
+					}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return v.list.Layout(gtx, len(shown), func(gtx layout.Context, index int) layout.Dimensions {
+							return v.layoutVolume(gtx, shown[index])
+						})
+					})
+				}),
+			)
+		}),
+		// Create dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutCreateDialog(gtx)
+		}),
+		// Clone and rename dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutCloneDialog(gtx)
 		}),
-		// Volume list
-		layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
-			return layout.Inset{
-				Left:  unit.Dp(16),
-				Right: unit.Dp(16),
-			}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-				return v.list.Layout(gtx, len(volumes), func(gtx layout.Context, index int) layout.Dimensions {
-					return v.layoutVolume(gtx, volumes[index])
-				})
-			})
+		// Backup and restore dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutBackupDialog(gtx, volumes)
+		}),
+		// Confirmation dialog overlay
+		layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+			return v.layoutRemoveDialog(gtx)
 		}),
 	)
 }
@@ -69,49 +191,149 @@ func (v *VolumesView) layoutHeader(gtx layout.Context, count int) layout.Dimensi
 				label.Color = v.theme.Colors.TextMuted
 				return label.Layout(gtx)
 			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Dimensions{}
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.pruneButton, "Prune", false, v.pruning)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.restoreButton, "Restore…", false, false)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &v.createButton, "Create Volume", false, false)
+			}),
 		)
 	})
 }
 
 func (v *VolumesView) layoutVolume(gtx layout.Context, vol docker.Volume) layout.Dimensions {
+	btns := v.getVolumeButtons(vol.Name)
+
+	if btns.usedBy.Clicked(gtx) && vol.InUse() {
+		ids := make([]string, len(vol.Containers))
+		for i, ctr := range vol.Containers {
+			ids[i] = ctr.ID
+		}
+		v.showContainers(ids)
+	}
+	if btns.browse.Clicked(gtx) {
+		NewVolumeWindow(v.theme, v.docker, vol, v.settings.Volumes.HelperImage)
+	}
+	if btns.backup.Clicked(gtx) {
+		v.openBackupDialog(vol)
+	}
+	if btns.restore.Clicked(gtx) {
+		v.openRestoreDialog(vol.Name)
+	}
+	if btns.clone.Clicked(gtx) {
+		v.openCloneDialog(vol, false)
+	}
+	if btns.rename.Clicked(gtx) {
+		v.openCloneDialog(vol, true)
+	}
+	if btns.remove.Clicked(gtx) && !btns.processing {
+		if vol.InUse() {
+			// Refuse right away instead of asking for a confirmation that cannot succeed
+			v.setVolumeError("Cannot remove "+vol.Name, &docker.VolumeInUseError{Err: errors.New("volume is in use"), Containers: vol.Containers})
+		} else {
+			v.openRemoveDialog(vol)
+		}
+	}
+
+	details := "Driver: " + vol.Driver
+	if usage := volumeUsageText(vol); usage != "" {
+		details += " • " + usage
+	}
+	switch {
+	case vol.Project() != "":
+		details += " • compose project " + vol.Project()
+	case vol.Anonymous():
+		details += " • anonymous"
+	}
+	// Compose projects of the containers, which may differ from the one that created the volume
+	var usedIn []string
+	for _, project := range vol.Projects() {
+		if project != vol.Project() {
+			usedIn = append(usedIn, project)
+		}
+	}
+	if len(usedIn) > 0 {
+		details += " • used in " + strings.Join(usedIn, ", ")
+	}
+
 	return layout.Inset{
 		Top:    unit.Dp(8),
 		Bottom: unit.Dp(8),
 	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
-			// Name
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				label := material.Body1(v.theme.Material, vol.Name)
-				label.Color = v.theme.Colors.Text
-				return label.Layout(gtx)
-			}),
-			// Driver and mountpoint
-			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			// Volume info
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+					// Name
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						label := material.Body1(v.theme.Material, vol.Name)
+						label.Color = v.theme.Colors.Text
+						return label.Layout(gtx)
+					}),
+					// Driver and origin
+					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+						label := material.Caption(v.theme.Material, details)
+						label.Color = v.theme.Colors.TextMuted
+						return label.Layout(gtx)
+					}),
+					// Mountpoint (truncated)
 					layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-						label := material.Caption(v.theme.Material, "Driver: "+vol.Driver)
+						mountpoint := vol.Mountpoint
+						if len(mountpoint) > 60 {
+							mountpoint = "..." + mountpoint[len(mountpoint)-57:]
+						}
+						label := material.Caption(v.theme.Material, mountpoint)
 						label.Color = v.theme.Colors.TextMuted
 						return label.Layout(gtx)
 					}),
 				)
 			}),
-			// Mountpoint (truncated)
+			// Containers using the volume
 			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-				mountpoint := vol.Mountpoint
-				if len(mountpoint) > 60 {
-					mountpoint = "..." + mountpoint[len(mountpoint)-57:]
-				}
-				label := material.Caption(v.theme.Material, mountpoint)
-				label.Color = v.theme.Colors.TextMuted
-				return label.Layout(gtx)
+				return layoutUsedBy(gtx, v.theme, &btns.usedBy, vol.Containers)
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &btns.browse, "Browse", false, false)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &btns.backup, "Backup…", false, false)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &btns.restore, "Restore…", false, false)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &btns.clone, "Clone…", false, false)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &btns.rename, "Rename…", false, false)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutActionButton(gtx, v.theme, &btns.remove, "Delete", true, btns.processing)
 			}),
 		)
 	})
 }
 
-func (v *VolumesView) layoutEmpty(gtx layout.Context) layout.Dimensions {
+func (v *VolumesView) layoutEmpty(gtx layout.Context, filtered bool) layout.Dimensions {
+	text := "No volumes found"
+	if filtered {
+		text = "No volumes match the filter"
+	}
 	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
-		label := material.Body1(v.theme.Material, "No volumes found")
+		label := material.Body1(v.theme.Material, text)
 		label.Color = v.theme.Colors.TextMuted
 		return label.Layout(gtx)
 	})
@@ -0,0 +1,359 @@
+package ui
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// volumeBackupDialog holds the state of the "Backup Volume" and "Restore Volume" dialogs.
+type volumeBackupDialog struct {
+	restoring bool // Which of the two dialogs is open
+	open      bool
+	volume    docker.Volume // Volume to back up
+	name      widget.Editor // Volume to restore into
+	path      widget.Editor
+	stop      widget.Bool
+	replace   widget.Bool
+	start     widget.Clickable
+	cancel    widget.Clickable
+
+	// Updated from background goroutines
+	mu        sync.Mutex
+	running   bool
+	transfer  context.CancelFunc
+	sent      int64
+	total     int64
+	status    string
+	statusErr bool
+}
+
+// openBackupDialog shows the backup dialog for a volume with a timestamped destination.
+func (v *VolumesView) openBackupDialog(vol docker.Volume) {
+	d := &v.backupDialog
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running {
+		d.open = true
+		return
+	}
+
+	d.open = true
+	d.restoring = false
+	d.volume = vol
+	d.status = ""
+	d.sent = 0
+	d.total = 0
+	d.stop.Value = false
+	d.path.SetText(filepath.Join(downloadsDir(), vol.Name+"-"+time.Now().Format("20060102-150405")+".tar.gz"))
+}
+
+// openRestoreDialog shows the restore dialog, keeping the last path. An empty
+// name restores into a new volume the user names.
+func (v *VolumesView) openRestoreDialog(name string) {
+	d := &v.backupDialog
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running {
+		d.open = true
+		return
+	}
+
+	d.open = true
+	d.restoring = true
+	d.status = ""
+	d.sent = 0
+	d.total = 0
+	d.stop.Value = false
+	d.replace.Value = name != ""
+	d.name.SetText(name)
+}
+
+// startBackup backs up a volume in the background.
+func (v *VolumesView) startBackup(opts docker.BackupVolumeOptions) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d := &v.backupDialog
+	d.mu.Lock()
+	d.running = true
+	d.transfer = cancel
+	d.sent = 0
+	d.total = 0
+	d.status = "Backing up " + opts.Volume + "…"
+	d.statusErr = false
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+		defer cancel()
+
+		err := v.docker.BackupVolume(ctx, opts, func(n int64) {
+			d.mu.Lock()
+			d.sent = n
+			d.mu.Unlock()
+			v.invalidate()
+		})
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.running = false
+		d.transfer = nil
+		switch {
+		case ctx.Err() != nil:
+			d.status = "Backup cancelled"
+			d.statusErr = true
+		case err != nil:
+			d.status = "Failed to back up " + opts.Volume + ": " + err.Error()
+			d.statusErr = true
+		default:
+			d.status = "Backed up " + opts.Volume + " (" + docker.FormatSize(d.sent) + " read) to " + opts.Path
+		}
+	}()
+}
+
+// startRestore restores a backup into a volume in the background.
+func (v *VolumesView) startRestore(opts docker.RestoreVolumeOptions) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	d := &v.backupDialog
+	d.mu.Lock()
+	d.running = true
+	d.transfer = cancel
+	d.sent = 0
+	d.total = 0
+	d.status = "Restoring into " + opts.Volume + "…"
+	d.statusErr = false
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+		defer cancel()
+
+		err := v.docker.RestoreVolume(ctx, opts, func(sent, total int64) {
+			d.mu.Lock()
+			d.sent = sent
+			d.total = total
+			d.mu.Unlock()
+			v.invalidate()
+		})
+		v.invalidateUsage()
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.running = false
+		d.transfer = nil
+		switch {
+		case ctx.Err() != nil:
+			d.status = "Restore cancelled, the volume may be incomplete"
+			d.statusErr = true
+		case err != nil:
+			d.status = "Failed to restore into " + opts.Volume + ": " + err.Error()
+			d.statusErr = true
+		default:
+			d.status = "Restored " + filepath.Base(opts.Path) + " into " + opts.Volume
+		}
+	}()
+}
+
+// layoutBackupDialog renders the backup or restore dialog overlay when it is open.
+func (v *VolumesView) layoutBackupDialog(gtx layout.Context, volumes []docker.Volume) layout.Dimensions {
+	d := &v.backupDialog
+	if !d.open {
+		return layout.Dimensions{}
+	}
+
+	d.mu.Lock()
+	running := d.running
+	transfer := d.transfer
+	sent := d.sent
+	total := d.total
+	status := d.status
+	statusErr := d.statusErr
+	d.mu.Unlock()
+
+	if d.cancel.Clicked(gtx) {
+		if running && transfer != nil {
+			transfer()
+		} else {
+			d.open = false
+			return layout.Dimensions{Size: gtx.Constraints.Max}
+		}
+	}
+
+	name := strings.TrimSpace(d.name.Text())
+	target, exists := findVolume(volumes, name)
+	if !d.restoring {
+		target, exists = d.volume, true
+	}
+
+	if d.start.Clicked(gtx) && !running {
+		path := strings.TrimSpace(d.path.Text())
+		problem := ""
+		switch {
+		case path == "":
+			problem = "Please enter a path"
+		case d.restoring && name == "":
+			problem = "Please enter a volume name"
+		}
+		if problem != "" {
+			d.mu.Lock()
+			d.status = problem
+			d.statusErr = true
+			d.mu.Unlock()
+		} else if d.restoring {
+			v.startRestore(docker.RestoreVolumeOptions{
+				Volume:         name,
+				Path:           path,
+				HelperImage:    v.settings.Volumes.HelperImage,
+				Replace:        exists && d.replace.Value,
+				StopContainers: d.stop.Value,
+			})
+		} else {
+			v.startBackup(docker.BackupVolumeOptions{
+				Volume:         d.volume.Name,
+				Path:           path,
+				HelperImage:    v.settings.Volumes.HelperImage,
+				StopContainers: d.stop.Value,
+			})
+		}
+	}
+
+	title := "Backup Volume"
+	startLabel := "Back Up"
+	pathLabel := "Destination"
+	if d.restoring {
+		title = "Restore Volume"
+		startLabel = "Restore"
+		pathLabel = "Backup file (.tar or .tar.gz)"
+	}
+	if running {
+		startLabel = "Running…"
+	}
+
+	// Running containers keep writing while the volume is read or replaced
+	var active int
+	for _, ctr := range target.Containers {
+		if ctr.State == "running" {
+			active++
+		}
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(560), func(gtx layout.Context) layout.Dimensions {
+		children := []layout.FlexChild{
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.H6(v.theme.Material, title)
+				label.Color = v.theme.Colors.Text
+				return label.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+		}
+
+		if d.restoring {
+			children = append(children,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutTextField(gtx, v.theme, &d.name, "Volume", "postgres-data")
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					text := "A new volume with this name will be created."
+					if exists {
+						text = "The backup is extracted into the existing volume."
+					}
+					if name == "" {
+						return layout.Dimensions{}
+					}
+					return layout.Inset{Top: unit.Dp(4)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogText(gtx, v.theme, text)
+					})
+				}),
+				layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			)
+		} else {
+			children = append(children,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					text := "Writes the contents of " + d.volume.Name + " into a gzip-compressed tarball."
+					return layoutDialogText(gtx, v.theme, text)
+				}),
+				layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			)
+		}
+
+		children = append(children,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.path, pathLabel, "/media/usb/postgres-data.tar.gz")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if !d.restoring || !exists {
+					return layout.Dimensions{}
+				}
+				return layoutCheckBox(gtx, v.theme, &d.replace, "Delete the current contents of the volume first")
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if !exists || active == 0 {
+					return layout.Dimensions{}
+				}
+				return layoutCheckBox(gtx, v.theme, &d.stop, "Stop the "+pluralize(active, "running container")+" using the volume and restart them afterwards")
+			}),
+			// Progress
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if !running {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					return layoutTransferProgress(gtx, v.theme, sent, total)
+				})
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, status)
+					label.Color = v.theme.Colors.TextSecondary
+					if statusErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				cancelLabel := "Close"
+				if running {
+					cancelLabel = "Cancel"
+				}
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, cancelLabel, false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.start, startLabel, false)
+					},
+				)
+			}),
+		)
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+// findVolume returns the volume with the given name and whether it exists.
+func findVolume(volumes []docker.Volume, name string) (docker.Volume, bool) {
+	for _, vol := range volumes {
+		if vol.Name == name {
+			return vol, true
+		}
+	}
+	return docker.Volume{}, false
+}
@@ -0,0 +1,274 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// volumeCloneDialog holds the state of the "Clone Volume" and "Rename Volume" dialogs.
+type volumeCloneDialog struct {
+	open     bool
+	renaming bool // Which of the two dialogs is open
+	source   docker.Volume
+	name     widget.Editor
+	driver   widget.Editor
+	labels   widget.Editor
+	start    widget.Clickable
+	cancel   widget.Clickable
+
+	// Updated from background goroutines
+	mu        sync.Mutex
+	running   bool
+	copied    int64
+	status    string
+	statusErr bool
+}
+
+// openCloneDialog shows the clone or rename dialog prefilled with the driver and labels of vol.
+// Volumes mounted by running containers are refused right away, since they could change during the copy.
+func (v *VolumesView) openCloneDialog(vol docker.Volume, rename bool) {
+	var running []docker.Container
+	for _, ctr := range vol.Containers {
+		if ctr.State == "running" {
+			running = append(running, ctr)
+		}
+	}
+	if len(running) > 0 {
+		action := "clone"
+		if rename {
+			action = "rename"
+		}
+		v.setVolumeError("Cannot "+action+" "+vol.Name+" while containers write to it, stop them first", &docker.VolumeInUseError{Err: errors.New("volume is in use"), Containers: running})
+		return
+	}
+
+	d := &v.cloneDialog
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running {
+		return
+	}
+
+	d.open = true
+	d.renaming = rename
+	d.source = vol
+	d.status = ""
+	d.copied = 0
+	d.name.SetText("")
+	if !rename {
+		d.name.SetText(vol.Name + "-copy")
+	}
+	d.driver.SetText(vol.Driver)
+	d.labels.SetText(formatKeyValueLines(vol.Labels))
+}
+
+// formatKeyValueLines formats a map as sorted KEY=VALUE lines for parseKeyValueLines.
+func formatKeyValueLines(values map[string]string) string {
+	lines := make([]string, 0, len(values))
+	for key, value := range values {
+		lines = append(lines, key+"="+value)
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// startClone clones or renames the volume in the background and closes the dialog on success.
+func (v *VolumesView) startClone(opts docker.CloneVolumeOptions, rename bool, users int) {
+	d := &v.cloneDialog
+	d.mu.Lock()
+	d.running = true
+	d.copied = 0
+	d.status = "Copying " + opts.Source + " to " + opts.Target.Name + "…"
+	d.statusErr = false
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		progress := func(n int64) {
+			d.mu.Lock()
+			d.copied = n
+			d.mu.Unlock()
+			v.invalidate()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		var err error
+		if rename {
+			_, err = v.docker.RenameVolume(ctx, opts, progress)
+		} else {
+			_, err = v.docker.CloneVolume(ctx, opts, progress)
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.running = false
+		var inUse *docker.VolumeInUseError
+		switch {
+		case errors.As(err, &inUse):
+			// Show the containers in the banner so they can be stopped
+			d.open = false
+			v.setVolumeError("Cannot copy "+opts.Source, err)
+		case err != nil:
+			d.status = err.Error()
+			d.statusErr = true
+		case rename:
+			d.open = false
+			msg := "Renamed " + opts.Source + " to " + opts.Target.Name
+			if users > 0 {
+				msg += " and recreated " + pluralize(users, "container") + " to use it"
+			}
+			v.setNotice(msg)
+		default:
+			d.open = false
+			v.setNotice("Cloned " + opts.Source + " to " + opts.Target.Name)
+		}
+	}()
+}
+
+// layoutCloneDialog renders the clone or rename dialog overlay when it is open.
+func (v *VolumesView) layoutCloneDialog(gtx layout.Context) layout.Dimensions {
+	d := &v.cloneDialog
+
+	d.mu.Lock()
+	open := d.open
+	running := d.running
+	copied := d.copied
+	status := d.status
+	statusErr := d.statusErr
+	d.mu.Unlock()
+
+	if !open {
+		return layout.Dimensions{}
+	}
+
+	if d.cancel.Clicked(gtx) && !running {
+		d.mu.Lock()
+		d.open = false
+		d.mu.Unlock()
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+	if d.start.Clicked(gtx) && !running {
+		opts := docker.CloneVolumeOptions{
+			Source: d.source.Name,
+			Target: docker.CreateVolumeOptions{
+				Name:   strings.TrimSpace(d.name.Text()),
+				Driver: strings.TrimSpace(d.driver.Text()),
+			},
+			HelperImage: v.settings.Volumes.HelperImage,
+		}
+		var problem string
+		opts.Target.Labels, problem = parseKeyValueLines(d.labels.Text(), "Label")
+		switch {
+		case problem != "":
+		case opts.Target.Name == "":
+			problem = "Please enter a name for the new volume"
+		case opts.Target.Name == opts.Source:
+			problem = "Please enter a different name"
+		}
+		if problem != "" {
+			status = problem
+			statusErr = true
+			d.mu.Lock()
+			d.status = problem
+			d.statusErr = true
+			d.mu.Unlock()
+		} else {
+			v.startClone(opts, d.renaming, len(d.source.Containers))
+			running = true
+		}
+	}
+
+	title := "Clone Volume"
+	startLabel := "Clone"
+	description := "Copies all data of " + d.source.Name + " into a new volume. The original is kept."
+	if d.renaming {
+		title = "Rename Volume"
+		startLabel = "Rename"
+		description = "Docker cannot rename volumes, so " + d.source.Name + " is copied into a new volume and removed afterwards."
+		if n := len(d.source.Containers); n > 0 {
+			description += " The " + pluralize(n, "container") + " using it will be recreated to use the new volume."
+		}
+	}
+	if running {
+		startLabel = "Copying…"
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(520), func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.H6(v.theme.Material, title)
+				label.Color = v.theme.Colors.Text
+				return label.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogText(gtx, v.theme, description)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.name, "New name", "postgres-data")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.driver, "Driver", "local")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.Y = gtx.Dp(unit.Dp(72))
+				return layoutTextArea(gtx, v.theme, &d.labels, "Labels (one KEY=VALUE per line)", "com.docker.compose.project=myapp")
+			}),
+			// Progress
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if !running {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					total := d.source.Size
+					if total < 0 {
+						total = 0
+					}
+					return layoutTransferProgress(gtx, v.theme, copied, total)
+				})
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, status)
+					label.Color = v.theme.Colors.TextSecondary
+					if statusErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, "Cancel", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.start, startLabel, d.renaming)
+					},
+				)
+			}),
+		)
+	})
+}
@@ -0,0 +1,209 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// volumeCreateDialog holds the state of the "Create Volume" dialog.
+type volumeCreateDialog struct {
+	open       bool
+	name       widget.Editor
+	driver     widget.Editor
+	driverOpts widget.Editor
+	labels     widget.Editor
+	create     widget.Clickable
+	cancel     widget.Clickable
+
+	// Updated from background goroutines
+	mu        sync.Mutex
+	creating  bool
+	status    string
+	statusErr bool
+}
+
+// openCreateDialog shows the create dialog with empty fields.
+func (v *VolumesView) openCreateDialog() {
+	d := &v.createDialog
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.open = true
+	d.status = ""
+	d.name.SetText("")
+	d.driver.SetText("")
+	d.driverOpts.SetText("")
+	d.labels.SetText("")
+}
+
+// createOptions reads the volume options from the dialog.
+func (d *volumeCreateDialog) createOptions() (docker.CreateVolumeOptions, string) {
+	opts := docker.CreateVolumeOptions{
+		Name:   strings.TrimSpace(d.name.Text()),
+		Driver: strings.TrimSpace(d.driver.Text()),
+	}
+	var problem string
+	if opts.DriverOpts, problem = parseKeyValueLines(d.driverOpts.Text(), "Driver option"); problem != "" {
+		return opts, problem
+	}
+	if opts.Labels, problem = parseKeyValueLines(d.labels.Text(), "Label"); problem != "" {
+		return opts, problem
+	}
+	return opts, ""
+}
+
+// parseKeyValueLines parses one KEY=VALUE pair per line. Empty lines and
+// comments are skipped; a bare KEY has an empty value.
+func parseKeyValueLines(text, what string) (map[string]string, string) {
+	var values map[string]string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, _ := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, what + " \"" + line + "\" has no name"
+		}
+		if values == nil {
+			values = make(map[string]string)
+		}
+		values[key] = strings.TrimSpace(value)
+	}
+	return values, ""
+}
+
+// startCreate creates the volume in the background and closes the dialog on success.
+func (v *VolumesView) startCreate(opts docker.CreateVolumeOptions) {
+	d := &v.createDialog
+	d.mu.Lock()
+	d.creating = true
+	d.status = "Creating…"
+	d.statusErr = false
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		vol, err := v.docker.CreateVolume(ctx, opts)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.creating = false
+		if err != nil {
+			d.status = "Failed to create volume: " + err.Error()
+			d.statusErr = true
+			return
+		}
+		d.open = false
+		v.setNotice("Created volume " + vol.Name)
+	}()
+}
+
+// layoutCreateDialog renders the create dialog overlay when it is open.
+func (v *VolumesView) layoutCreateDialog(gtx layout.Context) layout.Dimensions {
+	d := &v.createDialog
+
+	d.mu.Lock()
+	open := d.open
+	creating := d.creating
+	status := d.status
+	statusErr := d.statusErr
+	d.mu.Unlock()
+
+	if !open {
+		return layout.Dimensions{}
+	}
+
+	if d.cancel.Clicked(gtx) && !creating {
+		d.mu.Lock()
+		d.open = false
+		d.mu.Unlock()
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+	if d.create.Clicked(gtx) && !creating {
+		if opts, problem := d.createOptions(); problem != "" {
+			status = problem
+			statusErr = true
+			d.mu.Lock()
+			d.status = problem
+			d.statusErr = true
+			d.mu.Unlock()
+		} else {
+			v.startCreate(opts)
+			creating = true
+		}
+	}
+
+	createLabel := "Create"
+	if creating {
+		createLabel = "Creating…"
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(520), func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+			// Title
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				title := material.H6(v.theme.Material, "Create Volume")
+				title.Color = v.theme.Colors.Text
+				return title.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(16)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.name, "Name", "Generated if empty")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutTextField(gtx, v.theme, &d.driver, "Driver", "local")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.Y = gtx.Dp(unit.Dp(72))
+				return layoutTextArea(gtx, v.theme, &d.driverOpts, "Driver options (one KEY=VALUE per line)", "type=tmpfs")
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				gtx.Constraints.Min.Y = gtx.Dp(unit.Dp(72))
+				return layoutTextArea(gtx, v.theme, &d.labels, "Labels (one KEY=VALUE per line)", "com.example.team=backend")
+			}),
+			// Status
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if status == "" {
+					return layout.Dimensions{}
+				}
+				return layout.Inset{Top: unit.Dp(12)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+					label := material.Body2(v.theme.Material, status)
+					label.Color = v.theme.Colors.TextSecondary
+					if statusErr {
+						label.Color = v.theme.Colors.StatusStopped
+					}
+					return label.Layout(gtx)
+				})
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			// Buttons
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, "Cancel", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.create, createLabel, false)
+					},
+				)
+			}),
+		)
+	})
+}
@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// Volume removal actions of the confirmation dialog
+const (
+	volumeActionRemove = "remove"
+	volumeActionPrune  = "prune"
+)
+
+// Volume prune modes, keyed for widget.Enum
+const (
+	volumePruneAnonymous = "anonymous"
+	volumePruneAll       = "all"
+)
+
+var volumePruneOptions = []radioOption{
+	{volumePruneAnonymous, "Anonymous volumes"},
+	{volumePruneAll, "All unused volumes"},
+}
+
+// volumeRemoveDialog holds the state of the remove and prune confirmation dialog.
+type volumeRemoveDialog struct {
+	action    string // Empty when the dialog is closed
+	volume    docker.Volume
+	pruneMode widget.Enum
+	confirm   widget.Clickable
+	cancel    widget.Clickable
+	list      widget.List
+
+	// Prune candidates, loaded in the background
+	mu         sync.Mutex
+	candidates []docker.Volume
+	loadedMode string
+	loading    bool
+	loadErr    string
+}
+
+// openRemoveDialog asks for confirmation before removing a volume.
+func (v *VolumesView) openRemoveDialog(vol docker.Volume) {
+	d := &v.removeDialog
+	d.action = volumeActionRemove
+	d.volume = vol
+}
+
+// openPruneDialog asks for confirmation before pruning volumes.
+func (v *VolumesView) openPruneDialog() {
+	d := &v.removeDialog
+	d.action = volumeActionPrune
+	if d.pruneMode.Value == "" {
+		// Leftovers of removed compose projects are named, so default to all
+		d.pruneMode.Value = volumePruneAll
+	}
+	v.loadPruneCandidates(d.pruneMode.Value)
+}
+
+// loadPruneCandidates lists the volumes a prune in the given mode would remove.
+func (v *VolumesView) loadPruneCandidates(mode string) {
+	d := &v.removeDialog
+	d.mu.Lock()
+	d.loading = true
+	d.loadedMode = mode
+	d.candidates = nil
+	d.loadErr = ""
+	d.mu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		candidates, err := v.docker.VolumePruneCandidates(ctx, mode == volumePruneAll)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.loadedMode != mode {
+			// The mode changed while loading
+			return
+		}
+		d.loading = false
+		if err != nil {
+			d.loadErr = err.Error()
+			return
+		}
+		d.candidates = candidates
+	}()
+}
+
+// runRemoveAction performs the confirmed action in the background.
+func (v *VolumesView) runRemoveAction() {
+	d := &v.removeDialog
+	action := d.action
+	vol := d.volume
+	pruneAll := d.pruneMode.Value == volumePruneAll
+
+	var btns *volumeRowButtons
+	if action == volumeActionRemove {
+		btns = v.getVolumeButtons(vol.Name)
+		btns.processing = true
+	} else {
+		v.pruning = true
+	}
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		switch action {
+		case volumeActionRemove:
+			if err := v.docker.RemoveVolume(ctx, vol.Name); err != nil {
+				v.setVolumeError("Failed to remove "+vol.Name, err)
+			} else {
+				v.setNotice("Removed " + vol.Name)
+			}
+			btns.processing = false
+		case volumeActionPrune:
+			report, err := v.docker.PruneVolumes(ctx, pruneAll)
+			if err != nil {
+				v.setVolumeError("Failed to prune volumes", err)
+			} else {
+				v.setNotice("Removed " + intToStr(report.Deleted) + " volumes, reclaimed " + docker.FormatSize(int64(report.SpaceReclaimed)))
+			}
+			v.pruning = false
+		}
+	}()
+}
+
+// layoutRemoveDialog renders the confirmation dialog overlay when it is open.
+func (v *VolumesView) layoutRemoveDialog(gtx layout.Context) layout.Dimensions {
+	d := &v.removeDialog
+	if d.action == "" {
+		return layout.Dimensions{}
+	}
+
+	if d.cancel.Clicked(gtx) {
+		d.action = ""
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+	if d.action == volumeActionPrune && d.pruneMode.Update(gtx) {
+		v.loadPruneCandidates(d.pruneMode.Value)
+	}
+
+	d.mu.Lock()
+	loading := d.loading
+	candidates := d.candidates
+	loadErr := d.loadErr
+	d.mu.Unlock()
+
+	if d.confirm.Clicked(gtx) && !(d.action == volumeActionPrune && (loading || len(candidates) == 0)) {
+		v.runRemoveAction()
+		d.action = ""
+		return layout.Dimensions{Size: gtx.Constraints.Max}
+	}
+
+	var title, confirmLabel string
+	var body []layout.FlexChild
+	switch d.action {
+	case volumeActionRemove:
+		title = "Remove Volume"
+		confirmLabel = "Remove"
+		body = []layout.FlexChild{
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogText(gtx, v.theme, "Volume "+d.volume.Name+" and all data stored in it will be removed permanently.")
+			}),
+		}
+	case volumeActionPrune:
+		title = "Prune Volumes"
+		confirmLabel = "Prune"
+		body = v.pruneDialogBody(d, loading, candidates, loadErr)
+	}
+
+	return layoutModal(gtx, v.theme, unit.Dp(520), func(gtx layout.Context) layout.Dimensions {
+		children := []layout.FlexChild{
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.H6(v.theme.Material, title)
+				label.Color = v.theme.Colors.Text
+				return label.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Height: unit.Dp(12)}.Layout),
+		}
+		children = append(children, body...)
+		children = append(children,
+			layout.Rigid(layout.Spacer{Height: unit.Dp(24)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutDialogButtons(gtx,
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.cancel, "Cancel", false)
+					},
+					func(gtx layout.Context) layout.Dimensions {
+						return layoutDialogButton(gtx, v.theme, &d.confirm, confirmLabel, true)
+					},
+				)
+			}),
+		)
+		return layout.Flex{Axis: layout.Vertical}.Layout(gtx, children...)
+	})
+}
+
+func (v *VolumesView) pruneDialogBody(d *volumeRemoveDialog, loading bool, candidates []docker.Volume, loadErr string) []layout.FlexChild {
+	return []layout.FlexChild{
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			return layoutRadioRow(gtx, v.theme, &d.pruneMode, volumePruneOptions)
+		}),
+		layout.Rigid(layout.Spacer{Height: unit.Dp(8)}.Layout),
+		layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+			switch {
+			case loading:
+				return layoutDialogText(gtx, v.theme, "Looking for volumes to remove…")
+			case loadErr != "":
+				return layoutDialogText(gtx, v.theme, "Failed to list volumes: "+loadErr)
+			case len(candidates) == 0:
+				return layoutDialogText(gtx, v.theme, "Nothing to prune.")
+			}
+
+			lines := make([]string, len(candidates))
+			for i, vol := range candidates {
+				lines[i] = vol.Name
+				if project := vol.Project(); project != "" {
+					lines[i] += "  (" + project + ")"
+				}
+			}
+			return layout.Flex{Axis: layout.Vertical}.Layout(gtx,
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutDialogText(gtx, v.theme, intToStr(len(candidates))+" volumes no container uses will be removed with all their data:")
+				}),
+				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+					return layoutItemList(gtx, v.theme, &d.list, lines)
+				}),
+			)
+		}),
+	}
+}
@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"gioui.org/layout"
+	"gioui.org/unit"
+	"gioui.org/widget/material"
+
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+// The daemon walks every volume to compute disk usage, so it is refreshed
+// far less often than the volume list itself.
+const volumeUsageTTL = 30 * time.Second
+
+// Volume sort orders, keyed for widget.Enum
+const (
+	volumeSortName = "name"
+	volumeSortSize = "size"
+)
+
+var volumeSortOptions = []radioOption{
+	{key: volumeSortName, label: "Name"},
+	{key: volumeSortSize, label: "Size"},
+}
+
+// refreshUsage loads the volume disk usage in the background once the last
+// result is older than volumeUsageTTL or was invalidated by an action.
+func (v *VolumesView) refreshUsage() {
+	v.usageMu.Lock()
+	if v.usageLoading || time.Since(v.usageAt) < volumeUsageTTL {
+		v.usageMu.Unlock()
+		return
+	}
+	v.usageLoading = true
+	v.usageMu.Unlock()
+
+	go func() {
+		defer v.invalidate()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		usage, err := v.docker.VolumeDiskUsage(ctx)
+
+		v.usageMu.Lock()
+		defer v.usageMu.Unlock()
+		v.usageLoading = false
+		v.usageAt = time.Now()
+		if err != nil {
+			// Keep the previous sizes; the next refresh tries again
+			return
+		}
+		v.usage = usage
+	}()
+}
+
+// invalidateUsage makes the next frame reload the disk usage.
+func (v *VolumesView) invalidateUsage() {
+	v.usageMu.Lock()
+	v.usageAt = time.Time{}
+	v.usageMu.Unlock()
+}
+
+// shownVolumes applies the disk usage, the usage filter and the sort order to volumes.
+func (v *VolumesView) shownVolumes(volumes []docker.Volume) []docker.Volume {
+	v.usageMu.Lock()
+	usage := v.usage
+	v.usageMu.Unlock()
+
+	volumes = docker.ApplyVolumeUsage(volumes, usage)
+	if v.filter.Value != usageFilterAll {
+		filtered := volumes[:0]
+		for _, vol := range volumes {
+			if vol.InUse() == (v.filter.Value == usageFilterInUse) {
+				filtered = append(filtered, vol)
+			}
+		}
+		volumes = filtered
+	}
+
+	if v.sort.Value == volumeSortSize {
+		sort.SliceStable(volumes, func(i, j int) bool {
+			if volumes[i].Size != volumes[j].Size {
+				return volumes[i].Size > volumes[j].Size
+			}
+			return volumes[i].Name < volumes[j].Name
+		})
+	} else {
+		sort.SliceStable(volumes, func(i, j int) bool {
+			return volumes[i].Name < volumes[j].Name
+		})
+	}
+	return volumes
+}
+
+// layoutFilter renders the usage filter, the sort order and the space unused volumes take up.
+func (v *VolumesView) layoutFilter(gtx layout.Context, volumes []docker.Volume) layout.Dimensions {
+	v.usageMu.Lock()
+	usage := v.usage
+	v.usageMu.Unlock()
+
+	var unused int
+	var reclaimable int64
+	for _, vol := range volumes {
+		if vol.InUse() {
+			continue
+		}
+		unused++
+		if u, ok := usage[vol.Name]; ok && u.Size > 0 {
+			reclaimable += u.Size
+		}
+	}
+
+	return layout.Inset{
+		Bottom: unit.Dp(8),
+		Left:   unit.Dp(16),
+		Right:  unit.Dp(16),
+	}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return layout.Flex{Axis: layout.Horizontal, Alignment: layout.Middle}.Layout(gtx,
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutRadioRow(gtx, v.theme, &v.filter, usageFilters)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(24)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				label := material.Caption(v.theme.Material, "Sort by")
+				label.Color = v.theme.Colors.TextMuted
+				return label.Layout(gtx)
+			}),
+			layout.Rigid(layout.Spacer{Width: unit.Dp(8)}.Layout),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				return layoutRadioRow(gtx, v.theme, &v.sort, volumeSortOptions)
+			}),
+			layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+				return layout.Dimensions{}
+			}),
+			layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+				if unused == 0 {
+					return layout.Dimensions{}
+				}
+				text := intToStr(unused) + " unused"
+				if usage != nil {
+					text += ", " + docker.FormatSize(reclaimable) + " reclaimable"
+				}
+				label := material.Caption(v.theme.Material, text)
+				label.Color = v.theme.Colors.TextMuted
+				return label.Layout(gtx)
+			}),
+		)
+	})
+}
+
+// volumeUsageText describes the size and reference count of a volume.
+func volumeUsageText(vol docker.Volume) string {
+	var text string
+	switch {
+	case vol.Size >= 0:
+		text = docker.FormatSize(vol.Size)
+	case vol.RefCount >= 0:
+		text = "size unknown"
+	default:
+		return ""
+	}
+	if vol.RefCount >= 0 {
+		refs := " references"
+		if vol.RefCount == 1 {
+			refs = " reference"
+		}
+		text += " • " + intToStr(int(vol.RefCount)) + refs
+	}
+	return text
+}
@@ -0,0 +1,278 @@
+// Package watch evaluates log pattern rules against the logs of running containers.
+package watch
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tsukinoko-kun/harbor/internal/config"
+	"github.com/tsukinoko-kun/harbor/internal/docker"
+)
+
+const (
+	// scanInterval is how often the list of running containers is refreshed.
+	scanInterval = 2 * time.Second
+
+	// notifyCooldown limits how often the same rule reports matches for the same container.
+	notifyCooldown = 10 * time.Second
+)
+
+// Match describes a log line that matched a watch rule.
+type Match struct {
+	Rule      config.WatchRule
+	Container docker.Container
+	Line      docker.LogLine
+}
+
+// ContainerAlerts summarizes the matches for a single container.
+type ContainerAlerts struct {
+	Count       int  // Total number of matching lines
+	Highlighted bool // A rule with the highlight action matched
+}
+
+// compiledRule is a watch rule with its compiled pattern.
+type compiledRule struct {
+	rule config.WatchRule
+	re   *regexp.Regexp
+}
+
+// follower is an active log stream of a single container.
+type follower struct {
+	cancel context.CancelFunc
+}
+
+// Watcher follows the logs of all running containers in the background and
+// evaluates the configured watch rules against each line.
+type Watcher struct {
+	docker  *docker.Client
+	onMatch func(Match)
+
+	mu         sync.Mutex
+	rules      []compiledRule
+	followed   map[string]*follower // Container ID -> active log stream
+	resumeAt   map[string]time.Time // Container ID -> end of the last stream
+	ruleCounts map[config.WatchRule]int
+	alerts     map[string]ContainerAlerts // Container ID -> alerts
+	lastNotify map[string]time.Time       // Rule and container -> last reported match
+}
+
+// New creates a watcher. onMatch is called for matches of rules with the
+// notify or highlight action; it is called from a background goroutine.
+func New(dockerClient *docker.Client, rules []config.WatchRule, onMatch func(Match)) *Watcher {
+	w := &Watcher{
+		docker:     dockerClient,
+		onMatch:    onMatch,
+		followed:   make(map[string]*follower),
+		resumeAt:   make(map[string]time.Time),
+		ruleCounts: make(map[config.WatchRule]int),
+		alerts:     make(map[string]ContainerAlerts),
+		lastNotify: make(map[string]time.Time),
+	}
+	w.SetRules(rules)
+	return w
+}
+
+// SetRules replaces the rules being evaluated. Rules with invalid patterns are skipped.
+func (w *Watcher) SetRules(rules []config.WatchRule) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			continue
+		}
+		compiled = append(compiled, compiledRule{rule: rule, re: re})
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.rules = compiled
+}
+
+// RuleCount returns how many lines matched the rule since Harbor started.
+func (w *Watcher) RuleCount(rule config.WatchRule) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ruleCounts[rule]
+}
+
+// Alerts returns the matches recorded for a container.
+func (w *Watcher) Alerts(containerID string) ContainerAlerts {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.alerts[containerID]
+}
+
+// ClearAlerts resets the matches recorded for a container.
+func (w *Watcher) ClearAlerts(containerID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.alerts, containerID)
+}
+
+// Run scans for running containers and follows their logs until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(scanInterval)
+	defer ticker.Stop()
+
+	for {
+		w.scan(ctx)
+
+		select {
+		case <-ctx.Done():
+			w.stopAll()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// scan starts following newly running containers.
+// Without any rules no logs are followed at all.
+func (w *Watcher) scan(ctx context.Context) {
+	w.mu.Lock()
+	hasRules := len(w.rules) > 0
+	w.mu.Unlock()
+
+	if !hasRules {
+		w.stopAll()
+		return
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	containers, err := w.docker.ListContainers(listCtx)
+	cancel()
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, ctr := range containers {
+		if ctr.State != "running" {
+			continue
+		}
+		if _, ok := w.followed[ctr.ID]; ok {
+			continue
+		}
+
+		since, ok := w.resumeAt[ctr.ID]
+		if !ok {
+			// Only evaluate lines logged after the container was first seen
+			since = time.Now()
+		}
+
+		followCtx, cancel := context.WithCancel(ctx)
+		f := &follower{cancel: cancel}
+		w.followed[ctr.ID] = f
+		go w.follow(followCtx, f, ctr, since)
+	}
+}
+
+// follow streams the logs of one container until it stops.
+func (w *Watcher) follow(ctx context.Context, f *follower, ctr docker.Container, since time.Time) {
+	defer func() {
+		w.mu.Lock()
+		if w.followed[ctr.ID] == f {
+			delete(w.followed, ctr.ID)
+		}
+		w.resumeAt[ctr.ID] = time.Now()
+		w.mu.Unlock()
+		f.cancel()
+	}()
+
+	info, err := w.docker.InspectContainer(ctx, ctr.ID)
+	if err != nil {
+		return
+	}
+
+	_ = w.docker.StreamLogs(ctx, ctr.ID, docker.LogOptions{
+		Follow: true,
+		Since:  since,
+		Tty:    info.Tty,
+	}, func(line docker.LogLine) {
+		w.evaluate(ctr, line)
+	})
+}
+
+// evaluate checks a log line against all rules in scope of the container.
+func (w *Watcher) evaluate(ctr docker.Container, line docker.LogLine) {
+	var matches []Match
+
+	w.mu.Lock()
+	for _, cr := range w.rules {
+		if !inScope(cr.rule, ctr) || !cr.re.MatchString(line.Message) {
+			continue
+		}
+
+		w.ruleCounts[cr.rule]++
+		alerts := w.alerts[ctr.ID]
+		alerts.Count++
+		if cr.rule.Action == config.WatchActionHighlight {
+			alerts.Highlighted = true
+		}
+		w.alerts[ctr.ID] = alerts
+
+		if cr.rule.Action == config.WatchActionCount {
+			continue
+		}
+
+		// Avoid flooding the user when a rule matches many lines in a row
+		key := cr.rule.Pattern + "\x00" + ctr.ID
+		if time.Since(w.lastNotify[key]) < notifyCooldown {
+			continue
+		}
+		w.lastNotify[key] = time.Now()
+		matches = append(matches, Match{Rule: cr.rule, Container: ctr, Line: line})
+	}
+	w.mu.Unlock()
+
+	if w.onMatch != nil {
+		for _, m := range matches {
+			w.onMatch(m)
+		}
+	}
+}
+
+// stopAll stops following all containers.
+func (w *Watcher) stopAll() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for id, f := range w.followed {
+		f.cancel()
+		delete(w.followed, id)
+	}
+}
+
+// inScope reports whether a rule applies to a container.
+func inScope(rule config.WatchRule, ctr docker.Container) bool {
+	if rule.Target == "" {
+		return true
+	}
+
+	switch rule.Scope {
+	case config.WatchScopeContainer:
+		return ctr.Name == rule.Target
+	case config.WatchScopeProject:
+		return ctr.Project == rule.Target
+	case config.WatchScopeImage:
+		return ctr.Image == rule.Target || imageRepository(ctr.Image) == rule.Target
+	default:
+		return true
+	}
+}
+
+// imageRepository strips the tag or digest from an image reference.
+func imageRepository(ref string) string {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	// A colon after the last slash separates the tag; earlier colons belong to a registry port
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	return ref
+}